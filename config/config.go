@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -9,12 +10,17 @@ import (
 type (
 	// Config -.
 	Config struct {
-		App      `yaml:"app"`
-		HTTP     `yaml:"http"`
-		Log      `yaml:"logger"`
-		Redis    `yaml:"redis"`
-		Postgres `yaml:"postgres"`
-		EdenAi   `yaml:"edenAi"`
+		App              `yaml:"app"`
+		HTTP             `yaml:"http"`
+		Log              `yaml:"logger"`
+		Redis            `yaml:"redis"`
+		Postgres         `yaml:"postgres"`
+		EdenAi           `yaml:"edenAi"`
+		ContentFilter    `yaml:"contentFilter"`
+		Group            `yaml:"group"`
+		Profile          `yaml:"profile"`
+		Presence         `yaml:"presence"`
+		ContactDiscovery `yaml:"contactDiscovery"`
 	}
 
 	// App -.
@@ -25,7 +31,8 @@ type (
 
 	// HTTP -.
 	HTTP struct {
-		Port string `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+		Port           string   `env-required:"true" yaml:"port" env:"HTTP_PORT"`
+		TrustedProxies []string `yaml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES" env-separator:","`
 	}
 
 	// Log -.
@@ -39,7 +46,7 @@ type (
 	}
 
 	Postgres struct {
-		Host string `env-required:"true"  env:"POSTGRES_HOST"`
+		Host     string `env-required:"true"  env:"POSTGRES_HOST"`
 		Username string `env-required:"true" yaml:"username" env:"POSTGRES_USERNAME"`
 		Password string `env-required:"true" yaml:"password" env:"POSTGRES_PASSWORD"`
 	}
@@ -47,13 +54,46 @@ type (
 	EdenAi struct {
 		ApiKey string `env-required:"true" env:"EDENAI_APIKEY" env-default:"EDENAI_APIKEY"`
 	}
+
+	// ContentFilter file config filter pesan, file dicek ulang setiap ReloadInterval (hot reload)
+	ContentFilter struct {
+		Path           string        `yaml:"path" env:"CONTENT_FILTER_PATH" env-default:"./config/content_filter.yml"`
+		ReloadInterval time.Duration `yaml:"reload_interval" env:"CONTENT_FILTER_RELOAD_INTERVAL" env-default:"30s"`
+	}
+
+	// Group batas jumlah member group, group dengan member >= LargeGroupThreshold masuk mode large group
+	Group struct {
+		MaxMembers          int `yaml:"max_members" env:"GROUP_MAX_MEMBERS" env-default:"1000"`
+		LargeGroupThreshold int `yaml:"large_group_threshold" env:"GROUP_LARGE_THRESHOLD" env-default:"200"`
+	}
+
+	// Profile batas ukuran avatar user dalam byte
+	Profile struct {
+		AvatarMaxSize int64 `yaml:"avatar_max_size" env:"PROFILE_AVATAR_MAX_SIZE" env-default:"2097152"`
+	}
+
+	// Presence user yang tidak aktif (kirim pesan, typing, membaca pesan) selama IdleTimeout otomatis away, 0 untuk menonaktifkan
+	Presence struct {
+		IdleTimeout time.Duration `yaml:"idle_timeout" env:"PRESENCE_IDLE_TIMEOUT" env-default:"5m"`
+	}
+
+	// ContactDiscovery batas pencarian user dari hash email address book agar tidak bisa dipakai untuk enumerasi user.
+	// Salt dibagikan ke client, Secret hanya di server untuk hmac hash email yang disimpan di database
+	ContactDiscovery struct {
+		MaxBatchSize         int           `yaml:"max_batch_size" env:"CONTACT_DISCOVERY_MAX_BATCH_SIZE" env-default:"500"`
+		MaxHashesPerWindow   int64         `yaml:"max_hashes_per_window" env:"CONTACT_DISCOVERY_MAX_HASHES_PER_WINDOW" env-default:"2000"`
+		MaxHashesPerIpWindow int64         `yaml:"max_hashes_per_ip_window" env:"CONTACT_DISCOVERY_MAX_HASHES_PER_IP_WINDOW" env-default:"5000"`
+		Window               time.Duration `yaml:"window" env:"CONTACT_DISCOVERY_WINDOW" env-default:"24h"`
+		Salt                 string        `yaml:"salt" env:"CONTACT_DISCOVERY_SALT" env-default:"chat-be-contact-discovery"`
+		Secret               string        `env-required:"true" env:"CONTACT_DISCOVERY_SECRET"`
+		IndexInterval        time.Duration `yaml:"index_interval" env:"CONTACT_DISCOVERY_INDEX_INTERVAL" env-default:"1m"`
+	}
 )
 
 // NewConfig returns app config.
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 
-
 	err := cleanenv.ReadConfig("./.env", cfg)
 	if err != nil {
 		return nil, err
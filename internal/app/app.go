@@ -14,6 +14,9 @@ import (
 	"github.com/lintangbs/chat-be/internal/entity"
 	"github.com/lintangbs/chat-be/internal/usecase/redisRepo"
 	"github.com/lintangbs/chat-be/internal/usecase/webapi"
+	"github.com/lintangbs/chat-be/internal/util/contentfilter"
+	"github.com/lintangbs/chat-be/internal/util/emailhash"
+	"github.com/lintangbs/chat-be/internal/util/gopool"
 	"github.com/lintangbs/chat-be/internal/util/jwt"
 	"github.com/lintangbs/chat-be/internal/util/sonyflake"
 	"github.com/lintangbs/chat-be/pkg/redispkg"
@@ -51,6 +54,12 @@ func Run(cfg *config.Config) {
 		l.Fatal(fmt.Errorf("app - Run - jwtTokenMaker - jwt.NewJWTMaker: %w", err))
 	}
 
+	// hash email contact discovery
+	emailHasher, err := emailhash.NewHasher(cfg.ContactDiscovery.Salt, cfg.ContactDiscovery.Secret)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - emailhash.NewHasher: %w", err))
+	}
+
 	authUseCase := usecase.NewAuthUseCase(
 		repo.NewUserRepo(gorm.Pool),
 		jwtTokenMaker,
@@ -58,8 +67,22 @@ func Run(cfg *config.Config) {
 		redisRepo.NewOtp(redis),
 		redisRepo.NewPubSubRedis(redis),
 		redisRepo.NewUserRedisrepo(redis),
+		emailHasher,
 	)
 
+	contentFilter, err := contentfilter.NewPipeline(cfg.ContentFilter.Path)
+	if err != nil {
+		l.Fatal(fmt.Errorf("app - Run - contentfilter.NewPipeline: %w", err))
+	}
+	// hot reload file config content filter
+	go contentFilter.Watch(cfg.ContentFilter.ReloadInterval)
+
+	idGen := sonyflake.NewSonyFlake()
+	groupLimits := entity.GroupLimits{
+		MaxMembers:          cfg.Group.MaxMembers,
+		LargeGroupThreshold: cfg.Group.LargeGroupThreshold,
+	}
+
 	chat := usecase.NewChat(
 		redisRepo.NewPubSubRedis(redis),
 		edenAi,
@@ -67,12 +90,27 @@ func Run(cfg *config.Config) {
 		redis,
 		redisRepo.NewUserRedisrepo(redis),
 		repo.NewPrivateChatRepo(gorm.Pool),
-		sonyflake.NewSonyFlake(),
+		idGen,
 		repo.NewGroupRepo(gorm.Pool),
 		repo.NewGroupChatRepo(gorm.Pool),
+		redisRepo.NewDraftRedisRepo(redis),
+		repo.NewReportRepo(gorm.Pool),
+		contentFilter,
+		repo.NewChannelRepo(gorm.Pool),
+		redisRepo.NewChannelRedisRepo(redis),
+		redisRepo.NewGroupRedisRepo(redis),
+		repo.NewConversationSettingRepo(gorm.Pool),
+		repo.NewBlockRepo(gorm.Pool),
+		repo.NewUserProfileRepo(gorm.Pool),
+		groupLimits,
+		cfg.Presence.IdleTimeout,
 	)
 
 	go chat.Run()
+	go chat.RunStatusExpiry()
+	go chat.RunIdleDetection()
+	go chat.RunChannelDelivery()
+	go chat.RunChannelServerHeartbeat()
 
 	entity.ChatServerNameGlobal = &entity.ServerName{
 		ChatServerName: "chat-server" + uuid2.New().String(),
@@ -88,27 +126,103 @@ func Run(cfg *config.Config) {
 
 	contactUseCase := usecase.NewContactUseCase(
 		repo.NewUserRepo(gorm.Pool),
+		repo.NewFriendRequestRepo(gorm.Pool),
+		repo.NewBlockRepo(gorm.Pool),
+		redisRepo.NewPubSubRedis(redis),
+		redisRepo.NewUserRedisrepo(redis),
+		repo.NewUserProfileRepo(gorm.Pool),
+		redisRepo.NewContactDiscoveryRedisRepo(redis),
+		emailHasher,
+		entity.ContactDiscoveryLimits{
+			MaxBatchSize:         cfg.ContactDiscovery.MaxBatchSize,
+			MaxHashesPerWindow:   cfg.ContactDiscovery.MaxHashesPerWindow,
+			MaxHashesPerIpWindow: cfg.ContactDiscovery.MaxHashesPerIpWindow,
+			Window:               cfg.ContactDiscovery.Window,
+		},
 	)
+	// hash email user baru / lama yang belum dihitung dengan salt & secret sekarang
+	go contactUseCase.RunEmailHashIndexer(cfg.ContactDiscovery.IndexInterval)
 
 	messageUseCase := usecase.NewMessageuseCase(
 		repo.NewPrivateChatRepo(gorm.Pool),
 		repo.NewUserRepo(gorm.Pool),
 		repo.NewGroupChatRepo(gorm.Pool),
 		repo.NewGroupRepo(gorm.Pool),
+		redisRepo.NewExportRedisRepo(redis),
+		redisRepo.NewDraftRedisRepo(redis),
+		gopool.NewPool(4, 16, 1),
+		chat,
 	)
 
 	//groupUseCase
 	groupUseCase := usecase.NewGroupUseCase(
 		repo.NewGroupRepo(gorm.Pool),
 		repo.NewUserRepo(gorm.Pool),
+		repo.NewGroupChatRepo(gorm.Pool),
+		repo.NewGroupInviteRepo(gorm.Pool),
+		repo.NewGroupJoinRequestRepo(gorm.Pool),
+		redisRepo.NewPubSubRedis(redis),
+		redisRepo.NewUserRedisrepo(redis),
+		idGen,
+		chat,
+		redisRepo.NewGroupRedisRepo(redis),
+		repo.NewBlockRepo(gorm.Pool),
+		repo.NewUserProfileRepo(gorm.Pool),
+		groupLimits,
+	)
+
+	moderationUseCase := usecase.NewModerationUseCase(
+		repo.NewReportRepo(gorm.Pool),
+		repo.NewUserRepo(gorm.Pool),
+		repo.NewPrivateChatRepo(gorm.Pool),
+		repo.NewGroupChatRepo(gorm.Pool),
+		repo.NewGroupRepo(gorm.Pool),
+		repo.NewSessionRepo(gorm.Pool),
+		redisRepo.NewPubSubRedis(redis),
+		redisRepo.NewUserRedisrepo(redis),
+	)
+
+	channelUseCase := usecase.NewChannelUseCase(
+		repo.NewChannelRepo(gorm.Pool),
+		repo.NewUserRepo(gorm.Pool),
+		redisRepo.NewPubSubRedis(redis),
+		redisRepo.NewUserRedisrepo(redis),
+	)
+
+	conversationSettingUseCase := usecase.NewConversationSettingUseCase(
+		repo.NewConversationSettingRepo(gorm.Pool),
+		repo.NewUserRepo(gorm.Pool),
+		repo.NewGroupRepo(gorm.Pool),
+	)
+
+	blockUseCase := usecase.NewBlockUseCase(
+		repo.NewBlockRepo(gorm.Pool),
+		repo.NewUserRepo(gorm.Pool),
+		redisRepo.NewPubSubRedis(redis),
+		redisRepo.NewUserRedisrepo(redis),
+	)
+
+	userProfileUseCase := usecase.NewUserProfileUseCase(
+		repo.NewUserProfileRepo(gorm.Pool),
+		repo.NewUserRepo(gorm.Pool),
+		repo.NewBlockRepo(gorm.Pool),
+		redisRepo.NewPubSubRedis(redis),
+		redisRepo.NewUserRedisrepo(redis),
+		chat,
+		entity.ProfileLimits{AvatarMaxSize: cfg.Profile.AvatarMaxSize},
 	)
 
 	// HTTP Server
 	handler := gin.New()
+	// ip client (rate limit per ip) hanya diambil dari header X-Forwarded-For jika request dari proxy yang dipercaya
+	if err = handler.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		l.Fatal(fmt.Errorf("app - Run - handler.SetTrustedProxies: %w", err))
+	}
 
 	handler.Use(cors.Default())
 
-	v1.NewRouter(handler, l, authUseCase, webSocketUseCase, contactUseCase, jwtTokenMaker, messageUseCase, groupUseCase)
+	v1.NewRouter(handler, l, authUseCase, webSocketUseCase, contactUseCase, jwtTokenMaker, messageUseCase, groupUseCase, moderationUseCase, channelUseCase,
+		conversationSettingUseCase, blockUseCase, userProfileUseCase)
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
 	// start subscriber channel chat-server-serverName
@@ -146,6 +146,11 @@ func (r *authRoutes) loginUser(c *gin.Context) {
 			return
 		}
 
+		if unwrapedErr == usecase.UserSuspendedErr {
+			ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+			return
+		}
+
 		if errM == gorm.ErrRecordNotFound {
 			ErrorResponse(c, http.StatusBadRequest, "User not found: "+errM.Error())
 			return
@@ -242,11 +247,11 @@ func (r *authRoutes) renewAccessToken(c *gin.Context) {
 }
 
 type deleteRefreshTokenRequest struct {
-	refreshToken string `json:"refresh_token" binding:"required"`
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 type deleteRefreshTokenResponse struct {
-	responseMessage string `json:"response_message"`
+	ResponseMessage string `json:"response_message"`
 }
 
 // @Summary     delete refresh token & fanout offline status ke semua kontak milik user
@@ -301,7 +306,7 @@ func (r *authRoutes) deleteRefreshToken(c *gin.Context) {
 	}
 
 	resp := deleteRefreshTokenResponse{
-		responseMessage: "refresh token successfully deleted!",
+		ResponseMessage: "refresh token successfully deleted!",
 	}
 	c.JSON(http.StatusOK, resp)
 }
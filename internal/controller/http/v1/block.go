@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"github.com/lintangbs/chat-be/pkg/logger"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type blockRoutes struct {
+	b   usecase.Block
+	l   logger.Interface
+	jwt jwt.JwtTokenMaker
+}
+
+func newBlockRoutes(handler *gin.RouterGroup, b usecase.Block, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
+	r := &blockRoutes{b, l, jwt}
+
+	h := handler.Group("/blocks").Use(api.AuthMiddleware(r.jwt, suspension))
+	{
+		h.GET("", r.getBlockedUsers)
+		h.PUT("/block", r.block)
+		h.PUT("/unblock", r.unblock)
+	}
+}
+
+type blockRequest struct {
+	Username string `json:"username"`
+}
+
+type blockedUserResponse struct {
+	UserId    uuid.UUID  `json:"user_id"`
+	Username  string     `json:"username"`
+	BlockedAt *time.Time `json:"blocked_at,omitempty"`
+}
+
+type blockedUsersResponse struct {
+	BlockedUsers []blockedUserResponse `json:"blocked_users"`
+}
+
+type blockMessageResponse struct {
+	ResponseMessage string `json:"message"`
+}
+
+// blockError mapping error block ke http status, return true jika error sudah di handle
+func (r *blockRoutes) blockError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	switch {
+	case unwrapedErr == usecase.CannotBlockYourselfErr:
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+	case errRepo == gorm.ErrRecordNotFound:
+		ErrorResponse(c, http.StatusBadRequest, "User not found: "+errRepo.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary     get blocked users
+// @Description     get all users blocked by the logged in user
+// @ID          getBlockedUsers
+// @Tags  	    block
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} blockedUsersResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/blocks [get]
+// Author: https://github.com/lintang-b-s
+func (r *blockRoutes) getBlockedUsers(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	blockedUsers, err := r.b.GetBlockedUsers(c.Request.Context(), authPayload.Username)
+	if err != nil {
+		if r.blockError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getBlockedUsers")
+		ErrorResponse(c, http.StatusInternalServerError, "getBlockedUsers service problems: "+err.Error())
+		return
+	}
+
+	res := blockedUsersResponse{BlockedUsers: []blockedUserResponse{}}
+	for _, blocked := range blockedUsers {
+		blockedAt := blocked.BlockedAt
+		res.BlockedUsers = append(res.BlockedUsers, blockedUserResponse{
+			UserId:    blocked.UserId,
+			Username:  blocked.Username,
+			BlockedAt: &blockedAt,
+		})
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary     block user
+// @Description     block a user. The blocked user can not send private messages or friend requests,
+// @Description     can not add the logged in user to groups and can not see the logged in user's online status.
+// @Description     Group messages in shared groups are still delivered with sender_blocked=true so the client can hide them
+// @ID          blockUser
+// @Tags  	    block
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body blockRequest true "user to block"
+// @Success     200 {object} blockedUserResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/blocks/block [put]
+// Author: https://github.com/lintang-b-s
+func (r *blockRoutes) block(c *gin.Context) {
+	var request blockRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - block")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	blocked, err := r.b.Block(c.Request.Context(), entity.BlockReqUc{
+		UserName:        authPayload.Username,
+		BlockedUsername: request.Username,
+	})
+	if err != nil {
+		if r.blockError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- block")
+		ErrorResponse(c, http.StatusInternalServerError, "block service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, blockedUserResponse{
+		UserId:   blocked.UserId,
+		Username: blocked.Username,
+	})
+}
+
+// @Summary     unblock user
+// @Description     unblock a user blocked by the logged in user
+// @ID          unblockUser
+// @Tags  	    block
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body blockRequest true "user to unblock"
+// @Success     200 {object} blockMessageResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/blocks/unblock [put]
+// Author: https://github.com/lintang-b-s
+func (r *blockRoutes) unblock(c *gin.Context) {
+	var request blockRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - unblock")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.b.Unblock(c.Request.Context(), entity.BlockReqUc{
+		UserName:        authPayload.Username,
+		BlockedUsername: request.Username,
+	})
+	if err != nil {
+		if r.blockError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- unblock")
+		ErrorResponse(c, http.StatusInternalServerError, "unblock service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, blockMessageResponse{ResponseMessage: request.Username + " unblocked"})
+}
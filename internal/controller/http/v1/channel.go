@@ -0,0 +1,379 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"github.com/lintangbs/chat-be/pkg/logger"
+	"gorm.io/gorm"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type channelRoutes struct {
+	ch  usecase.Channel
+	l   logger.Interface
+	jwt jwt.JwtTokenMaker
+}
+
+func newChannelRoutes(handler *gin.RouterGroup, ch usecase.Channel, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
+	r := &channelRoutes{ch, l, jwt}
+
+	h := handler.Group("/channels").Use(api.AuthMiddleware(r.jwt, suspension))
+	{
+		h.POST("", r.createChannel)
+		h.GET("", r.getUserChannels)
+		h.PUT("/subscribe", r.subscribe)
+		h.PUT("/unsubscribe", r.unsubscribe)
+		h.PUT("/promote", r.promoteAdmin)
+		h.PUT("/demote", r.demoteAdmin)
+		h.GET("/messages", r.getMessages)
+	}
+}
+
+type createChannelRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+type channelResponse struct {
+	Id              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	OwnerId         uuid.UUID `json:"owner_id"`
+	SubscriberCount int64     `json:"subscriber_count"`
+	Role            string    `json:"role,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func newChannelResponse(ch entity.Channel) channelResponse {
+	return channelResponse{
+		Id:              ch.Id,
+		Name:            ch.Name,
+		Description:     ch.Description,
+		OwnerId:         ch.OwnerId,
+		SubscriberCount: ch.SubscriberCount,
+		Role:            string(ch.Role),
+		CreatedAt:       ch.CreatedAt,
+		UpdatedAt:       ch.UpdatedAt,
+	}
+}
+
+type channelMessageResponse struct {
+	ResponseMessage string `json:"response_message"`
+}
+
+// channelError mapping error channel ke http status, return true jika error sudah di handle
+func (r *channelRoutes) channelError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	switch {
+	case unwrapedErr == usecase.ChannelPermissionDeniedErr:
+		ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+	case unwrapedErr == usecase.InvalidChannelNameErr || unwrapedErr == usecase.InvalidChannelRoleErr ||
+		unwrapedErr == usecase.OwnerCannotUnsubscribeErr || unwrapedErr == usecase.CannotChangeOwnChannelRole:
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+	case errRepo == repo.NotChannelMemberErr || errRepo == gorm.ErrRecordNotFound:
+		ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+	case errRepo == repo.ChannelAlreadySubscribedErr:
+		ErrorResponse(c, http.StatusConflict, errRepo.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary     create channel
+// @Description     create a broadcast channel, the creator becomes the channel owner. Only the owner & admins can post to the channel (websocket message type channel_post)
+// @ID          createChannel
+// @Tags  	    channel
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body createChannelRequest true "new channel"
+// @Success     201 {object} channelResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/channels [post]
+// Author: https://github.com/lintang-b-s
+func (r *channelRoutes) createChannel(c *gin.Context) {
+	var request createChannelRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - createChannel")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	channel, err := r.ch.CreateChannel(
+		c.Request.Context(),
+		entity.CreateChannelReqUc{
+			Name:        request.Name,
+			Description: request.Description,
+			UserName:    authPayload.Username,
+		},
+	)
+	if err != nil {
+		if r.channelError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- createChannel")
+		ErrorResponse(c, http.StatusInternalServerError, "createChannel service problems: "+err.Error())
+		return
+	}
+	channel.Role = entity.ChannelRoleOwner
+	c.JSON(http.StatusCreated, newChannelResponse(channel))
+}
+
+type userChannelsResponse struct {
+	Channels []channelResponse `json:"channels"`
+}
+
+// @Summary     get user channels
+// @Description     get all channels subscribed by the logged in user
+// @ID          getUserChannels
+// @Tags  	    channel
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} userChannelsResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/channels [get]
+// Author: https://github.com/lintang-b-s
+func (r *channelRoutes) getUserChannels(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	channels, err := r.ch.GetUserChannels(c.Request.Context(), authPayload.Username)
+	if err != nil {
+		if r.channelError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getUserChannels")
+		ErrorResponse(c, http.StatusInternalServerError, "getUserChannels service problems: "+err.Error())
+		return
+	}
+
+	res := userChannelsResponse{Channels: []channelResponse{}}
+	for _, ch := range channels {
+		res.Channels = append(res.Channels, newChannelResponse(ch))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+type channelIdRequest struct {
+	ChannelId uuid.UUID `json:"channel_id" binding:"required"`
+}
+
+// @Summary     subscribe channel
+// @Description     subscribe to a channel, online websocket connections of the user start receiving channel posts immediately
+// @ID          subscribeChannel
+// @Tags  	    channel
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body channelIdRequest true "channel to subscribe"
+// @Success     200 {object} channelMessageResponse
+// @Failure     400 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/channels/subscribe [put]
+// Author: https://github.com/lintang-b-s
+func (r *channelRoutes) subscribe(c *gin.Context) {
+	var request channelIdRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - subscribe")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.ch.Subscribe(
+		c.Request.Context(),
+		entity.ChannelReqUc{ChannelId: request.ChannelId, UserName: authPayload.Username},
+	)
+	if err != nil {
+		if r.channelError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- subscribe")
+		ErrorResponse(c, http.StatusInternalServerError, "subscribe service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, channelMessageResponse{ResponseMessage: "subscribed to channel"})
+}
+
+// @Summary     unsubscribe channel
+// @Description     unsubscribe from a channel. The channel owner can not unsubscribe
+// @ID          unsubscribeChannel
+// @Tags  	    channel
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body channelIdRequest true "channel to unsubscribe"
+// @Success     200 {object} channelMessageResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/channels/unsubscribe [put]
+// Author: https://github.com/lintang-b-s
+func (r *channelRoutes) unsubscribe(c *gin.Context) {
+	var request channelIdRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - unsubscribe")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.ch.Unsubscribe(
+		c.Request.Context(),
+		entity.ChannelReqUc{ChannelId: request.ChannelId, UserName: authPayload.Username},
+	)
+	if err != nil {
+		if r.channelError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- unsubscribe")
+		ErrorResponse(c, http.StatusInternalServerError, "unsubscribe service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, channelMessageResponse{ResponseMessage: "unsubscribed from channel"})
+}
+
+type channelMemberRequest struct {
+	ChannelId uuid.UUID `json:"channel_id" binding:"required"`
+	Member    string    `json:"member" binding:"required"`
+}
+
+// @Summary     promote channel admin
+// @Description     promote a subscriber to channel admin, admins can post to the channel. Owner only
+// @ID          promoteChannelAdmin
+// @Tags  	    channel
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body channelMemberRequest true "subscriber to promote"
+// @Success     200 {object} channelMessageResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/channels/promote [put]
+// Author: https://github.com/lintang-b-s
+func (r *channelRoutes) promoteAdmin(c *gin.Context) {
+	r.changeMemberRole(c, entity.ChannelRoleAdmin, "promoteAdmin")
+}
+
+// @Summary     demote channel admin
+// @Description     demote a channel admin back to subscriber. Owner only
+// @ID          demoteChannelAdmin
+// @Tags  	    channel
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body channelMemberRequest true "admin to demote"
+// @Success     200 {object} channelMessageResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/channels/demote [put]
+// Author: https://github.com/lintang-b-s
+func (r *channelRoutes) demoteAdmin(c *gin.Context) {
+	r.changeMemberRole(c, entity.ChannelRoleSubscriber, "demoteAdmin")
+}
+
+func (r *channelRoutes) changeMemberRole(c *gin.Context, role entity.ChannelRole, handlerName string) {
+	var request channelMemberRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - "+handlerName)
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.ch.ChangeMemberRole(
+		c.Request.Context(),
+		entity.ChangeChannelRoleReqUc{
+			ChannelId: request.ChannelId,
+			UserName:  authPayload.Username,
+			Member:    request.Member,
+			Role:      role,
+		},
+	)
+	if err != nil {
+		if r.channelError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- " + handlerName)
+		ErrorResponse(c, http.StatusInternalServerError, handlerName+" service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, channelMessageResponse{ResponseMessage: request.Member + " is now " + string(role)})
+}
+
+type channelMessagesResponse struct {
+	Messages []entity.ChannelMessage `json:"messages"`
+}
+
+// @Summary     get channel messages
+// @Description     get channel messages posted after afterId (message id), subscribers only
+// @ID          getChannelMessages
+// @Tags  	    channel
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       channelId query string true "channel id"
+// @Param       afterId query int false "only messages with a bigger message id"
+// @Param       limit query int false "max messages (default 50, max 100)"
+// @Success     200 {object} channelMessagesResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/channels/messages [get]
+// Author: https://github.com/lintang-b-s
+func (r *channelRoutes) getMessages(c *gin.Context) {
+	channelId, err := uuid.Parse(c.Query("channelId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid channelId")
+		return
+	}
+	afterId, err := strconv.ParseUint(c.DefaultQuery("afterId", "0"), 10, 64)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid afterId")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	msgs, err := r.ch.GetMessages(
+		c.Request.Context(),
+		entity.ChannelMessagesReqUc{
+			ChannelId: channelId,
+			UserName:  authPayload.Username,
+			AfterId:   afterId,
+			Limit:     limit,
+		},
+	)
+	if err != nil {
+		if r.channelError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getChannelMessages")
+		ErrorResponse(c, http.StatusInternalServerError, "getChannelMessages service problems: "+err.Error())
+		return
+	}
+	res := channelMessagesResponse{Messages: msgs.Messages}
+	if res.Messages == nil {
+		res.Messages = []entity.ChannelMessage{}
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/lintangbs/chat-be/internal/entity"
 	api "github.com/lintangbs/chat-be/internal/middleware"
 	"github.com/lintangbs/chat-be/internal/usecase"
@@ -11,6 +13,8 @@ import (
 	"github.com/lintangbs/chat-be/pkg/logger"
 	"gorm.io/gorm"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type contactRoutes struct {
@@ -19,13 +23,22 @@ type contactRoutes struct {
 	jwt jwt.JwtTokenMaker
 }
 
-func NewContactRoutes(handler *gin.RouterGroup, c usecase.Contact, l logger.Interface, jwt jwt.JwtTokenMaker) {
+func NewContactRoutes(handler *gin.RouterGroup, c usecase.Contact, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
 	r := &contactRoutes{c, l, jwt}
 
-	h := handler.Group("/contact").Use(api.AuthMiddleware(r.jwt))
+	h := handler.Group("/contact").Use(api.AuthMiddleware(r.jwt, suspension))
 	{
 		h.POST("/add", r.addContact)
 		h.GET("/", r.getContact)
+		h.PUT("/remove", r.removeContact)
+		h.PUT("/update", r.updateContact)
+		h.POST("/requests", r.sendFriendRequest)
+		h.GET("/requests", r.getFriendRequests)
+		h.PUT("/requests/accept", r.acceptFriendRequest)
+		h.PUT("/requests/decline", r.declineFriendRequest)
+		h.PUT("/requests/cancel", r.cancelFriendRequest)
+		h.GET("/discovery/salt", r.getDiscoverySalt)
+		h.POST("/discovery", r.discoverContacts)
 	}
 }
 
@@ -33,66 +46,263 @@ type addFriendRequest struct {
 	FriendUsername string `json:"friend_username"`
 }
 
+type friendRequestIdRequest struct {
+	RequestId uuid.UUID `json:"request_id"`
+}
+
+type friendRequestResponse struct {
+	Id                uuid.UUID  `json:"id"`
+	SenderUsername    string     `json:"sender_username"`
+	RecipientUsername string     `json:"recipient_username"`
+	Status            string     `json:"status"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+func newFriendRequestResponse(friendReq entity.FriendRequest) friendRequestResponse {
+	return friendRequestResponse{
+		Id:                friendReq.Id,
+		SenderUsername:    friendReq.SenderUsername,
+		RecipientUsername: friendReq.RecipientUsername,
+		Status:            string(friendReq.Status),
+		DecidedAt:         friendReq.DecidedAt,
+		CreatedAt:         friendReq.CreatedAt,
+	}
+}
+
+type friendRequestsResponse struct {
+	Incoming []friendRequestResponse `json:"incoming"`
+	Outgoing []friendRequestResponse `json:"outgoing"`
+}
+
+// friendRequestError mapping error friend request ke http status, return true jika error sudah di handle
+func (r *contactRoutes) friendRequestError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	switch {
+	case unwrapedErr == usecase.CannotAddYourselfErr || unwrapedErr == repo.AlreadyYourInYourContactErr:
+		ErrorResponse(c, http.StatusBadRequest, " Bad Request : "+unwrapedErr.Error())
+	case unwrapedErr == usecase.FriendRequestPermissionDeniedErr || unwrapedErr == usecase.BlockedByUserErr:
+		ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+	case unwrapedErr == repo.FriendRequestAlreadyDecidedErr:
+		ErrorResponse(c, http.StatusConflict, unwrapedErr.Error())
+	case errRepo == repo.FriendRequestPendingErr || errRepo == repo.FriendRequestAlreadyDecidedErr:
+		ErrorResponse(c, http.StatusConflict, errRepo.Error())
+	case errRepo == gorm.ErrRecordNotFound:
+		ErrorResponse(c, http.StatusBadRequest, "User or friend request not found: "+errRepo.Error())
+	default:
+		return false
+	}
+	return true
+}
+
 // @Summary     Add Contact
-// @Description    Add Contact
+// @Description    Send a friend request, the user is added to contacts after the request is accepted. Deprecated, use POST /v1/contact/requests
 // @ID          addContact
 // @Tags  	    contact
 // @Accept      json
 // @Produce     json
 // @Security OAuth2Application
 // @Param       request body addFriendRequest true "set up addFriendRequest"
-// @Success     200 {object} userResponse
+// @Success     201 {object} friendRequestResponse
 // @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
 // @Failure     500 {object} response
 // @Router      /v1/contact/add [post]
 // Author: https://github.com/lintang-b-s
 func (r *contactRoutes) addContact(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	r.sendFriendRequest(c)
+}
+
+// @Summary     Send friend request
+// @Description    Send a friend request, contacts are created in both directions after the request is accepted
+// @ID          sendFriendRequest
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body addFriendRequest true "set up addFriendRequest"
+// @Success     201 {object} friendRequestResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/requests [post]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) sendFriendRequest(c *gin.Context) {
 	var request addFriendRequest
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		r.l.Error(err, "http - v1 - addContact")
+		r.l.Error(err, "http - v1 - sendFriendRequest")
 		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
 		return
 	}
 	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
 
-	user, err := r.c.AddContact(
+	friendReq, err := r.c.SendFriendRequest(
 		c.Request.Context(),
 		entity.AddFriendRequest{
 			MyUsername:     authPayload.Username,
 			FriendUsername: request.FriendUsername,
 		},
 	)
-
 	if err != nil {
-		unwrapedErr := errors.Unwrap(err)
-		errRepo := errors.Unwrap(unwrapedErr)
-		if errRepo == gorm.ErrRecordNotFound {
-			ErrorResponse(c, http.StatusBadRequest, "User not found: "+errRepo.Error())
+		if r.friendRequestError(c, err) {
 			return
 		}
+		r.l.Error(err, "http - v1 - sendFriendRequest")
+		ErrorResponse(c, http.StatusInternalServerError, "sendFriendRequest service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, newFriendRequestResponse(friendReq))
+}
+
+// @Summary     Get friend requests
+// @Description    Get pending friend requests sent to and sent by the logged in user
+// @ID          getFriendRequests
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} friendRequestsResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/requests [get]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) getFriendRequests(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
 
-		if errRepo == repo.AlreadyYourInYourContactErr {
-			ErrorResponse(c, http.StatusBadRequest, " Bad Request : "+errRepo.Error())
+	friendReqs, err := r.c.GetFriendRequests(c.Request.Context(), entity.GetContactRequest{MyUsername: authPayload.Username})
+	if err != nil {
+		if r.friendRequestError(c, err) {
 			return
 		}
+		r.l.Error(err, "http - v1 - getFriendRequests")
+		ErrorResponse(c, http.StatusInternalServerError, "getFriendRequests service problems: "+err.Error())
+		return
+	}
+
+	res := friendRequestsResponse{
+		Incoming: []friendRequestResponse{},
+		Outgoing: []friendRequestResponse{},
+	}
+	for _, friendReq := range friendReqs.Incoming {
+		res.Incoming = append(res.Incoming, newFriendRequestResponse(friendReq))
+	}
+	for _, friendReq := range friendReqs.Outgoing {
+		res.Outgoing = append(res.Outgoing, newFriendRequestResponse(friendReq))
+	}
+	c.JSON(http.StatusOK, res)
+}
 
-		r.l.Error("http - v1- addContact")
-		ErrorResponse(c, http.StatusInternalServerError, "addContact service problems: "+err.Error())
+// @Summary     Accept friend request
+// @Description    Accept a friend request sent to the logged in user, both users become contacts of each other
+// @ID          acceptFriendRequest
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body friendRequestIdRequest true "set up friendRequestIdRequest"
+// @Success     200 {object} friendRequestResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/requests/accept [put]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) acceptFriendRequest(c *gin.Context) {
+	r.respondFriendRequest(c, "acceptFriendRequest", r.c.AcceptFriendRequest)
+}
+
+// @Summary     Decline friend request
+// @Description    Decline a friend request sent to the logged in user
+// @ID          declineFriendRequest
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body friendRequestIdRequest true "set up friendRequestIdRequest"
+// @Success     200 {object} friendRequestResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/requests/decline [put]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) declineFriendRequest(c *gin.Context) {
+	r.respondFriendRequest(c, "declineFriendRequest", r.c.DeclineFriendRequest)
+}
+
+// @Summary     Cancel friend request
+// @Description    Cancel a pending friend request sent by the logged in user
+// @ID          cancelFriendRequest
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body friendRequestIdRequest true "set up friendRequestIdRequest"
+// @Success     200 {object} friendRequestResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/requests/cancel [put]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) cancelFriendRequest(c *gin.Context) {
+	r.respondFriendRequest(c, "cancelFriendRequest", r.c.CancelFriendRequest)
+}
+
+// respondFriendRequest handler accept / decline / cancel friend request
+func (r *contactRoutes) respondFriendRequest(c *gin.Context, name string,
+	respond func(context.Context, entity.FriendRequestReqUc) (entity.FriendRequest, error)) {
+	var request friendRequestIdRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.RequestId == uuid.Nil {
+		r.l.Error(err, "http - v1 - "+name)
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
 
-	res := userResponse{
-		Username: user.Username,
-		Id:       user.Id,
-		Email:    user.Email,
+	friendReq, err := respond(c.Request.Context(), entity.FriendRequestReqUc{
+		UserName:  authPayload.Username,
+		RequestId: request.RequestId,
+	})
+	if err != nil {
+		if r.friendRequestError(c, err) {
+			return
+		}
+		r.l.Error(err, "http - v1 - "+name)
+		ErrorResponse(c, http.StatusInternalServerError, name+" service problems: "+err.Error())
+		return
 	}
 
-	c.JSON(http.StatusCreated, res)
+	c.JSON(http.StatusOK, newFriendRequestResponse(friendReq))
+}
+
+// contactResponse kontak user, nickname & note hanya terlihat oleh user
+type contactResponse struct {
+	Id       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	Nickname string    `json:"nickname"`
+	Note     string    `json:"note"`
+}
+
+func newContactResponse(contact entity.UserResponse) contactResponse {
+	return contactResponse{
+		Id:       contact.Id,
+		Username: contact.Username,
+		Email:    contact.Email,
+		Nickname: contact.Nickname,
+		Note:     contact.Note,
+	}
 }
 
 type getContactResponse struct {
-	Contacts []userResponse `json:"contacts"`
+	Contacts []contactResponse `json:"contacts"`
 }
 
 // @Summary     Get  User Contact
@@ -129,14 +339,9 @@ func (r *contactRoutes) getContact(c *gin.Context) {
 		return
 	}
 
-	var uFriends []userResponse
+	var uFriends []contactResponse
 	for _, contact := range contacts.Friends {
-		uFriend := userResponse{
-			Id:       contact.Id,
-			Username: contact.Username,
-			Email:    contact.Email,
-		}
-		uFriends = append(uFriends, uFriend)
+		uFriends = append(uFriends, newContactResponse(contact))
 	}
 
 	res := getContactResponse{
@@ -145,3 +350,223 @@ func (r *contactRoutes) getContact(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+type removeContactRequest struct {
+	FriendUsername string `json:"friend_username"`
+}
+
+type updateContactRequest struct {
+	FriendUsername string  `json:"friend_username"`
+	Nickname       *string `json:"nickname"` // null / tidak dikirim berarti tidak diubah
+	Note           *string `json:"note"`     // null / tidak dikirim berarti tidak diubah
+}
+
+type contactMessageResponse struct {
+	ResponseMessage string `json:"message"`
+}
+
+// contactError mapping error kontak ke http status, return true jika error sudah di handle
+func (r *contactRoutes) contactError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	switch {
+	case unwrapedErr == usecase.InvalidContactNicknameErr || unwrapedErr == usecase.InvalidContactNoteErr:
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+	case errRepo == repo.ErrNotFoundContactErr:
+		ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+	case errRepo == gorm.ErrRecordNotFound:
+		ErrorResponse(c, http.StatusBadRequest, "User not found: "+errRepo.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary     Remove Contact
+// @Description    Remove a contact in both directions. Online status is no longer shared between both users
+// @ID          removeContact
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body removeContactRequest true "contact to remove"
+// @Success     200 {object} contactMessageResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/remove [put]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) removeContact(c *gin.Context) {
+	var request removeContactRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - removeContact")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.c.RemoveContact(c.Request.Context(), entity.AddFriendRequest{
+		MyUsername:     authPayload.Username,
+		FriendUsername: request.FriendUsername,
+	})
+	if err != nil {
+		if r.contactError(c, err) {
+			return
+		}
+		r.l.Error(err, "http - v1 - removeContact")
+		ErrorResponse(c, http.StatusInternalServerError, "removeContact service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, contactMessageResponse{ResponseMessage: request.FriendUsername + " removed from your contacts"})
+}
+
+// @Summary     Update Contact
+// @Description    Set a private nickname and/or note for a contact, only visible to the logged in user
+// @ID          updateContact
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body updateContactRequest true "contact nickname and note"
+// @Success     200 {object} contactResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/update [put]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) updateContact(c *gin.Context) {
+	var request updateContactRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - updateContact")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	contact, err := r.c.UpdateContact(c.Request.Context(), entity.UpdateContactReqUc{
+		MyUsername:     authPayload.Username,
+		FriendUsername: request.FriendUsername,
+		Nickname:       request.Nickname,
+		Note:           request.Note,
+	})
+	if err != nil {
+		if r.contactError(c, err) {
+			return
+		}
+		r.l.Error(err, "http - v1 - updateContact")
+		ErrorResponse(c, http.StatusInternalServerError, "updateContact service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newContactResponse(contact))
+}
+
+type discoveryRequest struct {
+	Hashes []string `json:"hashes"` // hex(sha256(salt + lower(trim(email))))
+}
+
+type discoverySaltResponse struct {
+	Salt string `json:"salt"`
+}
+
+type discoveryMatchResponse struct {
+	Hash        string    `json:"hash"`
+	Id          uuid.UUID `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	AvatarUrl   string    `json:"avatar_url"`
+}
+
+type discoveryResponse struct {
+	Matches        []discoveryMatchResponse `json:"matches"`
+	RemainingQuota int64                    `json:"remaining_quota"`
+	QuotaResetAt   time.Time                `json:"quota_reset_at"`
+}
+
+// discoveryError mapping error pencarian user dari hash email ke http status, return true jika error sudah di handle
+func (r *contactRoutes) discoveryError(c *gin.Context, err error, quota entity.ContactDiscoveryResult) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	switch {
+	case unwrapedErr == usecase.InvalidEmailHashErr || unwrapedErr == usecase.DiscoveryBatchTooLargeErr:
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+	case unwrapedErr == usecase.DiscoveryRateLimitErr:
+		retryAfter := int64(time.Until(quota.QuotaResetAt).Seconds())
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+		ErrorResponse(c, http.StatusTooManyRequests, unwrapedErr.Error())
+	case errRepo == gorm.ErrRecordNotFound:
+		ErrorResponse(c, http.StatusBadRequest, "User not found: "+errRepo.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary     Get contact discovery salt
+// @Description    Get the salt used to hash the email addresses of the address book. Each email must be sent as hex(sha256(salt + lower(trim(email))))
+// @ID          getDiscoverySalt
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} discoverySaltResponse
+// @Router      /v1/contact/discovery/salt [get]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) getDiscoverySalt(c *gin.Context) {
+	salt := r.c.GetDiscoverySalt(c.Request.Context())
+	c.JSON(http.StatusOK, discoverySaltResponse{Salt: salt.Salt})
+}
+
+// @Summary     Discover contacts
+// @Description    Find registered users from a batch of salted email hashes of the address book (see GET /v1/contact/discovery/salt). Every unique hash uses the quota of the user and of the client ip, returns 429 when a quota is exhausted
+// @ID          discoverContacts
+// @Tags  	    contact
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body discoveryRequest true "salted email hashes"
+// @Success     200 {object} discoveryResponse
+// @Failure     400 {object} response
+// @Failure     429 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/contact/discovery [post]
+// Author: https://github.com/lintang-b-s
+func (r *contactRoutes) discoverContacts(c *gin.Context) {
+	var request discoveryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - discoverContacts")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	result, err := r.c.DiscoverContacts(c.Request.Context(), entity.ContactDiscoveryReqUc{
+		MyUsername: authPayload.Username,
+		ClientIp:   c.ClientIP(),
+		Hashes:     request.Hashes,
+	})
+	if err != nil {
+		if r.discoveryError(c, err, result) {
+			return
+		}
+		r.l.Error(err, "http - v1 - discoverContacts")
+		ErrorResponse(c, http.StatusInternalServerError, "discoverContacts service problems: "+err.Error())
+		return
+	}
+
+	matches := make([]discoveryMatchResponse, 0, len(result.Matches))
+	for _, match := range result.Matches {
+		matches = append(matches, discoveryMatchResponse{
+			Hash:        match.Hash,
+			Id:          match.UserId,
+			Username:    match.Username,
+			DisplayName: match.DisplayName,
+			AvatarUrl:   match.AvatarUrl,
+		})
+	}
+	c.JSON(http.StatusOK, discoveryResponse{
+		Matches:        matches,
+		RemainingQuota: result.RemainingQuota,
+		QuotaResetAt:   result.QuotaResetAt,
+	})
+}
@@ -0,0 +1,247 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"github.com/lintangbs/chat-be/pkg/logger"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type conversationSettingRoutes struct {
+	cs  usecase.ConversationSetting
+	l   logger.Interface
+	jwt jwt.JwtTokenMaker
+}
+
+func newConversationSettingRoutes(handler *gin.RouterGroup, cs usecase.ConversationSetting, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
+	r := &conversationSettingRoutes{cs, l, jwt}
+
+	h := handler.Group("/conversations").Use(api.AuthMiddleware(r.jwt, suspension))
+	{
+		h.GET("/settings", r.getSettings)
+		h.PUT("/mute", r.mute)
+		h.PUT("/unmute", r.unmute)
+		h.PUT("/archive", r.archive)
+		h.PUT("/unarchive", r.unarchive)
+	}
+}
+
+// conversationRequest percakapan yang settingnya diubah, isi friend_username atau group_id
+type conversationRequest struct {
+	FriendUsername string    `json:"friend_username"`
+	GroupId        uuid.UUID `json:"group_id"`
+}
+
+type muteConversationRequest struct {
+	FriendUsername string     `json:"friend_username"`
+	GroupId        uuid.UUID  `json:"group_id"`
+	MutedUntil     *time.Time `json:"muted_until"` // kosong berarti mute tanpa batas waktu
+}
+
+type conversationSettingResponse struct {
+	ConversationType string     `json:"conversation_type"`
+	ConversationId   uuid.UUID  `json:"conversation_id"`
+	ConversationName string     `json:"conversation_name"`
+	Muted            bool       `json:"muted"`
+	MutedUntil       *time.Time `json:"muted_until,omitempty"`
+	Archived         bool       `json:"archived"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func newConversationSettingResponse(setting entity.ConversationSetting) conversationSettingResponse {
+	return conversationSettingResponse{
+		ConversationType: string(setting.ConversationType),
+		ConversationId:   setting.ConversationId,
+		ConversationName: setting.ConversationName,
+		Muted:            setting.IsMuted(time.Now()),
+		MutedUntil:       setting.MutedUntil,
+		Archived:         setting.Archived,
+		UpdatedAt:        setting.UpdatedAt,
+	}
+}
+
+type conversationSettingsResponse struct {
+	Settings []conversationSettingResponse `json:"settings"`
+}
+
+// conversationSettingError mapping error setting percakapan ke http status, return true jika error sudah di handle
+func (r *conversationSettingRoutes) conversationSettingError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	switch {
+	case unwrapedErr == usecase.ConversationRequiredErr || unwrapedErr == usecase.InvalidMutedUntilErr ||
+		unwrapedErr == usecase.GroupIdRequiredErr:
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+	case unwrapedErr == gorm.ErrRecordNotFound:
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+	case errRepo == gorm.ErrRecordNotFound || errRepo == repo.ErrNotFoundContactErr || errRepo == repo.UserNotMemberErr:
+		ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary     get conversation settings
+// @Description     get all muted and archived conversations of the logged in user
+// @ID          getConversationSettings
+// @Tags  	    conversation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} conversationSettingsResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/conversations/settings [get]
+// Author: https://github.com/lintang-b-s
+func (r *conversationSettingRoutes) getSettings(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	settings, err := r.cs.GetSettings(c.Request.Context(), authPayload.Username)
+	if err != nil {
+		if r.conversationSettingError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getSettings")
+		ErrorResponse(c, http.StatusInternalServerError, "getSettings service problems: "+err.Error())
+		return
+	}
+
+	res := conversationSettingsResponse{Settings: []conversationSettingResponse{}}
+	for _, setting := range settings {
+		res.Settings = append(res.Settings, newConversationSettingResponse(setting))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary     mute conversation
+// @Description     mute a private chat (friend_username) or group (group_id) indefinitely or until muted_until.
+// @Description     Messages are still delivered, but with muted=true so the client does not raise a notification
+// @ID          muteConversation
+// @Tags  	    conversation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body muteConversationRequest true "conversation to mute"
+// @Success     200 {object} conversationSettingResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/conversations/mute [put]
+// Author: https://github.com/lintang-b-s
+func (r *conversationSettingRoutes) mute(c *gin.Context) {
+	var request muteConversationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - mute")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	setting, err := r.cs.Mute(
+		c.Request.Context(),
+		entity.ConversationSettingReqUc{
+			UserName:       authPayload.Username,
+			FriendUsername: request.FriendUsername,
+			GroupId:        request.GroupId,
+			MutedUntil:     request.MutedUntil,
+		},
+	)
+	if err != nil {
+		if r.conversationSettingError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- mute")
+		ErrorResponse(c, http.StatusInternalServerError, "mute service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newConversationSettingResponse(setting))
+}
+
+// @Summary     unmute conversation
+// @Description     unmute a private chat (friend_username) or group (group_id)
+// @ID          unmuteConversation
+// @Tags  	    conversation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body conversationRequest true "conversation to unmute"
+// @Success     200 {object} conversationSettingResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/conversations/unmute [put]
+// Author: https://github.com/lintang-b-s
+func (r *conversationSettingRoutes) unmute(c *gin.Context) {
+	r.updateSetting(c, "unmute", r.cs.Unmute)
+}
+
+// @Summary     archive conversation
+// @Description     archive a private chat (friend_username) or group (group_id)
+// @ID          archiveConversation
+// @Tags  	    conversation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body conversationRequest true "conversation to archive"
+// @Success     200 {object} conversationSettingResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/conversations/archive [put]
+// Author: https://github.com/lintang-b-s
+func (r *conversationSettingRoutes) archive(c *gin.Context) {
+	r.updateSetting(c, "archive", r.cs.Archive)
+}
+
+// @Summary     unarchive conversation
+// @Description     move a private chat (friend_username) or group (group_id) out of the archive
+// @ID          unarchiveConversation
+// @Tags  	    conversation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body conversationRequest true "conversation to unarchive"
+// @Success     200 {object} conversationSettingResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/conversations/unarchive [put]
+// Author: https://github.com/lintang-b-s
+func (r *conversationSettingRoutes) unarchive(c *gin.Context) {
+	r.updateSetting(c, "unarchive", r.cs.Unarchive)
+}
+
+func (r *conversationSettingRoutes) updateSetting(c *gin.Context, handlerName string,
+	update func(context.Context, entity.ConversationSettingReqUc) (entity.ConversationSetting, error)) {
+	var request conversationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - "+handlerName)
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	setting, err := update(
+		c.Request.Context(),
+		entity.ConversationSettingReqUc{
+			UserName:       authPayload.Username,
+			FriendUsername: request.FriendUsername,
+			GroupId:        request.GroupId,
+		},
+	)
+	if err != nil {
+		if r.conversationSettingError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- " + handlerName)
+		ErrorResponse(c, http.StatusInternalServerError, handlerName+" service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newConversationSettingResponse(setting))
+}
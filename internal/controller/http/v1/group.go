@@ -21,14 +21,33 @@ type groupRoutes struct {
 	jwt jwt.JwtTokenMaker
 }
 
-func newGroupRoutes(handler *gin.RouterGroup, g usecase.Group, l logger.Interface, jwt jwt.JwtTokenMaker) {
+func newGroupRoutes(handler *gin.RouterGroup, g usecase.Group, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
 	r := &groupRoutes{g, l, jwt}
 
-	h := handler.Group("/groups").Use(api.AuthMiddleware(r.jwt))
+	h := handler.Group("/groups").Use(api.AuthMiddleware(r.jwt, suspension))
 	{
 		h.POST("", r.createGroup)
+		h.GET("", r.getUserGroups)
+		h.GET("/members", r.getGroupMembers)
 		h.PUT("/add", r.addNewGroupMember)
 		h.PUT("/remove", r.removeGroupMember)
+		h.PUT("/promote", r.promoteGroupMember)
+		h.PUT("/demote", r.demoteGroupMember)
+		h.PUT("/rename", r.renameGroup)
+		h.PUT("/update", r.updateGroup)
+		h.PUT("/leave", r.leaveGroup)
+		h.PUT("/transfer", r.transferGroupOwnership)
+		h.PUT("/delete", r.deleteGroup)
+		h.PUT("/pin", r.pinGroupMessage)
+		h.PUT("/unpin", r.unpinGroupMessage)
+		h.PUT("/messages/delete", r.deleteGroupMessage)
+		h.POST("/invites", r.createGroupInvite)
+		h.GET("/invites", r.getGroupInvites)
+		h.PUT("/invites/revoke", r.revokeGroupInvite)
+		h.POST("/join/:token", r.joinGroup)
+		h.GET("/join-requests", r.getGroupJoinRequests)
+		h.PUT("/join-requests/approve", r.approveGroupJoinRequest)
+		h.PUT("/join-requests/deny", r.denyGroupJoinRequest)
 	}
 }
 
@@ -54,6 +73,8 @@ type groupResponse struct {
 // @Param       request body createGroupRequest true "set up new group"
 // @Success     200 {object} groupResponse
 // @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
 // @Failure     500 {object} response
 // @Router      /v1/groups [post]
 // Author: https://github.com/lintang-b-s
@@ -85,8 +106,12 @@ func (r *groupRoutes) createGroup(c *gin.Context) {
 			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
 			return
 		}
-		if errRepo == repo.GroupAlreadyExistsErr {
-			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+		if unwrapedErr == usecase.BlockedByUserErr {
+			ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+			return
+		}
+		if unwrapedErr == usecase.GroupMemberLimitErr {
+			ErrorResponse(c, http.StatusConflict, unwrapedErr.Error())
 			return
 		}
 
@@ -105,10 +130,65 @@ func (r *groupRoutes) createGroup(c *gin.Context) {
 	c.JSON(http.StatusCreated, res)
 }
 
+type userGroupResponse struct {
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	AvatarUrl   string    `json:"avatar_url"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type getUserGroupsResponse struct {
+	Groups []userGroupResponse `json:"groups"`
+}
+
+// @Summary     get user groups
+// @Description     get all groups the user is a member of, with the group id used by the other group endpoints
+// @ID          getUserGroups
+// @Tags  	    group
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} getUserGroupsResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups [get]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) getUserGroups(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	groups, err := r.g.GetUserGroups(c.Request.Context(), authPayload.Username)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- getUserGroups")
+		ErrorResponse(c, http.StatusInternalServerError, "getUserGroups service problems: "+err.Error())
+		return
+	}
+
+	res := getUserGroupsResponse{Groups: []userGroupResponse{}}
+	for _, group := range groups {
+		res.Groups = append(res.Groups, userGroupResponse{
+			Id:          group.Id,
+			Name:        group.Name,
+			Description: group.Description,
+			AvatarUrl:   group.AvatarUrl,
+			Role:        string(group.Role),
+			CreatedAt:   group.CreatedAt,
+			UpdatedAt:   group.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, res)
+}
+
 // AddNewMemberReqUc request in usecasee
 type addNewGroupMemberRequest struct {
-	Name    string   `json:"name"`
-	Members []string `json:"members"`
+	GroupId uuid.UUID `json:"group_id"`
+	Name    string    `json:"name"` // deprecated, pakai group_id
+	Members []string  `json:"members"`
 }
 
 // @Summary     add new group member
@@ -121,6 +201,8 @@ type addNewGroupMemberRequest struct {
 // @Param       request body addNewGroupMemberRequest true "set up new group"
 // @Success     200 {object} groupResponse
 // @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
 // @Failure     500 {object} response
 // @Router      /v1/groups/add [put]
 // Author: https://github.com/lintang-b-s
@@ -132,22 +214,23 @@ func (r *groupRoutes) addNewGroupMember(c *gin.Context) {
 		return
 	}
 	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+	deprecatedGroupName(c, request.GroupId, request.Name)
 
 	group, err := r.g.AddNewGroupMember(
 		c.Request.Context(),
 		entity.AddNewGroupMemberReqUc{
+			GroupId:  request.GroupId,
 			Name:     request.Name,
 			UserName: authPayload.Username,
 			Members:  request.Members,
 		},
 	)
 	if err != nil {
-		unwrapedErr := errors.Unwrap(err)
-		errRepo := errors.Unwrap(unwrapedErr)
-		if errRepo == gorm.ErrRecordNotFound {
-			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+		if r.groupError(c, err) {
 			return
 		}
+		unwrapedErr := errors.Unwrap(err)
+		errRepo := errors.Unwrap(unwrapedErr)
 
 		if errRepo == repo.ErrNotFoundContactErr {
 			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
@@ -174,8 +257,9 @@ func (r *groupRoutes) addNewGroupMember(c *gin.Context) {
 }
 
 type removeGroupMember struct {
-	Name         string `json:"name"`
-	UsertoRemove string `json:"userto_remove"`
+	GroupId      uuid.UUID `json:"group_id"`
+	Name         string    `json:"name"` // deprecated, pakai group_id
+	UsertoRemove string    `json:"userto_remove"`
 }
 
 // @Summary    remove group member
@@ -199,20 +283,19 @@ func (r *groupRoutes) removeGroupMember(c *gin.Context) {
 		return
 	}
 	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+	deprecatedGroupName(c, request.GroupId, request.Name)
 
 	group, err := r.g.RemoveGroupMember(
 		c.Request.Context(),
 		entity.RemoveGroupMemberReqUc{
+			GroupId:      request.GroupId,
 			Name:         request.Name,
 			UserName:     authPayload.Username,
 			UsertoRemove: request.UsertoRemove,
 		},
 	)
 	if err != nil {
-		unwrapedErr := errors.Unwrap(err)
-		errRepo := errors.Unwrap(unwrapedErr)
-		if errRepo == gorm.ErrRecordNotFound {
-			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+		if r.groupError(c, err) {
 			return
 		}
 
@@ -228,3 +311,316 @@ func (r *groupRoutes) removeGroupMember(c *gin.Context) {
 	}
 	c.JSON(http.StatusCreated, res)
 }
+
+type changeGroupRoleRequest struct {
+	GroupId  uuid.UUID `json:"group_id"`
+	Name     string    `json:"name"` // deprecated, pakai group_id
+	Username string    `json:"username" binding:"required"`
+}
+
+type groupMemberResponse struct {
+	UserId uuid.UUID `json:"user_id"`
+	Role   string    `json:"role"`
+}
+
+// @Summary     promote group member
+// @Description     owner only, promote a group member to admin
+// @ID          promoteGroupMember
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body changeGroupRoleRequest true "member to promote"
+// @Success     200 {object} groupMemberResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/promote [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) promoteGroupMember(c *gin.Context) {
+	r.changeGroupRole(c, entity.GroupRoleAdmin)
+}
+
+// @Summary     demote group admin
+// @Description     owner only, demote a group admin to member
+// @ID          demoteGroupMember
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body changeGroupRoleRequest true "admin to demote"
+// @Success     200 {object} groupMemberResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/demote [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) demoteGroupMember(c *gin.Context) {
+	r.changeGroupRole(c, entity.GroupRoleMember)
+}
+
+func (r *groupRoutes) changeGroupRole(c *gin.Context, role entity.GroupRole) {
+	var request changeGroupRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - changeGroupRole")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+	deprecatedGroupName(c, request.GroupId, request.Name)
+
+	member, err := r.g.ChangeMemberRole(
+		c.Request.Context(),
+		entity.ChangeGroupRoleReqUc{
+			GroupId:  request.GroupId,
+			Name:     request.Name,
+			UserName: authPayload.Username,
+			Member:   request.Username,
+			Role:     role,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		if unwrapedErr == usecase.InvalidGroupRoleErr || unwrapedErr == usecase.CannotChangeOwnRoleErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- changeGroupRole")
+		ErrorResponse(c, http.StatusInternalServerError, "changeGroupRole service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, groupMemberResponse{UserId: member.UserId, Role: string(member.Role)})
+}
+
+type renameGroupRequest struct {
+	GroupId uuid.UUID `json:"group_id"`
+	Name    string    `json:"name"` // deprecated, pakai group_id
+	NewName string    `json:"new_name" binding:"required"`
+}
+
+// @Summary     rename group
+// @Description     rename group, owner & admin only
+// @ID          renameGroup
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body renameGroupRequest true "new group name"
+// @Success     200 {object} groupResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/rename [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) renameGroup(c *gin.Context) {
+	var request renameGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - renameGroup")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+	deprecatedGroupName(c, request.GroupId, request.Name)
+
+	group, err := r.g.RenameGroup(
+		c.Request.Context(),
+		entity.RenameGroupReqUc{
+			GroupId:  request.GroupId,
+			Name:     request.Name,
+			UserName: authPayload.Username,
+			NewName:  request.NewName,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		if unwrapedErr == usecase.InvalidGroupNameErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- renameGroup")
+		ErrorResponse(c, http.StatusInternalServerError, "renameGroup service problems: "+err.Error())
+		return
+	}
+
+	res := groupResponse{
+		Id:        group.Id,
+		Name:      group.Name,
+		CreatedAt: group.CreatedAt,
+		UpdatedAt: group.UpdatedAt,
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+type groupMessageRequest struct {
+	GroupId   uuid.UUID `json:"group_id"`
+	Name      string    `json:"name"` // deprecated, pakai group_id
+	MessageId uint64    `json:"message_id" binding:"required"`
+}
+
+type groupMessageResponse struct {
+	ResponseMessage string `json:"response_message"`
+}
+
+// @Summary     pin group message
+// @Description     pin a group message, owner & admin only
+// @ID          pinGroupMessage
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body groupMessageRequest true "message to pin"
+// @Success     200 {object} groupMessageResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/pin [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) pinGroupMessage(c *gin.Context) {
+	r.setGroupMessagePinned(c, true)
+}
+
+// @Summary     unpin group message
+// @Description     unpin a group message, owner & admin only
+// @ID          unpinGroupMessage
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body groupMessageRequest true "message to unpin"
+// @Success     200 {object} groupMessageResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/unpin [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) unpinGroupMessage(c *gin.Context) {
+	r.setGroupMessagePinned(c, false)
+}
+
+func (r *groupRoutes) setGroupMessagePinned(c *gin.Context, pinned bool) {
+	var request groupMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - setGroupMessagePinned")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+	deprecatedGroupName(c, request.GroupId, request.Name)
+
+	err := r.g.PinMessage(
+		c.Request.Context(),
+		entity.GroupMessageActionReqUc{
+			GroupId:   request.GroupId,
+			Name:      request.Name,
+			UserName:  authPayload.Username,
+			MessageId: request.MessageId,
+		},
+		pinned,
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- setGroupMessagePinned")
+		ErrorResponse(c, http.StatusInternalServerError, "setGroupMessagePinned service problems: "+err.Error())
+		return
+	}
+
+	if pinned {
+		c.JSON(http.StatusOK, groupMessageResponse{ResponseMessage: "message pinned"})
+		return
+	}
+	c.JSON(http.StatusOK, groupMessageResponse{ResponseMessage: "message unpinned"})
+}
+
+// @Summary     delete group message
+// @Description     delete a group message. Members can delete their own messages, owner & admin can delete anyone's
+// @ID          deleteGroupMessage
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body groupMessageRequest true "message to delete"
+// @Success     200 {object} groupMessageResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/messages/delete [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) deleteGroupMessage(c *gin.Context) {
+	var request groupMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - deleteGroupMessage")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+	deprecatedGroupName(c, request.GroupId, request.Name)
+
+	err := r.g.DeleteMessage(
+		c.Request.Context(),
+		entity.GroupMessageActionReqUc{
+			GroupId:   request.GroupId,
+			Name:      request.Name,
+			UserName:  authPayload.Username,
+			MessageId: request.MessageId,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- deleteGroupMessage")
+		ErrorResponse(c, http.StatusInternalServerError, "deleteGroupMessage service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, groupMessageResponse{ResponseMessage: "message deleted"})
+}
+
+// groupError menulis response untuk error yang umum di endpoint group, return true jika error sudah ditangani
+func (r *groupRoutes) groupError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	if unwrapedErr == usecase.GroupPermissionDeniedErr {
+		ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+		return true
+	}
+	if unwrapedErr == usecase.GroupIdRequiredErr {
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+		return true
+	}
+	if unwrapedErr == usecase.BlockedByUserErr {
+		ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+		return true
+	}
+	if unwrapedErr == usecase.GroupMemberLimitErr || errRepo == usecase.GroupMemberLimitErr {
+		ErrorResponse(c, http.StatusConflict, usecase.GroupMemberLimitErr.Error())
+		return true
+	}
+	if errRepo == gorm.ErrRecordNotFound || errRepo == repo.UserNotMemberErr || errRepo == repo.AmbiguousGroupNameErr {
+		ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+		return true
+	}
+	return false
+}
+
+// deprecatedGroupName memberi tahu client lama yang masih memakai nama group bahwa nama group akan dihapus dari api,
+// nama group tidak lagi unik sehingga client harus pindah ke group id
+func deprecatedGroupName(c *gin.Context, groupId uuid.UUID, groupName string) {
+	if groupId == uuid.Nil && groupName != "" {
+		c.Header("Deprecation", "true")
+		c.Header("Warning", `299 - "group lookup by name is deprecated, use group_id"`)
+	}
+}
@@ -0,0 +1,275 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type createGroupInviteRequest struct {
+	GroupId          uuid.UUID `json:"group_id" binding:"required"`
+	ExpiresIn        int64     `json:"expires_in"` // detik (maksimal 90 hari), 0 berarti tidak pernah expired
+	MaxUses          int       `json:"max_uses"`   // 0 berarti tidak dibatasi
+	ApprovalRequired bool      `json:"approval_required"`
+}
+
+type groupInviteResponse struct {
+	Id               uuid.UUID  `json:"id"`
+	GroupId          uuid.UUID  `json:"group_id"`
+	Token            string     `json:"token"`
+	CreatedBy        uuid.UUID  `json:"created_by"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	MaxUses          int        `json:"max_uses"`
+	Uses             int        `json:"uses"`
+	ApprovalRequired bool       `json:"approval_required"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type getGroupInvitesResponse struct {
+	Invites []groupInviteResponse `json:"invites"`
+}
+
+// @Summary     create group invite link
+// @Description     create an invite link for a group, owner & admin only. Anyone with the token can join via /v1/groups/join/{token}
+// @ID          createGroupInvite
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body createGroupInviteRequest true "invite link settings"
+// @Success     201 {object} groupInviteResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/invites [post]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) createGroupInvite(c *gin.Context) {
+	var request createGroupInviteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - createGroupInvite")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	// dicek sebelum diubah ke time.Duration supaya tidak overflow
+	if request.ExpiresIn > int64(usecase.MaxInviteExpiry/time.Second) {
+		ErrorResponse(c, http.StatusBadRequest, usecase.InvalidInviteErr.Error())
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	invite, err := r.g.CreateInvite(
+		c.Request.Context(),
+		entity.CreateGroupInviteReqUc{
+			GroupId:          request.GroupId,
+			UserName:         authPayload.Username,
+			ExpiresIn:        time.Duration(request.ExpiresIn) * time.Second,
+			MaxUses:          request.MaxUses,
+			ApprovalRequired: request.ApprovalRequired,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		if unwrapedErr == usecase.InvalidInviteErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- createGroupInvite")
+		ErrorResponse(c, http.StatusInternalServerError, "createGroupInvite service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, newGroupInviteResponse(invite))
+}
+
+// @Summary     get group invite links
+// @Description     get all active (not revoked, not expired, not used up) invite links of a group, owner & admin only
+// @ID          getGroupInvites
+// @Tags  	    group
+// @Produce     json
+// @Security OAuth2Application
+// @Param       groupId query string true "group id"
+// @Success     200 {object} getGroupInvitesResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/invites [get]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) getGroupInvites(c *gin.Context) {
+	groupId, err := uuid.Parse(c.Query("groupId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "groupId must be a valid uuid")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	invites, err := r.g.GetInvites(
+		c.Request.Context(),
+		entity.GroupInviteReqUc{
+			GroupId:  groupId,
+			UserName: authPayload.Username,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- getGroupInvites")
+		ErrorResponse(c, http.StatusInternalServerError, "getGroupInvites service problems: "+err.Error())
+		return
+	}
+
+	res := getGroupInvitesResponse{Invites: []groupInviteResponse{}}
+	for _, invite := range invites {
+		res.Invites = append(res.Invites, newGroupInviteResponse(invite))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+type revokeGroupInviteRequest struct {
+	GroupId  uuid.UUID `json:"group_id" binding:"required"`
+	InviteId uuid.UUID `json:"invite_id" binding:"required"`
+}
+
+// @Summary     revoke group invite link
+// @Description     revoke an invite link so its token can no longer be used, owner & admin only
+// @ID          revokeGroupInvite
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body revokeGroupInviteRequest true "invite link to revoke"
+// @Success     200 {object} groupMessageResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/invites/revoke [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) revokeGroupInvite(c *gin.Context) {
+	var request revokeGroupInviteRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - revokeGroupInvite")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.g.RevokeInvite(
+		c.Request.Context(),
+		entity.GroupInviteReqUc{
+			GroupId:  request.GroupId,
+			UserName: authPayload.Username,
+			InviteId: request.InviteId,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- revokeGroupInvite")
+		ErrorResponse(c, http.StatusInternalServerError, "revokeGroupInvite service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, groupMessageResponse{ResponseMessage: "invite link revoked"})
+}
+
+type joinGroupResponse struct {
+	Group       groupResponse             `json:"group"`
+	JoinRequest *groupJoinRequestResponse `json:"join_request,omitempty"`
+}
+
+// @Summary     join group
+// @Description     join a group with an invite link token, the user doesn't need to be a friend of the inviter.
+// @Description     If the invite link requires approval a pending join request is created instead (202), group admins are notified and the user is notified of the decision via websocket
+// @ID          joinGroup
+// @Tags  	    group
+// @Produce     json
+// @Security OAuth2Application
+// @Param       token path string true "invite link token"
+// @Success     200 {object} joinGroupResponse
+// @Success     202 {object} joinGroupResponse
+// @Failure     400 {object} response
+// @Failure     409 {object} response
+// @Failure     410 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/join/{token} [post]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) joinGroup(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	joined, err := r.g.JoinGroup(
+		c.Request.Context(),
+		entity.JoinGroupReqUc{
+			Token:    c.Param("token"),
+			UserName: authPayload.Username,
+		},
+	)
+	if err != nil {
+		unwrapedErr := errors.Unwrap(err)
+		errRepo := errors.Unwrap(unwrapedErr)
+		if unwrapedErr == usecase.InviteTokenRequiredErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+		if errRepo == gorm.ErrRecordNotFound {
+			ErrorResponse(c, http.StatusBadRequest, "invite link not found")
+			return
+		}
+		if errRepo == repo.UserAlreadyMembersErr || errRepo == repo.JoinRequestPendingErr || errRepo == repo.GroupMemberLimitErr {
+			ErrorResponse(c, http.StatusConflict, errRepo.Error())
+			return
+		}
+		if errRepo == repo.InviteExpiredErr || errRepo == repo.InviteRevokedErr || errRepo == repo.InviteExhaustedErr {
+			ErrorResponse(c, http.StatusGone, errRepo.Error())
+			return
+		}
+
+		r.l.Error("http - v1- joinGroup")
+		ErrorResponse(c, http.StatusInternalServerError, "joinGroup service problems: "+err.Error())
+		return
+	}
+
+	res := joinGroupResponse{
+		Group: groupResponse{
+			Id:        joined.Group.Id,
+			Name:      joined.Group.Name,
+			CreatedAt: joined.Group.CreatedAt,
+			UpdatedAt: joined.Group.UpdatedAt,
+		},
+	}
+	if joined.JoinRequest != nil {
+		joinReq := newGroupJoinRequestResponse(*joined.JoinRequest)
+		res.JoinRequest = &joinReq
+		c.JSON(http.StatusAccepted, res)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func newGroupInviteResponse(invite entity.GroupInvite) groupInviteResponse {
+	return groupInviteResponse{
+		Id:               invite.Id,
+		GroupId:          invite.GroupId,
+		Token:            invite.Token,
+		CreatedBy:        invite.CreatedBy,
+		ExpiresAt:        invite.ExpiresAt,
+		MaxUses:          invite.MaxUses,
+		Uses:             invite.Uses,
+		ApprovalRequired: invite.ApprovalRequired,
+		CreatedAt:        invite.CreatedAt,
+	}
+}
@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"net/http"
+	"time"
+)
+
+type groupJoinRequestResponse struct {
+	Id        uuid.UUID  `json:"id"`
+	GroupId   uuid.UUID  `json:"group_id"`
+	GroupName string     `json:"group_name"`
+	Username  string     `json:"username"`
+	Status    string     `json:"status"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type getGroupJoinRequestsResponse struct {
+	JoinRequests []groupJoinRequestResponse `json:"join_requests"`
+}
+
+// @Summary     get group join requests
+// @Description     get all pending join requests of a group, owner & admin only
+// @ID          getGroupJoinRequests
+// @Tags  	    group
+// @Produce     json
+// @Security OAuth2Application
+// @Param       groupId query string true "group id"
+// @Success     200 {object} getGroupJoinRequestsResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/join-requests [get]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) getGroupJoinRequests(c *gin.Context) {
+	groupId, err := uuid.Parse(c.Query("groupId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "groupId must be a valid uuid")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	joinReqs, err := r.g.GetJoinRequests(
+		c.Request.Context(),
+		entity.GroupInviteReqUc{
+			GroupId:  groupId,
+			UserName: authPayload.Username,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- getGroupJoinRequests")
+		ErrorResponse(c, http.StatusInternalServerError, "getGroupJoinRequests service problems: "+err.Error())
+		return
+	}
+
+	res := getGroupJoinRequestsResponse{JoinRequests: []groupJoinRequestResponse{}}
+	for _, joinReq := range joinReqs {
+		res.JoinRequests = append(res.JoinRequests, newGroupJoinRequestResponse(joinReq))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+type decideGroupJoinRequest struct {
+	GroupId   uuid.UUID `json:"group_id" binding:"required"`
+	RequestId uuid.UUID `json:"request_id" binding:"required"`
+}
+
+// @Summary     approve group join request
+// @Description     approve a pending join request, the user becomes a member of the group and is notified via websocket. Owner & admin only.
+// @Description     Fails with 410 when the invite link of the request has been revoked, expired or reached its maximum number of uses
+// @ID          approveGroupJoinRequest
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body decideGroupJoinRequest true "join request to approve"
+// @Success     200 {object} groupJoinRequestResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
+// @Failure     410 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/join-requests/approve [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) approveGroupJoinRequest(c *gin.Context) {
+	r.decideGroupJoinRequest(c, true)
+}
+
+// @Summary     deny group join request
+// @Description     deny a pending join request, the user is notified via websocket. Owner & admin only
+// @ID          denyGroupJoinRequest
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body decideGroupJoinRequest true "join request to deny"
+// @Success     200 {object} groupJoinRequestResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     409 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/join-requests/deny [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) denyGroupJoinRequest(c *gin.Context) {
+	r.decideGroupJoinRequest(c, false)
+}
+
+func (r *groupRoutes) decideGroupJoinRequest(c *gin.Context, approve bool) {
+	var request decideGroupJoinRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - decideGroupJoinRequest")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	joinReq, err := r.g.DecideJoinRequest(
+		c.Request.Context(),
+		entity.DecideJoinRequestReqUc{
+			GroupId:   request.GroupId,
+			UserName:  authPayload.Username,
+			RequestId: request.RequestId,
+			Approve:   approve,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		errRepo := errors.Unwrap(unwrapedErr)
+		if unwrapedErr == repo.JoinRequestAlreadyDecidedErr {
+			ErrorResponse(c, http.StatusConflict, unwrapedErr.Error())
+			return
+		}
+		if errRepo == repo.JoinRequestAlreadyDecidedErr || errRepo == repo.UserAlreadyMembersErr {
+			ErrorResponse(c, http.StatusConflict, errRepo.Error())
+			return
+		}
+		if errRepo == repo.InviteExpiredErr || errRepo == repo.InviteRevokedErr || errRepo == repo.InviteExhaustedErr {
+			ErrorResponse(c, http.StatusGone, errRepo.Error())
+			return
+		}
+
+		r.l.Error("http - v1- decideGroupJoinRequest")
+		ErrorResponse(c, http.StatusInternalServerError, "decideGroupJoinRequest service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, newGroupJoinRequestResponse(joinReq))
+}
+
+func newGroupJoinRequestResponse(joinReq entity.GroupJoinRequest) groupJoinRequestResponse {
+	return groupJoinRequestResponse{
+		Id:        joinReq.Id,
+		GroupId:   joinReq.GroupId,
+		GroupName: joinReq.GroupName,
+		Username:  joinReq.Username,
+		Status:    string(joinReq.Status),
+		DecidedAt: joinReq.DecidedAt,
+		CreatedAt: joinReq.CreatedAt,
+	}
+}
@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"net/http"
+	"time"
+)
+
+type updateGroupRequest struct {
+	GroupId     uuid.UUID `json:"group_id" binding:"required"`
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	AvatarUrl   *string   `json:"avatar_url"`
+}
+
+type groupInfoResponse struct {
+	Id          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	AvatarUrl   string    `json:"avatar_url"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// @Summary     update group
+// @Description     update the name, description and/or avatar of a group, fields that are not sent are left unchanged. Owner & admin only.
+// @Description     Members receive a system message and a group_updated websocket event
+// @ID          updateGroup
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body updateGroupRequest true "new group info"
+// @Success     200 {object} groupInfoResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/update [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) updateGroup(c *gin.Context) {
+	var request updateGroupRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - updateGroup")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	group, err := r.g.UpdateGroup(
+		c.Request.Context(),
+		entity.UpdateGroupReqUc{
+			GroupId:     request.GroupId,
+			UserName:    authPayload.Username,
+			Name:        request.Name,
+			Description: request.Description,
+			AvatarUrl:   request.AvatarUrl,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		if unwrapedErr == usecase.InvalidGroupNameErr || unwrapedErr == usecase.InvalidAvatarUrlErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- updateGroup")
+		ErrorResponse(c, http.StatusInternalServerError, "updateGroup service problems: "+err.Error())
+		return
+	}
+
+	res := groupInfoResponse{
+		Id:          group.Id,
+		Name:        group.Name,
+		Description: group.Description,
+		AvatarUrl:   group.AvatarUrl,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+type groupIdRequest struct {
+	GroupId uuid.UUID `json:"group_id" binding:"required"`
+}
+
+// @Summary     leave group
+// @Description     leave a group. The owner has to transfer ownership or delete the group first
+// @ID          leaveGroup
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body groupIdRequest true "group to leave"
+// @Success     200 {object} groupMessageResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/leave [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) leaveGroup(c *gin.Context) {
+	var request groupIdRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - leaveGroup")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.g.LeaveGroup(
+		c.Request.Context(),
+		entity.GroupReqUc{
+			GroupId:  request.GroupId,
+			UserName: authPayload.Username,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		if unwrapedErr == usecase.OwnerCannotLeaveErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- leaveGroup")
+		ErrorResponse(c, http.StatusInternalServerError, "leaveGroup service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, groupMessageResponse{ResponseMessage: "you left the group"})
+}
+
+type transferGroupOwnershipRequest struct {
+	GroupId  uuid.UUID `json:"group_id" binding:"required"`
+	NewOwner string    `json:"new_owner" binding:"required"`
+}
+
+// @Summary     transfer group ownership
+// @Description     make another member the owner of the group, the current owner becomes an admin. Owner only
+// @ID          transferGroupOwnership
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body transferGroupOwnershipRequest true "new owner username"
+// @Success     200 {object} groupMemberResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/transfer [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) transferGroupOwnership(c *gin.Context) {
+	var request transferGroupOwnershipRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - transferGroupOwnership")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	member, err := r.g.TransferOwnership(
+		c.Request.Context(),
+		entity.TransferOwnershipReqUc{
+			GroupId:  request.GroupId,
+			UserName: authPayload.Username,
+			NewOwner: request.NewOwner,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		if unwrapedErr == usecase.CannotTransferToSelfErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- transferGroupOwnership")
+		ErrorResponse(c, http.StatusInternalServerError, "transferGroupOwnership service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, groupMemberResponse{UserId: member.UserId, Role: string(member.Role)})
+}
+
+// @Summary     delete group
+// @Description     delete a group, members receive a system message and a group_updated websocket event with deleted=true. Owner only
+// @ID          deleteGroup
+// @Tags  	    group
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body groupIdRequest true "group to delete"
+// @Success     200 {object} groupMessageResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/delete [put]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) deleteGroup(c *gin.Context) {
+	var request groupIdRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - deleteGroup")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.g.DeleteGroup(
+		c.Request.Context(),
+		entity.GroupReqUc{
+			GroupId:  request.GroupId,
+			UserName: authPayload.Username,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- deleteGroup")
+		ErrorResponse(c, http.StatusInternalServerError, "deleteGroup service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, groupMessageResponse{ResponseMessage: "group deleted"})
+}
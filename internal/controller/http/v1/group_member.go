@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type groupMemberDetailResponse struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	Online   *bool     `json:"online,omitempty"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type groupMembersResponse struct {
+	GroupId     uuid.UUID                   `json:"group_id"`
+	Total       int64                       `json:"total"`
+	LargeGroup  bool                        `json:"large_group"`
+	Members     []groupMemberDetailResponse `json:"members"`
+	NextAfterId *uuid.UUID                  `json:"next_after_id,omitempty"`
+}
+
+// @Summary     get group members
+// @Description     get group members page by page (ordered by user id), the next page is requested with afterId = next_after_id.
+// @Description     Online status of members is not returned for large groups
+// @ID          getGroupMembers
+// @Tags  	    group
+// @Produce     json
+// @Security OAuth2Application
+// @Param       groupId query string true "group id"
+// @Param       afterId query string false "user id of the last member in the previous page"
+// @Param       limit query int false "max members (default 50, max 200)"
+// @Success     200 {object} groupMembersResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/groups/members [get]
+// Author: https://github.com/lintang-b-s
+func (r *groupRoutes) getGroupMembers(c *gin.Context) {
+	groupId, err := uuid.Parse(c.Query("groupId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "groupId must be a valid uuid")
+		return
+	}
+	afterId := uuid.Nil
+	if c.Query("afterId") != "" {
+		if afterId, err = uuid.Parse(c.Query("afterId")); err != nil {
+			ErrorResponse(c, http.StatusBadRequest, "afterId must be a valid uuid")
+			return
+		}
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	members, err := r.g.GetGroupMembers(
+		c.Request.Context(),
+		entity.GroupMembersReqUc{
+			GroupId:  groupId,
+			UserName: authPayload.Username,
+			AfterId:  afterId,
+			Limit:    limit,
+		},
+	)
+	if err != nil {
+		if r.groupError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- getGroupMembers")
+		ErrorResponse(c, http.StatusInternalServerError, "getGroupMembers service problems: "+err.Error())
+		return
+	}
+	res := groupMembersResponse{
+		GroupId:    members.GroupId,
+		Total:      members.Total,
+		LargeGroup: members.LargeGroup,
+		Members:    []groupMemberDetailResponse{},
+	}
+	for _, member := range members.Members {
+		res.Members = append(res.Members, groupMemberDetailResponse{
+			UserId:   member.UserId,
+			Username: member.Username,
+			Role:     string(member.Role),
+			Online:   member.Online,
+			JoinedAt: member.JoinedAt,
+		})
+	}
+	if members.NextAfterId != uuid.Nil {
+		res.NextAfterId = &members.NextAfterId
+	}
+	c.JSON(http.StatusOK, res)
+}
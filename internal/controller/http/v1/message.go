@@ -2,11 +2,13 @@ package v1
 
 import (
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/lintangbs/chat-be/internal/entity"
 	api "github.com/lintangbs/chat-be/internal/middleware"
 	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/usecase/redisRepo"
 	"github.com/lintangbs/chat-be/internal/usecase/repo"
 	"github.com/lintangbs/chat-be/internal/util/jwt"
 	"github.com/lintangbs/chat-be/pkg/logger"
@@ -21,31 +23,44 @@ type messageRoutes struct {
 	jwt jwt.JwtTokenMaker
 }
 
-func NewMessageRoutes(handler *gin.RouterGroup, m usecase.Message, l logger.Interface, jwt jwt.JwtTokenMaker) {
+func NewMessageRoutes(handler *gin.RouterGroup, m usecase.Message, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
 	r := &messageRoutes{m, l, jwt}
 
-	h := handler.Group("/messages").Use(api.AuthMiddleware(r.jwt))
+	h := handler.Group("/messages").Use(api.AuthMiddleware(r.jwt, suspension))
 	{
 		h.GET("", r.getMessages)
 		h.GET("/friend", r.getMessagesByFriend)
 		h.GET("/group", r.getMessagesByGroupChat)
+		h.GET("/export", r.exportMessages)
+		h.GET("/export/:jobId", r.downloadExport)
 	}
 
 }
 
 // PrivateChat messages
 type privateChatMessage struct {
-	MessageId   uint64    `json:"message_id"`
-	MessageFrom uuid.UUID `json:"message_from"`
-	MessageTo   uuid.UUID `json:"message_to"`
-	Content     string    `json:"content"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	DeletedAt   time.Time `json:"deleted_at"`
+	MessageId   uint64                 `json:"message_id"`
+	MessageFrom uuid.UUID              `json:"message_from"`
+	MessageTo   uuid.UUID              `json:"message_to"`
+	Content     string                 `json:"content"`
+	Format      string                 `json:"format"`
+	Entities    []entity.MessageEntity `json:"entities,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	DeletedAt   time.Time              `json:"deleted_at"`
+}
+
+type draftResponse struct {
+	ConversationType string    `json:"conversation_type"`
+	ConversationId   uuid.UUID `json:"conversation_id"`
+	ConversationName string    `json:"conversation_name"`
+	Content          string    `json:"content"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 type privateChatUsersResponse struct {
 	Message map[string]map[uuid.UUID][]privateChatMessage `json:"message"`
+	Drafts  []draftResponse                               `json:"drafts"`
 }
 
 // @Summary     Get user messages
@@ -92,6 +107,8 @@ func (r *messageRoutes) getMessages(c *gin.Context) {
 				MessageFrom: msgVal.MessageFrom,
 				MessageTo:   msgVal.MessageTo,
 				Content:     msgVal.Content,
+				Format:      string(msgVal.Format),
+				Entities:    msgVal.Entities,
 				CreatedAt:   msgVal.CreatedAt,
 				UpdatedAt:   msgVal.UpdatedAt,
 				DeletedAt:   msgVal.DeletedAt,
@@ -102,6 +119,17 @@ func (r *messageRoutes) getMessages(c *gin.Context) {
 		res.Message["friendId"] = innerMap
 	}
 
+	res.Drafts = []draftResponse{}
+	for _, draft := range msgs.Drafts {
+		res.Drafts = append(res.Drafts, draftResponse{
+			ConversationType: string(draft.ConversationType),
+			ConversationId:   draft.ConversationId,
+			ConversationName: draft.ConversationName,
+			Content:          draft.Content,
+			UpdatedAt:        draft.UpdatedAt,
+		})
+	}
+
 	c.JSON(http.StatusOK, res)
 }
 
@@ -158,6 +186,8 @@ func (r *messageRoutes) getMessagesByFriend(c *gin.Context) {
 			MessageFrom: msg.MessageFrom,
 			MessageTo:   msg.MessageTo,
 			Content:     msg.Content,
+			Format:      string(msg.Format),
+			Entities:    msg.Entities,
 			CreatedAt:   msg.CreatedAt,
 			UpdatedAt:   msg.UpdatedAt,
 			DeletedAt:   msg.DeletedAt,
@@ -172,12 +202,15 @@ func (r *messageRoutes) getMessagesByFriend(c *gin.Context) {
 }
 
 type groupChatMessage struct {
-	GroupId   uuid.UUID `json:"id"`
-	MessageId uint64    `json:"message_id"`
-	UserId    uuid.UUID `json:"user_id"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
-	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	GroupId   uuid.UUID              `json:"id"`
+	MessageId uint64                 `json:"message_id"`
+	UserId    uuid.UUID              `json:"user_id"`
+	Content   string                 `json:"content"`
+	Format    string                 `json:"format"`
+	Entities  []entity.MessageEntity `json:"entities,omitempty"`
+	PinnedAt  *time.Time             `json:"pinned_at,omitempty"`
+	CreatedAt time.Time              `json:"created_at,omitempty"`
+	UpdatedAt time.Time              `json:"updated_at,omitempty"`
 }
 
 type getMessagesByGroupName struct {
@@ -191,7 +224,8 @@ type getMessagesByGroupName struct {
 // @Accept      json
 // @Produce     json
 // @Security OAuth2Application
-// @Param        groupName    query     string  false  "groupName search by group"
+// @Param        groupId    query     string  false  "group id"
+// @Param        groupName    query     string  false  "deprecated, use groupId"
 // @Success     200 {object} getMessagesByGroupName
 // @Failure     400 {object} response
 // @Failure     500 {object} response
@@ -201,14 +235,17 @@ func (r *messageRoutes) getMessagesByGroupChat(c *gin.Context) {
 	groupName := c.Query("groupName")
 	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
 
-	if groupName == "" {
+	groupId, err := queryGroupId(c)
+	if err != nil || (groupId == uuid.Nil && groupName == "") {
 		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	deprecatedGroupName(c, groupId, groupName)
 
 	msgs, err := r.m.GetMessagesByGroupChat(
 		c.Request.Context(),
 		entity.GroupChatMsgRequest{
+			GroupId:   groupId,
 			GroupName: groupName,
 			UserName:  authPayload.Username,
 		},
@@ -217,7 +254,7 @@ func (r *messageRoutes) getMessagesByGroupChat(c *gin.Context) {
 	if err != nil {
 		unwrapedErr := errors.Unwrap(err)
 		errRepo := errors.Unwrap(unwrapedErr)
-		if errRepo == gorm.ErrRecordNotFound || errRepo == repo.UserNotMemberErr {
+		if errRepo == gorm.ErrRecordNotFound || errRepo == repo.UserNotMemberErr || errRepo == repo.AmbiguousGroupNameErr {
 			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
 			return
 		}
@@ -234,6 +271,9 @@ func (r *messageRoutes) getMessagesByGroupChat(c *gin.Context) {
 			MessageId: msg.MessageId,
 			UserId:    msg.UserId,
 			Content:   msg.Content,
+			Format:    string(msg.Format),
+			Entities:  msg.Entities,
+			PinnedAt:  msg.PinnedAt,
 			CreatedAt: msg.CreatedAt,
 			UpdatedAt: msg.UpdatedAt,
 		})
@@ -244,3 +284,185 @@ func (r *messageRoutes) getMessagesByGroupChat(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, res)
 }
+
+type exportJobResponse struct {
+	Id           uuid.UUID `json:"id"`
+	Status       string    `json:"status"`
+	FileName     string    `json:"file_name"`
+	DownloadLink string    `json:"download_link"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// exportResponseWriter set header file download ketika byte pertama ditulis,
+// sehingga response 202 untuk export di background tidak ikut menjadi attachment
+type exportResponseWriter struct {
+	c           *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.fileName))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+// @Summary     Export private chat / group chat history
+// @Description    Export full history as json lines, plain text or html. Large conversations are exported in background and return a download link (max 100000 messages / 32MB)
+// @ID          exportMessages
+// @Tags  	    messages
+// @Produce     json
+// @Produce     plain
+// @Produce     html
+// @Security OAuth2Application
+// @Param        friendUsername    query     string  false  "export private chat with friendUsername"
+// @Param        groupId    query     string  false  "export group chat with groupId"
+// @Param        groupName    query     string  false  "deprecated, use groupId"
+// @Param        format    query     string  false  "json, txt or html (default json)"
+// @Success     200 {string} string
+// @Success     202 {object} exportJobResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/messages/export [get]
+// Author: https://github.com/lintang-b-s
+func (r *messageRoutes) exportMessages(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	groupId, err := queryGroupId(c)
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid group id")
+		return
+	}
+	deprecatedGroupName(c, groupId, c.Query("groupName"))
+
+	req := entity.ExportMessagesRequest{
+		Username:       authPayload.Username,
+		FriendUsername: c.Query("friendUsername"),
+		GroupId:        groupId,
+		GroupName:      c.Query("groupName"),
+		Format:         entity.ExportFormat(c.DefaultQuery("format", string(entity.ExportFormatJSON))),
+	}
+
+	w := &exportResponseWriter{
+		c:           c,
+		contentType: usecase.ExportContentType(req.Format),
+		fileName:    usecase.ExportFileName(req),
+	}
+	job, err := r.m.ExportMessages(c.Request.Context(), req, w)
+	if err != nil {
+		if w.started {
+			// response sudah terkirim sebagian, tidak bisa mengirim error response lagi
+			r.l.Error(err, "http - v1 - exportMessages")
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		errRepo := errors.Unwrap(unwrapedErr)
+		if unwrapedErr == usecase.InvalidExportFormatErr || unwrapedErr == usecase.InvalidExportRequestErr ||
+			unwrapedErr == usecase.ExportTooLargeErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+		if errRepo == gorm.ErrRecordNotFound || errRepo == repo.UserNotMemberErr || errRepo == repo.AmbiguousGroupNameErr {
+			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+			return
+		}
+
+		r.l.Error("http - v1- exportMessages")
+		ErrorResponse(c, http.StatusInternalServerError, "exportMessages service problems: "+err.Error())
+		return
+	}
+
+	if job.Status == entity.ExportJobStatusPending {
+		c.JSON(http.StatusAccepted, exportJobResponse{
+			Id:           job.Id,
+			Status:       string(job.Status),
+			FileName:     job.FileName,
+			DownloadLink: job.DownloadLink,
+			CreatedAt:    job.CreatedAt,
+		})
+		return
+	}
+
+	if !w.started {
+		// percakapan kosong, tetap kirim file kosong
+		w.Write(nil)
+	}
+}
+
+// @Summary     Download conversation export
+// @Description    Download the result of a background export, returns 202 while the export is still running
+// @ID          downloadExport
+// @Tags  	    messages
+// @Produce     json
+// @Produce     plain
+// @Produce     html
+// @Security OAuth2Application
+// @Param        jobId    path     string  true  "export job id"
+// @Success     200 {string} string
+// @Success     202 {object} exportJobResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/messages/export/{jobId} [get]
+// Author: https://github.com/lintang-b-s
+func (r *messageRoutes) downloadExport(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	jobId, err := uuid.Parse(c.Param("jobId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid export job id")
+		return
+	}
+
+	job, data, err := r.m.GetExportJob(
+		c.Request.Context(),
+		entity.GetExportJobRequest{
+			Id:       jobId,
+			Username: authPayload.Username,
+		},
+	)
+	if err != nil {
+		unwrapedErr := errors.Unwrap(err)
+		errRepo := errors.Unwrap(unwrapedErr)
+		if unwrapedErr == usecase.ExportJobForbiddenErr {
+			ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+			return
+		}
+		if errRepo == redisRepo.ExportJobNotFoundErr {
+			ErrorResponse(c, http.StatusNotFound, errRepo.Error())
+			return
+		}
+
+		r.l.Error("http - v1- downloadExport")
+		ErrorResponse(c, http.StatusInternalServerError, "downloadExport service problems: "+err.Error())
+		return
+	}
+
+	if job.Status == entity.ExportJobStatusPending {
+		c.JSON(http.StatusAccepted, exportJobResponse{
+			Id:           job.Id,
+			Status:       string(job.Status),
+			FileName:     job.FileName,
+			DownloadLink: job.DownloadLink,
+			CreatedAt:    job.CreatedAt,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	c.Data(http.StatusOK, usecase.ExportContentType(job.Format), data)
+}
+
+// queryGroupId parse query param groupId, uuid.Nil jika tidak ada
+func queryGroupId(c *gin.Context) (uuid.UUID, error) {
+	if c.Query("groupId") == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(c.Query("groupId"))
+}
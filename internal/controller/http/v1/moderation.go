@@ -0,0 +1,380 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"github.com/lintangbs/chat-be/pkg/logger"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+type moderationRoutes struct {
+	m   usecase.Moderation
+	l   logger.Interface
+	jwt jwt.JwtTokenMaker
+}
+
+func newModerationRoutes(handler *gin.RouterGroup, m usecase.Moderation, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
+	r := &moderationRoutes{m, l, jwt}
+
+	h := handler.Group("/reports").Use(api.AuthMiddleware(r.jwt, suspension))
+	{
+		h.POST("", r.createReport)
+	}
+
+	admin := handler.Group("/admin").Use(api.AuthMiddleware(r.jwt, suspension))
+	{
+		admin.GET("/reports", r.getReports)
+		admin.PUT("/reports/:reportId", r.resolveReport)
+		admin.PUT("/messages/hide", r.hideMessage)
+		admin.PUT("/users/:username/suspend", r.suspendUser)
+	}
+}
+
+type createReportRequest struct {
+	ReportedUsername string    `json:"reported_username"`
+	ConversationType string    `json:"conversation_type"`
+	MessageId        uint64    `json:"message_id"`
+	GroupId          uuid.UUID `json:"group_id"`
+	GroupName        string    `json:"group_name"` // deprecated, pakai group_id
+	Reason           string    `json:"reason" binding:"required"`
+}
+
+type reportResponse struct {
+	Id               uuid.UUID `json:"id"`
+	ReporterId       uuid.UUID `json:"reporter_id"`
+	ReportedUserId   uuid.UUID `json:"reported_user_id"`
+	ConversationType string    `json:"conversation_type,omitempty"`
+	MessageId        uint64    `json:"message_id,omitempty"`
+	GroupId          uuid.UUID `json:"group_id,omitempty"`
+	Reason           string    `json:"reason"`
+	Status           string    `json:"status"`
+	ResolvedBy       uuid.UUID `json:"resolved_by,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func newReportResponse(report entity.Report) reportResponse {
+	return reportResponse{
+		Id:               report.Id,
+		ReporterId:       report.ReporterId,
+		ReportedUserId:   report.ReportedUserId,
+		ConversationType: string(report.ConversationType),
+		MessageId:        report.MessageId,
+		GroupId:          report.GroupId,
+		Reason:           report.Reason,
+		Status:           string(report.Status),
+		ResolvedBy:       report.ResolvedBy,
+		CreatedAt:        report.CreatedAt,
+		UpdatedAt:        report.UpdatedAt,
+	}
+}
+
+// @Summary     report message or user
+// @Description    report a private/group message (conversation_type & message_id) or a user (reported_username)
+// @ID          createReport
+// @Tags  	    moderation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body createReportRequest true "report message or user"
+// @Success     201 {object} reportResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/reports [post]
+// Author: https://github.com/lintang-b-s
+func (r *moderationRoutes) createReport(c *gin.Context) {
+	var request createReportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - createReport")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+	deprecatedGroupName(c, request.GroupId, request.GroupName)
+
+	report, err := r.m.CreateReport(
+		c.Request.Context(),
+		entity.CreateReportReqUc{
+			ReporterUsername: authPayload.Username,
+			ReportedUsername: request.ReportedUsername,
+			ConversationType: entity.ConversationType(request.ConversationType),
+			MessageId:        request.MessageId,
+			GroupId:          request.GroupId,
+			GroupName:        request.GroupName,
+			Reason:           request.Reason,
+		},
+	)
+	if err != nil {
+		unwrapedErr := errors.Unwrap(err)
+		errRepo := errors.Unwrap(unwrapedErr)
+		if unwrapedErr == usecase.InvalidReportErr || unwrapedErr == usecase.InvalidConversationErr ||
+			unwrapedErr == usecase.CannotReportYourselfErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+		if unwrapedErr == usecase.NotParticipantErr {
+			ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+			return
+		}
+		if errRepo == gorm.ErrRecordNotFound || errRepo == repo.UserNotMemberErr || errRepo == repo.AmbiguousGroupNameErr {
+			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+			return
+		}
+		if unwrapedErr == usecase.GroupIdRequiredErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- createReport")
+		ErrorResponse(c, http.StatusInternalServerError, "createReport service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, newReportResponse(report))
+}
+
+type getReportsResponse struct {
+	Reports []reportResponse `json:"reports"`
+}
+
+// @Summary     get moderation queue
+// @Description    admin only, get reports by status (default open)
+// @ID          getReports
+// @Tags  	    moderation
+// @Produce     json
+// @Security OAuth2Application
+// @Param        status    query     string  false  "open, resolved or dismissed"
+// @Success     200 {object} getReportsResponse
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/admin/reports [get]
+// Author: https://github.com/lintang-b-s
+func (r *moderationRoutes) getReports(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	reports, err := r.m.GetReports(
+		c.Request.Context(),
+		entity.GetReportsReqUc{
+			AdminUsername: authPayload.Username,
+			Status:        entity.ReportStatus(c.Query("status")),
+		},
+	)
+	if err != nil {
+		if r.adminError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- getReports")
+		ErrorResponse(c, http.StatusInternalServerError, "getReports service problems: "+err.Error())
+		return
+	}
+
+	res := getReportsResponse{Reports: []reportResponse{}}
+	for _, report := range reports {
+		res.Reports = append(res.Reports, newReportResponse(report))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+type resolveReportRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// @Summary     resolve report
+// @Description    admin only, close an open report as resolved or dismissed
+// @ID          resolveReport
+// @Tags  	    moderation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param        reportId    path     string  true  "report id"
+// @Param       request body resolveReportRequest true "resolved or dismissed"
+// @Success     200 {object} reportResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/admin/reports/{reportId} [put]
+// Author: https://github.com/lintang-b-s
+func (r *moderationRoutes) resolveReport(c *gin.Context) {
+	var request resolveReportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - resolveReport")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	reportId, err := uuid.Parse(c.Param("reportId"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid report id")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	report, err := r.m.ResolveReport(
+		c.Request.Context(),
+		entity.ResolveReportReqUc{
+			AdminUsername: authPayload.Username,
+			ReportId:      reportId,
+			Status:        entity.ReportStatus(request.Status),
+		},
+	)
+	if err != nil {
+		if r.adminError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		errRepo := errors.Unwrap(unwrapedErr)
+		if unwrapedErr == usecase.InvalidReportStatusErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+		if errRepo == repo.ReportAlreadyClosedErr {
+			ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+			return
+		}
+
+		r.l.Error("http - v1- resolveReport")
+		ErrorResponse(c, http.StatusInternalServerError, "resolveReport service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, newReportResponse(report))
+}
+
+type moderationResponse struct {
+	ResponseMessage string `json:"response_message"`
+}
+
+type hideMessageRequest struct {
+	ConversationType string    `json:"conversation_type" binding:"required"`
+	MessageId        uint64    `json:"message_id" binding:"required"`
+	GroupId          uuid.UUID `json:"group_id"`
+}
+
+// @Summary     hide message
+// @Description    admin only, hide (soft delete) a private or group message
+// @ID          hideMessage
+// @Tags  	    moderation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body hideMessageRequest true "message to hide"
+// @Success     200 {object} moderationResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/admin/messages/hide [put]
+// Author: https://github.com/lintang-b-s
+func (r *moderationRoutes) hideMessage(c *gin.Context) {
+	var request hideMessageRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - hideMessage")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.m.HideMessage(
+		c.Request.Context(),
+		entity.HideMessageReqUc{
+			AdminUsername:    authPayload.Username,
+			ConversationType: entity.ConversationType(request.ConversationType),
+			MessageId:        request.MessageId,
+			GroupId:          request.GroupId,
+		},
+	)
+	if err != nil {
+		if r.adminError(c, err) {
+			return
+		}
+		unwrapedErr := errors.Unwrap(err)
+		if unwrapedErr == usecase.InvalidConversationErr {
+			ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+			return
+		}
+
+		r.l.Error("http - v1- hideMessage")
+		ErrorResponse(c, http.StatusInternalServerError, "hideMessage service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, moderationResponse{ResponseMessage: "message hidden"})
+}
+
+type suspendUserRequest struct {
+	Suspend bool   `json:"suspend"`
+	Reason  string `json:"reason"`
+}
+
+// @Summary     suspend user
+// @Description    admin only, suspend or unsuspend a user. Suspending revokes refresh tokens and closes all websocket connections
+// @ID          suspendUser
+// @Tags  	    moderation
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param        username    path     string  true  "username"
+// @Param       request body suspendUserRequest true "suspend or unsuspend"
+// @Success     200 {object} moderationResponse
+// @Failure     400 {object} response
+// @Failure     403 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/admin/users/{username}/suspend [put]
+// Author: https://github.com/lintang-b-s
+func (r *moderationRoutes) suspendUser(c *gin.Context) {
+	var request suspendUserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - suspendUser")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	err := r.m.SuspendUser(
+		c.Request.Context(),
+		entity.SuspendUserReqUc{
+			AdminUsername: authPayload.Username,
+			Username:      c.Param("username"),
+			Suspend:       request.Suspend,
+			Reason:        request.Reason,
+		},
+	)
+	if err != nil {
+		if r.adminError(c, err) {
+			return
+		}
+
+		r.l.Error("http - v1- suspendUser")
+		ErrorResponse(c, http.StatusInternalServerError, "suspendUser service problems: "+err.Error())
+		return
+	}
+
+	if request.Suspend {
+		c.JSON(http.StatusOK, moderationResponse{ResponseMessage: "user suspended"})
+		return
+	}
+	c.JSON(http.StatusOK, moderationResponse{ResponseMessage: "user unsuspended"})
+}
+
+// adminError menulis response untuk error yang umum di semua endpoint admin, return true jika error sudah ditangani
+func (r *moderationRoutes) adminError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	if unwrapedErr == usecase.NotAdminErr {
+		ErrorResponse(c, http.StatusForbidden, unwrapedErr.Error())
+		return true
+	}
+	if errRepo == gorm.ErrRecordNotFound {
+		ErrorResponse(c, http.StatusBadRequest, errRepo.Error())
+		return true
+	}
+	return false
+}
@@ -24,7 +24,8 @@ import (
 // @host        localhost:8080
 // @BasePath    /v1
 func NewRouter(handler *gin.Engine, l logger.Interface, a usecase.Auth, ws usecase.Websocket, cont usecase.Contact, jwt jwt.JwtTokenMaker,
-	mus usecase.Message, g usecase.Group) {
+	mus usecase.Message, g usecase.Group, mod usecase.Moderation, ch usecase.Channel,
+	cs usecase.ConversationSetting, b usecase.Block, up usecase.UserProfile) {
 	// Options
 	handler.Use(gin.Logger())
 	handler.Use(gin.Recovery())
@@ -44,8 +45,13 @@ func NewRouter(handler *gin.Engine, l logger.Interface, a usecase.Auth, ws useca
 	{
 		newAuthRoutes(h, a, l)
 		NewWebsocketRoutes(h, ws, l)
-		NewContactRoutes(h, cont, l, jwt)
-		NewMessageRoutes(h, mus, l, jwt)
-		newGroupRoutes(h, g, l, jwt)
+		NewContactRoutes(h, cont, l, jwt, a)
+		NewMessageRoutes(h, mus, l, jwt, a)
+		newGroupRoutes(h, g, l, jwt, a)
+		newModerationRoutes(h, mod, l, jwt, a)
+		newChannelRoutes(h, ch, l, jwt, a)
+		newConversationSettingRoutes(h, cs, l, jwt, a)
+		newBlockRoutes(h, b, l, jwt, a)
+		newUserRoutes(h, up, l, jwt, a)
 	}
 }
@@ -0,0 +1,670 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	api "github.com/lintangbs/chat-be/internal/middleware"
+	"github.com/lintangbs/chat-be/internal/usecase"
+	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"github.com/lintangbs/chat-be/pkg/logger"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type userRoutes struct {
+	u   usecase.UserProfile
+	l   logger.Interface
+	jwt jwt.JwtTokenMaker
+}
+
+func newUserRoutes(handler *gin.RouterGroup, u usecase.UserProfile, l logger.Interface, jwt jwt.JwtTokenMaker, suspension api.SuspensionChecker) {
+	r := &userRoutes{u, l, jwt}
+
+	h := handler.Group("/users").Use(api.AuthMiddleware(r.jwt, suspension))
+	{
+		h.GET("/search", r.searchUsers)
+		h.GET("/me/privacy", r.getPrivacySettings)
+		h.PUT("/me/privacy", r.updatePrivacySettings)
+		h.GET("/:username/profile", r.getProfile)
+		h.GET("/:username/mutual", r.getMutual)
+		h.PATCH("/me", r.updateProfile)
+		h.PUT("/me/avatar", r.uploadAvatar)
+		h.DELETE("/me/avatar", r.removeAvatar)
+		h.GET("/me/status", r.getStatus)
+		h.PUT("/me/status", r.setStatus)
+		h.DELETE("/me/status", r.clearStatus)
+	}
+
+	// avatar tanpa auth agar bisa langsung dipakai di tag img client
+	handler.Group("/users").GET("/:username/avatar", r.getAvatar)
+}
+
+type userSearchResultResponse struct {
+	UserId      uuid.UUID `json:"user_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	AvatarUrl   string    `json:"avatar_url"`
+}
+
+type userSearchResponse struct {
+	Users []userSearchResultResponse `json:"users"`
+}
+
+type publicProfileResponse struct {
+	UserId      uuid.UUID  `json:"user_id"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"display_name"`
+	AvatarUrl   string     `json:"avatar_url"`
+	Bio         string     `json:"bio"`
+	Location    string     `json:"location"`
+	Website     string     `json:"website"`
+	LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`
+	IsContact   bool       `json:"is_contact"`
+}
+
+func newPublicProfileResponse(profile entity.PublicProfile) publicProfileResponse {
+	return publicProfileResponse{
+		UserId:      profile.UserId,
+		Username:    profile.Username,
+		DisplayName: profile.DisplayName,
+		AvatarUrl:   profile.AvatarUrl,
+		Bio:         profile.Bio,
+		Location:    profile.Location,
+		Website:     profile.Website,
+		LastSeenAt:  profile.LastSeenAt,
+		IsContact:   profile.IsContact,
+	}
+}
+
+type userStatusRequest struct {
+	Presence  string `json:"presence"`   // available / away / busy / dnd, kosong berarti available
+	Text      string `json:"text"`       // teks status bebas, maksimal 100 karakter
+	ExpiresIn int64  `json:"expires_in"` // detik sampai status kembali ke available (maksimal 30 hari), 0 berarti tidak kadaluarsa
+}
+
+type userStatusResponse struct {
+	Presence  string     `json:"presence"`
+	Text      string     `json:"text"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Idle      bool       `json:"idle"` // otomatis away karena user tidak aktif
+}
+
+type userMessageResponse struct {
+	ResponseMessage string `json:"message"`
+}
+
+func newUserStatusResponse(status entity.UserStatus) userStatusResponse {
+	return userStatusResponse{
+		Presence:  string(status.Presence),
+		Text:      status.Text,
+		ExpiresAt: status.ExpiresAt,
+		Idle:      status.Idle,
+	}
+}
+
+// updateProfileRequest field null / tidak dikirim berarti tidak diubah
+type updateProfileRequest struct {
+	DisplayName *string `json:"display_name"`
+	Bio         *string `json:"bio"`
+	Location    *string `json:"location"`
+	Website     *string `json:"website"` // url http / https, string kosong menghapus website
+}
+
+type privacyExceptionsRequest struct {
+	AlwaysShare []string `json:"always_share"` // username yang selalu boleh melihat
+	NeverShare  []string `json:"never_share"`  // username yang tidak pernah boleh melihat
+}
+
+type privacySettingsRequest struct {
+	Discoverable           string                    `json:"discoverable"`             // everyone / contacts / nobody, kosong berarti tidak diubah
+	OnlineStatus           string                    `json:"online_status"`            // everyone / contacts / nobody, kosong berarti tidak diubah
+	LastSeen               string                    `json:"last_seen"`                // everyone / contacts / nobody, kosong berarti tidak diubah
+	OnlineStatusExceptions *privacyExceptionsRequest `json:"online_status_exceptions"` // null berarti tidak diubah
+	LastSeenExceptions     *privacyExceptionsRequest `json:"last_seen_exceptions"`     // null berarti tidak diubah
+}
+
+type privacyExceptionsResponse struct {
+	AlwaysShare []string `json:"always_share"`
+	NeverShare  []string `json:"never_share"`
+}
+
+type privacySettingsResponse struct {
+	Discoverable           string                    `json:"discoverable"`
+	OnlineStatus           string                    `json:"online_status"`
+	LastSeen               string                    `json:"last_seen"`
+	OnlineStatusExceptions privacyExceptionsResponse `json:"online_status_exceptions"`
+	LastSeenExceptions     privacyExceptionsResponse `json:"last_seen_exceptions"`
+	UpdatedAt              *time.Time                `json:"updated_at,omitempty"`
+}
+
+func newPrivacyExceptionsResponse(exceptions []entity.PrivacyException) privacyExceptionsResponse {
+	res := privacyExceptionsResponse{AlwaysShare: []string{}, NeverShare: []string{}}
+	for _, exception := range exceptions {
+		if exception.Allow {
+			res.AlwaysShare = append(res.AlwaysShare, exception.Username)
+			continue
+		}
+		res.NeverShare = append(res.NeverShare, exception.Username)
+	}
+	return res
+}
+
+func newPrivacySettingsResponse(settings entity.PrivacySettings) privacySettingsResponse {
+	res := privacySettingsResponse{
+		Discoverable:           string(settings.Discoverable),
+		OnlineStatus:           string(settings.OnlineStatus),
+		LastSeen:               string(settings.LastSeen),
+		OnlineStatusExceptions: newPrivacyExceptionsResponse(settings.OnlineStatusExceptions),
+		LastSeenExceptions:     newPrivacyExceptionsResponse(settings.LastSeenExceptions),
+	}
+	if !settings.UpdatedAt.IsZero() {
+		res.UpdatedAt = &settings.UpdatedAt
+	}
+	return res
+}
+
+func (p *privacyExceptionsRequest) toReqUc() *entity.PrivacyExceptionsReqUc {
+	if p == nil {
+		return nil
+	}
+	return &entity.PrivacyExceptionsReqUc{AlwaysShare: p.AlwaysShare, NeverShare: p.NeverShare}
+}
+
+// userError mapping error user profile ke http status, return true jika error sudah di handle
+func (r *userRoutes) userError(c *gin.Context, err error) bool {
+	unwrapedErr := errors.Unwrap(err)
+	errRepo := errors.Unwrap(unwrapedErr)
+	switch {
+	case unwrapedErr == usecase.SearchQueryTooShortErr || unwrapedErr == usecase.InvalidPrivacySettingErr ||
+		unwrapedErr == usecase.InvalidDisplayNameErr || unwrapedErr == usecase.InvalidBioErr ||
+		unwrapedErr == usecase.InvalidLocationErr || unwrapedErr == usecase.InvalidWebsiteErr ||
+		unwrapedErr == usecase.InvalidAvatarTypeErr || unwrapedErr == usecase.InvalidPrivacyExceptionErr ||
+		unwrapedErr == usecase.InvalidUserStatusErr || unwrapedErr == usecase.UserStatusTextTooLongErr ||
+		unwrapedErr == usecase.InvalidStatusExpiryErr || unwrapedErr == usecase.MutualWithYourselfErr:
+		ErrorResponse(c, http.StatusBadRequest, unwrapedErr.Error())
+	case unwrapedErr == usecase.AvatarTooLargeErr:
+		ErrorResponse(c, http.StatusRequestEntityTooLarge, unwrapedErr.Error())
+	case errRepo == gorm.ErrRecordNotFound:
+		ErrorResponse(c, http.StatusNotFound, "User not found: "+errRepo.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary     search users
+// @Description     search users by username or display name prefix and similarity (trigram).
+// @Description     Users that are not discoverable by the logged in user are not returned
+// @ID          searchUsers
+// @Tags  	    user
+// @Produce     json
+// @Security OAuth2Application
+// @Param       q query string true "search query (min 2 characters)"
+// @Param       limit query int false "max users (default 20, max 50)"
+// @Success     200 {object} userSearchResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/search [get]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) searchUsers(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		ErrorResponse(c, http.StatusBadRequest, "invalid limit")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	users, err := r.u.SearchUsers(c.Request.Context(), entity.UserSearchReqUc{
+		UserName: authPayload.Username,
+		Query:    c.Query("q"),
+		Limit:    limit,
+	})
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- searchUsers")
+		ErrorResponse(c, http.StatusInternalServerError, "searchUsers service problems: "+err.Error())
+		return
+	}
+
+	res := userSearchResponse{Users: []userSearchResultResponse{}}
+	for _, user := range users {
+		res.Users = append(res.Users, userSearchResultResponse{
+			UserId:      user.UserId,
+			Username:    user.Username,
+			DisplayName: user.DisplayName,
+			AvatarUrl:   user.AvatarUrl,
+		})
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary     get public profile
+// @Description     get the public profile of a user. last_seen_at is omitted when hidden by the user's privacy settings
+// @ID          getPublicProfile
+// @Tags  	    user
+// @Produce     json
+// @Security OAuth2Application
+// @Param       username path string true "username"
+// @Success     200 {object} publicProfileResponse
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/{username}/profile [get]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) getProfile(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	profile, err := r.u.GetProfile(c.Request.Context(), entity.PublicProfileReqUc{
+		UserName:        authPayload.Username,
+		ProfileUsername: c.Param("username"),
+	})
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getProfile")
+		ErrorResponse(c, http.StatusInternalServerError, "getProfile service problems: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, newPublicProfileResponse(profile))
+}
+
+type mutualGroupResponse struct {
+	Id        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	AvatarUrl string    `json:"avatar_url"`
+}
+
+type mutualResponse struct {
+	Groups   []mutualGroupResponse      `json:"groups"`
+	Contacts []userSearchResultResponse `json:"contacts"`
+}
+
+// @Summary     get mutual groups and contacts
+// @Description     get the groups and contacts the logged in user shares with another user.
+// @Description     Empty when the other user has blocked the logged in user
+// @ID          getMutual
+// @Tags  	    user
+// @Produce     json
+// @Security OAuth2Application
+// @Param       username path string true "username"
+// @Success     200 {object} mutualResponse
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/{username}/mutual [get]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) getMutual(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	mutual, err := r.u.GetMutual(c.Request.Context(), entity.PublicProfileReqUc{
+		UserName:        authPayload.Username,
+		ProfileUsername: c.Param("username"),
+	})
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error(err, "http - v1 - getMutual")
+		ErrorResponse(c, http.StatusInternalServerError, "getMutual service problems: "+err.Error())
+		return
+	}
+
+	res := mutualResponse{
+		Groups:   make([]mutualGroupResponse, 0, len(mutual.Groups)),
+		Contacts: make([]userSearchResultResponse, 0, len(mutual.Contacts)),
+	}
+	for _, group := range mutual.Groups {
+		res.Groups = append(res.Groups, mutualGroupResponse{Id: group.Id, Name: group.Name, AvatarUrl: group.AvatarUrl})
+	}
+	for _, contact := range mutual.Contacts {
+		res.Contacts = append(res.Contacts, userSearchResultResponse{
+			UserId:      contact.UserId,
+			Username:    contact.Username,
+			DisplayName: contact.DisplayName,
+			AvatarUrl:   contact.AvatarUrl,
+		})
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// @Summary     get privacy settings
+// @Description     get privacy settings of the logged in user
+// @ID          getPrivacySettings
+// @Tags  	    user
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} privacySettingsResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me/privacy [get]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) getPrivacySettings(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	settings, err := r.u.GetPrivacySettings(c.Request.Context(), authPayload.Username)
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getPrivacySettings")
+		ErrorResponse(c, http.StatusInternalServerError, "getPrivacySettings service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newPrivacySettingsResponse(settings))
+}
+
+// @Summary     update privacy settings
+// @Description     update who can find the logged in user in search (discoverable), who can see online status (online_status)
+// @Description     and who can see last seen (last_seen). Allowed values are everyone, contacts and nobody.
+// @Description     online_status_exceptions / last_seen_exceptions replace all exceptions of the setting when sent:
+// @Description     always_share users can always see it, never_share users can never see it
+// @ID          updatePrivacySettings
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body privacySettingsRequest true "privacy settings"
+// @Success     200 {object} privacySettingsResponse
+// @Failure     400 {object} response
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me/privacy [put]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) updatePrivacySettings(c *gin.Context) {
+	var request privacySettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - updatePrivacySettings")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	settings, err := r.u.UpdatePrivacySettings(c.Request.Context(), entity.PrivacySettingsReqUc{
+		UserName:               authPayload.Username,
+		Discoverable:           entity.PrivacyVisibility(request.Discoverable),
+		OnlineStatus:           entity.PrivacyVisibility(request.OnlineStatus),
+		LastSeen:               entity.PrivacyVisibility(request.LastSeen),
+		OnlineStatusExceptions: request.OnlineStatusExceptions.toReqUc(),
+		LastSeenExceptions:     request.LastSeenExceptions.toReqUc(),
+	})
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- updatePrivacySettings")
+		ErrorResponse(c, http.StatusInternalServerError, "updatePrivacySettings service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newPrivacySettingsResponse(settings))
+}
+
+// @Summary     update profile
+// @Description     update profile of the logged in user. Fields that are null or not sent are not changed.
+// @Description     The new profile is pushed to online contacts with a profile_updated websocket message
+// @ID          updateProfile
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body updateProfileRequest true "profile fields"
+// @Success     200 {object} publicProfileResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me [patch]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) updateProfile(c *gin.Context) {
+	var request updateProfileRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - updateProfile")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	profile, err := r.u.UpdateProfile(c.Request.Context(), entity.UpdateProfileReqUc{
+		UserName:    authPayload.Username,
+		DisplayName: request.DisplayName,
+		Bio:         request.Bio,
+		Location:    request.Location,
+		Website:     request.Website,
+	})
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- updateProfile")
+		ErrorResponse(c, http.StatusInternalServerError, "updateProfile service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newPublicProfileResponse(profile))
+}
+
+// avatarMultipartOverhead ukuran maksimal header & boundary multipart di luar file avatar
+const avatarMultipartOverhead = 64 << 10
+
+// @Summary     upload avatar
+// @Description     upload avatar of the logged in user (jpeg, png, gif or webp). avatar_url of the user points to the uploaded avatar.
+// @Description     The new profile is pushed to online contacts with a profile_updated websocket message
+// @ID          uploadAvatar
+// @Tags  	    user
+// @Accept      multipart/form-data
+// @Produce     json
+// @Security OAuth2Application
+// @Param       avatar formData file true "avatar image"
+// @Success     200 {object} publicProfileResponse
+// @Failure     400 {object} response
+// @Failure     413 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me/avatar [put]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) uploadAvatar(c *gin.Context) {
+	maxSize := r.u.AvatarMaxSize()
+	if maxSize > 0 {
+		// batasi body request sebelum multipart di parse, ditambah overhead header multipart
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+avatarMultipartOverhead)
+	}
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		r.l.Error(err, "http - v1 - uploadAvatar")
+		if strings.Contains(err.Error(), "request body too large") {
+			ErrorResponse(c, http.StatusRequestEntityTooLarge, usecase.AvatarTooLargeErr.Error())
+			return
+		}
+		ErrorResponse(c, http.StatusBadRequest, "avatar file is required")
+		return
+	}
+	if maxSize > 0 && fileHeader.Size > maxSize {
+		ErrorResponse(c, http.StatusRequestEntityTooLarge, usecase.AvatarTooLargeErr.Error())
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		r.l.Error(err, "http - v1 - uploadAvatar")
+		ErrorResponse(c, http.StatusBadRequest, "invalid avatar file")
+		return
+	}
+	defer file.Close()
+	reader := io.Reader(file)
+	if maxSize > 0 {
+		// 1 byte lebih dari batas supaya file yang terlalu besar tetap ditolak usecase
+		reader = io.LimitReader(file, maxSize+1)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		r.l.Error(err, "http - v1 - uploadAvatar")
+		ErrorResponse(c, http.StatusBadRequest, "invalid avatar file")
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	profile, err := r.u.UploadAvatar(c.Request.Context(), entity.AvatarUploadReqUc{
+		UserName: authPayload.Username,
+		Data:     data,
+	})
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- uploadAvatar")
+		ErrorResponse(c, http.StatusInternalServerError, "uploadAvatar service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newPublicProfileResponse(profile))
+}
+
+// @Summary     remove avatar
+// @Description     remove avatar of the logged in user
+// @ID          removeAvatar
+// @Tags  	    user
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} publicProfileResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me/avatar [delete]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) removeAvatar(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	profile, err := r.u.RemoveAvatar(c.Request.Context(), authPayload.Username)
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- removeAvatar")
+		ErrorResponse(c, http.StatusInternalServerError, "removeAvatar service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newPublicProfileResponse(profile))
+}
+
+// @Summary     get avatar
+// @Description     get the avatar image uploaded by a user. Does not need authorization so it can be used directly as image source
+// @ID          getAvatar
+// @Tags  	    user
+// @Produce     image/jpeg,image/png,image/gif,image/webp
+// @Param       username path string true "username"
+// @Success     200 {file} file
+// @Failure     404 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/{username}/avatar [get]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) getAvatar(c *gin.Context) {
+	avatar, err := r.u.GetAvatar(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getAvatar")
+		ErrorResponse(c, http.StatusInternalServerError, "getAvatar service problems: "+err.Error())
+		return
+	}
+	// url avatar berubah setiap upload (query v) sehingga aman di cache lama
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, avatar.ContentType, avatar.Data)
+}
+
+// @Summary     get status
+// @Description     get status (available, away, busy or dnd and free-text status) of the logged in user
+// @ID          getUserStatus
+// @Tags  	    user
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} userStatusResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me/status [get]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) getStatus(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	status, err := r.u.GetStatus(c.Request.Context(), authPayload.Username)
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- getStatus")
+		ErrorResponse(c, http.StatusInternalServerError, "getStatus service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newUserStatusResponse(status))
+}
+
+// @Summary     set status
+// @Description     set status of the logged in user: presence (available, away, busy or dnd), free-text status and optional expiry in seconds (at most 30 days).
+// @Description     Expired statuses go back to available automatically. The status is sent to contacts in online_status_fanout websocket messages
+// @ID          setUserStatus
+// @Tags  	    user
+// @Accept      json
+// @Produce     json
+// @Security OAuth2Application
+// @Param       request body userStatusRequest true "status"
+// @Success     200 {object} userStatusResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me/status [put]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) setStatus(c *gin.Context) {
+	var request userStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.l.Error(err, "http - v1 - setStatus")
+		ErrorResponse(c, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	// dicek sebelum diubah ke time.Duration supaya tidak overflow
+	if request.ExpiresIn > int64(usecase.MaxStatusExpiry/time.Second) {
+		ErrorResponse(c, http.StatusBadRequest, usecase.InvalidStatusExpiryErr.Error())
+		return
+	}
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	status, err := r.u.SetStatus(c.Request.Context(), entity.UserStatusReqUc{
+		UserName:  authPayload.Username,
+		Presence:  entity.PresenceStatus(request.Presence),
+		Text:      request.Text,
+		ExpiresIn: time.Duration(request.ExpiresIn) * time.Second,
+	})
+	if err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- setStatus")
+		ErrorResponse(c, http.StatusInternalServerError, "setStatus service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, newUserStatusResponse(status))
+}
+
+// @Summary     clear status
+// @Description     clear status of the logged in user (back to available)
+// @ID          clearUserStatus
+// @Tags  	    user
+// @Produce     json
+// @Security OAuth2Application
+// @Success     200 {object} userMessageResponse
+// @Failure     400 {object} response
+// @Failure     500 {object} response
+// @Router      /v1/users/me/status [delete]
+// Author: https://github.com/lintang-b-s
+func (r *userRoutes) clearStatus(c *gin.Context) {
+	authPayload := c.MustGet(api.AuthorizationPayloadKey).(*jwt.Payload)
+
+	if err := r.u.ClearStatus(c.Request.Context(), authPayload.Username); err != nil {
+		if r.userError(c, err) {
+			return
+		}
+		r.l.Error("http - v1- clearStatus")
+		ErrorResponse(c, http.StatusInternalServerError, "clearStatus service problems: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, userMessageResponse{ResponseMessage: "status cleared"})
+}
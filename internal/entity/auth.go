@@ -15,13 +15,17 @@ type UserResponse struct {
 	Id       uuid.UUID      `json:"id"`
 	Username string         `json:"username"`
 	Email    string         `json:"email"`
+	Nickname string         `json:"nickname,omitempty"` // nickname pribadi yang diberikan user ke kontaknya
+	Note     string         `json:"note,omitempty"`     // catatan pribadi user tentang kontaknya
 	Friends  []UserResponse `json:"friends"`
 }
 type GetUser struct {
-	Id             uuid.UUID `json:"id"`
-	Username       string    `json:"username"`
-	Email          string    `json:"email"`
-	HashedPassword string    `json:"hashed_password"`
+	Id             uuid.UUID  `json:"id"`
+	Username       string     `json:"username"`
+	Email          string     `json:"email"`
+	HashedPassword string     `json:"hashed_password"`
+	IsAdmin        bool       `json:"is_admin"`
+	SuspendedAt    *time.Time `json:"suspended_at"`
 }
 
 type LoginUserRequest struct {
@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// BlockedUser user yang diblokir, tidak bisa mengirim private chat, friend request, menambahkan ke group
+// dan melihat status online user yang memblokir
+type BlockedUser struct {
+	UserId    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+// BlockReqUc request block / unblock user di usecase
+type BlockReqUc struct {
+	UserName        string `json:"user_name"`
+	BlockedUsername string `json:"blocked_username"`
+}
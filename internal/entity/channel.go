@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ChannelRole string
+
+const (
+	ChannelRoleOwner      ChannelRole = "owner"
+	ChannelRoleAdmin      ChannelRole = "admin"
+	ChannelRoleSubscriber ChannelRole = "subscriber"
+)
+
+// CanPost hanya owner & admin yang bisa posting di channel
+func (r ChannelRole) CanPost() bool {
+	return r == ChannelRoleOwner || r == ChannelRoleAdmin
+}
+
+// Channel channel broadcast, conversation 1 ke banyak yang read only untuk subscriber
+type Channel struct {
+	Id              uuid.UUID   `json:"id"`
+	Name            string      `json:"name"`
+	Description     string      `json:"description"`
+	OwnerId         uuid.UUID   `json:"owner_id"`
+	SubscriberCount int64       `json:"subscriber_count"`
+	Role            ChannelRole `json:"role,omitempty"` // role user yang login di channel
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// ChannelMessage pesan yang diposting admin di channel
+type ChannelMessage struct {
+	ChannelId uuid.UUID       `json:"channel_id"`
+	MessageId uint64          `json:"message_id"`
+	UserId    uuid.UUID       `json:"user_id"`
+	Content   string          `json:"content"`
+	Format    MessageFormat   `json:"format"`
+	Entities  []MessageEntity `json:"entities,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ChannelMessages array of pesan channel
+type ChannelMessages struct {
+	Messages []ChannelMessage `json:"messages"`
+}
+
+// CreateChannelReqUc request membuat channel di usecase
+type CreateChannelReqUc struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UserName    string `json:"user_name"`
+}
+
+// ChannelReqUc request subscribe / unsubscribe channel di usecase
+type ChannelReqUc struct {
+	ChannelId uuid.UUID `json:"channel_id"`
+	UserName  string    `json:"user_name"`
+}
+
+// ChangeChannelRoleReqUc request promote / demote admin channel di usecase
+type ChangeChannelRoleReqUc struct {
+	ChannelId uuid.UUID   `json:"channel_id"`
+	UserName  string      `json:"user_name"`
+	Member    string      `json:"member"`
+	Role      ChannelRole `json:"role"`
+}
+
+// ChannelMessagesReqUc request history pesan channel di usecase
+type ChannelMessagesReqUc struct {
+	ChannelId uuid.UUID `json:"channel_id"`
+	UserName  string    `json:"user_name"`
+	AfterId   uint64    `json:"after_id"`
+	Limit     int       `json:"limit"`
+}
@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
 type AddFriendRequest struct {
 	MyUsername     string `json:"my_username"`
 	FriendUsername string `json:"friend_username"`
@@ -8,3 +13,55 @@ type AddFriendRequest struct {
 type GetContactRequest struct {
 	MyUsername string `json:"my_username"`
 }
+
+// UpdateContactReqUc request ubah nickname / catatan kontak di usecase, nil berarti tidak diubah
+type UpdateContactReqUc struct {
+	MyUsername     string  `json:"my_username"`
+	FriendUsername string  `json:"friend_username"`
+	Nickname       *string `json:"nickname"`
+	Note           *string `json:"note"`
+}
+
+// ContactDiscoverySalt salt global dari server untuk hash email di address book client,
+// client mengirim hex(sha256(salt + lower(trim(email))))
+type ContactDiscoverySalt struct {
+	Salt string `json:"salt"`
+}
+
+// ContactDiscoveryReqUc request pencarian user dari hash email address book di usecase
+type ContactDiscoveryReqUc struct {
+	MyUsername string   `json:"my_username"`
+	ClientIp   string   `json:"client_ip"`
+	Hashes     []string `json:"hashes"`
+}
+
+// ContactDiscoveryMatch hash email yang cocok dengan user terdaftar
+type ContactDiscoveryMatch struct {
+	Hash        string    `json:"hash"`
+	UserId      uuid.UUID `json:"user_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	AvatarUrl   string    `json:"avatar_url"`
+}
+
+// ContactDiscoveryResult hasil pencarian user dari hash email beserta sisa kuota hash & waktu kuota direset
+type ContactDiscoveryResult struct {
+	Matches        []ContactDiscoveryMatch `json:"matches"`
+	RemainingQuota int64                   `json:"remaining_quota"`
+	QuotaResetAt   time.Time               `json:"quota_reset_at"`
+}
+
+// ContactDiscoveryLimits batas pencarian user dari hash email (dari config) agar tidak bisa dipakai untuk enumerasi user:
+// MaxBatchSize hash per request, MaxHashesPerWindow total hash per user & MaxHashesPerIpWindow total hash per ip dalam Window
+type ContactDiscoveryLimits struct {
+	MaxBatchSize         int
+	MaxHashesPerWindow   int64
+	MaxHashesPerIpWindow int64
+	Window               time.Duration
+}
+
+// UserEmail email user yang hash contact discoverynya perlu dihitung
+type UserEmail struct {
+	UserId uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
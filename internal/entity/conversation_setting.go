@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ConversationSetting setting user di sebuah percakapan (mute & archive)
+type ConversationSetting struct {
+	UserId           uuid.UUID        `json:"user_id"`
+	ConversationType ConversationType `json:"conversation_type"`
+	ConversationId   uuid.UUID        `json:"conversation_id"`   // id friend / id group
+	ConversationName string           `json:"conversation_name"` // username friend / nama group
+	Muted            bool             `json:"muted"`
+	MutedUntil       *time.Time       `json:"muted_until,omitempty"` // nil berarti mute tanpa batas waktu
+	Archived         bool             `json:"archived"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// IsMuted cek apakah percakapan masih di mute pada waktu now
+func (s ConversationSetting) IsMuted(now time.Time) bool {
+	return s.Muted && (s.MutedUntil == nil || s.MutedUntil.After(now))
+}
+
+// ConversationSettingReqUc request mengubah setting percakapan (friend_username atau group_id) di usecase,
+// MutedUntil nil berarti mute tanpa batas waktu
+type ConversationSettingReqUc struct {
+	UserName       string     `json:"user_name"`
+	FriendUsername string     `json:"friend_username"`
+	GroupId        uuid.UUID  `json:"group_id"`
+	MutedUntil     *time.Time `json:"muted_until"`
+}
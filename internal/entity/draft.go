@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type (
+	ConversationType string
+)
+
+const (
+	ConversationTypePrivate ConversationType = "private"
+	ConversationTypeGroup   ConversationType = "group"
+)
+
+// Draft pesan yang belum dikirim user di sebuah percakapan
+type Draft struct {
+	UserId           uuid.UUID        `json:"user_id"`
+	ConversationType ConversationType `json:"conversation_type"`
+	ConversationId   uuid.UUID        `json:"conversation_id"`   // id friend / id group
+	ConversationName string           `json:"conversation_name"` // username friend / nama group
+	Content          string           `json:"content"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
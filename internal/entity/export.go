@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type (
+	ExportFormat    string
+	ExportJobStatus string
+)
+
+const (
+	ExportFormatJSON ExportFormat = "json"
+	ExportFormatText ExportFormat = "txt"
+	ExportFormatHTML ExportFormat = "html"
+
+	ExportJobStatusPending ExportJobStatus = "pending"
+	ExportJobStatusDone    ExportJobStatus = "done"
+	ExportJobStatusFailed  ExportJobStatus = "failed"
+)
+
+// ExportMessagesRequest param di usecase untuk export history percakapan
+// diisi salah satu dari FriendUsername (private chat) atau GroupId (group chat)
+type ExportMessagesRequest struct {
+	Username       string       `json:"username"`
+	FriendUsername string       `json:"friend_username"`
+	GroupId        uuid.UUID    `json:"group_id"`
+	GroupName      string       `json:"group_name"` // deprecated, pakai GroupId
+	Format         ExportFormat `json:"format"`
+}
+
+// ExportMessage satu baris pesan di file export, user id sudah diganti username
+type ExportMessage struct {
+	MessageId uint64    `json:"message_id"`
+	Sender    string    `json:"sender"`
+	Recipient string    `json:"recipient,omitempty"`
+	Group     string    `json:"group,omitempty"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportJob export yang dijalankan di background untuk percakapan yang besar
+type ExportJob struct {
+	Id           uuid.UUID       `json:"id"`
+	Username     string          `json:"username"`
+	Format       ExportFormat    `json:"format"`
+	Status       ExportJobStatus `json:"status"`
+	FileName     string          `json:"file_name"`
+	DownloadLink string          `json:"download_link"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// GetExportJobRequest param di usecase untuk download hasil export
+type GetExportJobRequest struct {
+	Id       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
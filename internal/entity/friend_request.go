@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type FriendRequestStatus string
+
+const (
+	FriendRequestPending   FriendRequestStatus = "pending"
+	FriendRequestAccepted  FriendRequestStatus = "accepted"
+	FriendRequestDeclined  FriendRequestStatus = "declined"
+	FriendRequestCancelled FriendRequestStatus = "cancelled"
+)
+
+// FriendRequest request pertemanan, kontak baru dibuat (dua arah) ketika request di accept
+type FriendRequest struct {
+	Id                uuid.UUID           `json:"id"`
+	SenderId          uuid.UUID           `json:"sender_id"`
+	SenderUsername    string              `json:"sender_username"`
+	RecipientId       uuid.UUID           `json:"recipient_id"`
+	RecipientUsername string              `json:"recipient_username"`
+	Status            FriendRequestStatus `json:"status"`
+	DecidedAt         *time.Time          `json:"decided_at,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+}
+
+// FriendRequests request pertemanan pending milik user, Incoming dikirim ke user & Outgoing dikirim oleh user
+type FriendRequests struct {
+	Incoming []FriendRequest `json:"incoming"`
+	Outgoing []FriendRequest `json:"outgoing"`
+}
+
+// FriendRequestReqUc request accept / decline / cancel friend request di usecase
+type FriendRequestReqUc struct {
+	UserName  string    `json:"user_name"`
+	RequestId uuid.UUID `json:"request_id"`
+}
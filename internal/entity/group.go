@@ -14,25 +14,29 @@ type CreateGroupRequest struct {
 
 // Group
 type Group struct {
-	Id        uuid.UUID   `json:"id"`
-	Name      string      `json:"name"`
-	Members   []uuid.UUID `json:"members"`
-	CreatedAt time.Time   `json:"createdAt"`
-	UpdatedAt time.Time   `json:"updatedAt"`
+	Id          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	AvatarUrl   string      `json:"avatar_url"`
+	Members     []uuid.UUID `json:"members"`
+	Role        GroupRole   `json:"role,omitempty"` // role user yang login di group
+	CreatedAt   time.Time   `json:"createdAt"`
+	UpdatedAt   time.Time   `json:"updatedAt"`
 }
 
 // AddNewGroupMemberReq menamahkan member group chat baru
 type AddNewGroupMemberReq struct {
-	Name    string      `json:"name"`
-	UserId  uuid.UUID   `json:"user_id"`
-	Members []uuid.UUID `json:"members"`
+	GroupId    uuid.UUID   `json:"group_id"`
+	UserId     uuid.UUID   `json:"user_id"`
+	Members    []uuid.UUID `json:"members"`
+	MaxMembers int         `json:"max_members"`
 }
 
 // RemoveGroupMemberReq menghapus member dari group
 type RemoveGroupMemberReq struct {
-	Name   string    `json:"name"`
-	UserId uuid.UUID `json:"user_id"`
-	Member uuid.UUID `json:"member"`
+	GroupId uuid.UUID `json:"group_id"`
+	UserId  uuid.UUID `json:"user_id"`
+	Member  uuid.UUID `json:"member"`
 }
 
 // creategroup request in usecase
@@ -42,15 +46,88 @@ type CreateGroupReqUc struct {
 	Members  []string `json:"members"`
 }
 
-// AddNewMemberReqUc request in usecasee
+// AddNewMemberReqUc request in usecasee, Name hanya dipakai jika GroupId kosong (client lama)
 type AddNewGroupMemberReqUc struct {
-	Name     string   `json:"name"`
-	UserName string   `json:"user_id"`
-	Members  []string `json:"members"`
+	GroupId  uuid.UUID `json:"group_id"`
+	Name     string    `json:"name"`
+	UserName string    `json:"user_id"`
+	Members  []string  `json:"members"`
 }
 
 type RemoveGroupMemberReqUc struct {
-	Name         string `json:"name"`
-	UserName     string `json:"user_id"`
-	UsertoRemove string `json:"userto_remove"`
+	GroupId      uuid.UUID `json:"group_id"`
+	Name         string    `json:"name"`
+	UserName     string    `json:"user_id"`
+	UsertoRemove string    `json:"userto_remove"`
+}
+
+// UpdateGroupReqUc request mengubah info group di usecase, field nil tidak diubah
+type UpdateGroupReqUc struct {
+	GroupId     uuid.UUID `json:"group_id"`
+	UserName    string    `json:"user_name"`
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	AvatarUrl   *string   `json:"avatar_url"`
+}
+
+// GroupReqUc request leave / delete group di usecase
+type GroupReqUc struct {
+	GroupId  uuid.UUID `json:"group_id"`
+	UserName string    `json:"user_name"`
+}
+
+// TransferOwnershipReqUc request transfer owner group ke member lain di usecase
+type TransferOwnershipReqUc struct {
+	GroupId  uuid.UUID `json:"group_id"`
+	UserName string    `json:"user_name"`
+	NewOwner string    `json:"new_owner"`
+}
+
+// GroupRecipient id & username member group, dipakai untuk fanout pesan group (di cache di redis)
+type GroupRecipient struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
+
+// GroupLimits batas jumlah member group & threshold large group (dari config)
+type GroupLimits struct {
+	MaxMembers          int
+	LargeGroupThreshold int
+}
+
+// IsLargeGroup group dengan member >= threshold masuk mode large group:
+// status online member & typing indicator tidak di fanout
+func (l GroupLimits) IsLargeGroup(memberCount int64) bool {
+	return l.LargeGroupThreshold > 0 && memberCount >= int64(l.LargeGroupThreshold)
+}
+
+// CanAdd cek apakah group masih bisa ditambah newMembers member
+func (l GroupLimits) CanAdd(memberCount int64, newMembers int) bool {
+	return l.MaxMembers <= 0 || memberCount+int64(newMembers) <= int64(l.MaxMembers)
+}
+
+// GroupMemberDetail member group beserta username & role
+type GroupMemberDetail struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Role     GroupRole `json:"role"`
+	Online   *bool     `json:"online,omitempty"` // nil untuk large group
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// GroupMembers 1 halaman member group, halaman berikutnya diambil dengan after_id = NextAfterId
+type GroupMembers struct {
+	GroupId     uuid.UUID           `json:"group_id"`
+	Total       int64               `json:"total"`
+	LargeGroup  bool                `json:"large_group"`
+	Members     []GroupMemberDetail `json:"members"`
+	NextAfterId uuid.UUID           `json:"next_after_id,omitempty"`
+}
+
+// GroupMembersReqUc request member group per halaman di usecase
+type GroupMembersReqUc struct {
+	GroupId  uuid.UUID `json:"group_id"`
+	UserName string    `json:"user_name"`
+	AfterId  uuid.UUID `json:"after_id"`
+	Limit    int       `json:"limit"`
 }
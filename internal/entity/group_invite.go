@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// GroupInvite link invite group, user yang redeem tidak perlu berteman dengan pembuat invite
+type GroupInvite struct {
+	Id               uuid.UUID  `json:"id"`
+	GroupId          uuid.UUID  `json:"group_id"`
+	Token            string     `json:"token"`
+	CreatedBy        uuid.UUID  `json:"created_by"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"` // nil berarti tidak pernah expired
+	MaxUses          int        `json:"max_uses"`             // 0 berarti tidak dibatasi
+	Uses             int        `json:"uses"`
+	ApprovalRequired bool       `json:"approval_required"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// CreateGroupInviteReqUc request membuat invite link di usecase
+type CreateGroupInviteReqUc struct {
+	GroupId          uuid.UUID     `json:"group_id"`
+	UserName         string        `json:"user_name"`
+	ExpiresIn        time.Duration `json:"expires_in"` // 0 berarti tidak pernah expired
+	MaxUses          int           `json:"max_uses"`
+	ApprovalRequired bool          `json:"approval_required"`
+}
+
+// GroupInviteReqUc request list / revoke invite link di usecase
+type GroupInviteReqUc struct {
+	GroupId  uuid.UUID `json:"group_id"`
+	UserName string    `json:"user_name"`
+	InviteId uuid.UUID `json:"invite_id"`
+}
+
+// JoinGroupReqUc request join group dengan token invite di usecase
+type JoinGroupReqUc struct {
+	Token    string `json:"token"`
+	UserName string `json:"user_name"`
+}
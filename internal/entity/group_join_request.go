@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type GroupJoinRequestStatus string
+
+const (
+	GroupJoinRequestPending  GroupJoinRequestStatus = "pending"
+	GroupJoinRequestApproved GroupJoinRequestStatus = "approved"
+	GroupJoinRequestDenied   GroupJoinRequestStatus = "denied"
+)
+
+// GroupJoinRequest request join group lewat invite link yang butuh approval admin
+type GroupJoinRequest struct {
+	Id        uuid.UUID              `json:"id"`
+	GroupId   uuid.UUID              `json:"group_id"`
+	GroupName string                 `json:"group_name"`
+	UserId    uuid.UUID              `json:"user_id"`
+	Username  string                 `json:"username"`
+	InviteId  uuid.UUID              `json:"invite_id"`
+	Status    GroupJoinRequestStatus `json:"status"`
+	DecidedBy uuid.UUID              `json:"decided_by,omitempty"`
+	DecidedAt *time.Time             `json:"decided_at,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// JoinGroupResult hasil join group, JoinRequest diisi jika invite link butuh approval admin
+type JoinGroupResult struct {
+	Group       Group             `json:"group"`
+	JoinRequest *GroupJoinRequest `json:"join_request,omitempty"`
+}
+
+// DecideJoinRequestReqUc request approve / deny join request di usecase
+type DecideJoinRequestReqUc struct {
+	GroupId   uuid.UUID `json:"group_id"`
+	UserName  string    `json:"user_name"`
+	RequestId uuid.UUID `json:"request_id"`
+	Approve   bool      `json:"approve"`
+}
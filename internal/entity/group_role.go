@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+)
+
+type (
+	GroupRole       string
+	GroupPermission string
+)
+
+const (
+	GroupRoleOwner  GroupRole = "owner"
+	GroupRoleAdmin  GroupRole = "admin"
+	GroupRoleMember GroupRole = "member"
+)
+
+const (
+	GroupPermissionAddMember     GroupPermission = "add_member"
+	GroupPermissionRemoveMember  GroupPermission = "remove_member"
+	GroupPermissionRename        GroupPermission = "rename"
+	GroupPermissionPinMessage    GroupPermission = "pin_message"
+	GroupPermissionDeleteMessage GroupPermission = "delete_message" // menghapus pesan member lain
+	GroupPermissionInvokeChatbot GroupPermission = "invoke_chatbot"
+	GroupPermissionManageRoles   GroupPermission = "manage_roles"   // promote & demote admin
+	GroupPermissionManageInvites GroupPermission = "manage_invites" // membuat, list & revoke invite link
+	GroupPermissionApproveJoin   GroupPermission = "approve_join"   // approve & deny join request
+	GroupPermissionEditInfo      GroupPermission = "edit_info"      // deskripsi & avatar group
+	GroupPermissionTransferOwner GroupPermission = "transfer_owner"
+	GroupPermissionDeleteGroup   GroupPermission = "delete_group"
+)
+
+// groupPermissions permission matrix setiap role di group
+var groupPermissions = map[GroupRole]map[GroupPermission]bool{
+	GroupRoleOwner: {
+		GroupPermissionAddMember:     true,
+		GroupPermissionRemoveMember:  true,
+		GroupPermissionRename:        true,
+		GroupPermissionPinMessage:    true,
+		GroupPermissionDeleteMessage: true,
+		GroupPermissionInvokeChatbot: true,
+		GroupPermissionManageRoles:   true,
+		GroupPermissionManageInvites: true,
+		GroupPermissionApproveJoin:   true,
+		GroupPermissionEditInfo:      true,
+		GroupPermissionTransferOwner: true,
+		GroupPermissionDeleteGroup:   true,
+	},
+	GroupRoleAdmin: {
+		GroupPermissionAddMember:     true,
+		GroupPermissionRemoveMember:  true,
+		GroupPermissionRename:        true,
+		GroupPermissionPinMessage:    true,
+		GroupPermissionDeleteMessage: true,
+		GroupPermissionInvokeChatbot: true,
+		GroupPermissionManageInvites: true,
+		GroupPermissionApproveJoin:   true,
+		GroupPermissionEditInfo:      true,
+	},
+	GroupRoleMember: {
+		GroupPermissionInvokeChatbot: true,
+	},
+}
+
+// groupRoleRank urutan role, role yang lebih tinggi bisa mengatur role di bawahnya
+var groupRoleRank = map[GroupRole]int{
+	GroupRoleOwner:  3,
+	GroupRoleAdmin:  2,
+	GroupRoleMember: 1,
+}
+
+// Can cek apakah role punya permission
+func (r GroupRole) Can(p GroupPermission) bool {
+	return groupPermissions[r][p]
+}
+
+// Outranks cek apakah role lebih tinggi dari role lain,
+// contoh admin boleh remove member tetapi tidak boleh remove admin lain / owner
+func (r GroupRole) Outranks(other GroupRole) bool {
+	return groupRoleRank[r] > groupRoleRank[other]
+}
+
+// GroupMember member group beserta rolenya
+type GroupMember struct {
+	UserId uuid.UUID `json:"user_id"`
+	Role   GroupRole `json:"role"`
+}
+
+// ChangeGroupRoleReqUc request promote / demote member group di usecase
+type ChangeGroupRoleReqUc struct {
+	GroupId  uuid.UUID `json:"group_id"`
+	Name     string    `json:"name"`
+	UserName string    `json:"user_name"`
+	Member   string    `json:"member"`
+	Role     GroupRole `json:"role"`
+}
+
+// RenameGroupReqUc request mengganti nama group di usecase
+type RenameGroupReqUc struct {
+	GroupId  uuid.UUID `json:"group_id"`
+	Name     string    `json:"name"`
+	UserName string    `json:"user_name"`
+	NewName  string    `json:"new_name"`
+}
+
+// GroupMessageActionReqUc request pin / unpin / hapus pesan group di usecase
+type GroupMessageActionReqUc struct {
+	GroupId   uuid.UUID `json:"group_id"`
+	Name      string    `json:"name"`
+	UserName  string    `json:"user_name"`
+	MessageId uint64    `json:"message_id"`
+}
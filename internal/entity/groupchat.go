@@ -5,14 +5,39 @@ import (
 	"time"
 )
 
+type GroupChatKind string
+
+const (
+	// GroupChatKindMessage pesan biasa dari member group
+	GroupChatKindMessage GroupChatKind = "message"
+
+	// system message, UserId diisi user yang melakukan perubahan
+	GroupChatKindMemberAdded          GroupChatKind = "member_added"
+	GroupChatKindMemberRemoved        GroupChatKind = "member_removed"
+	GroupChatKindRenamed              GroupChatKind = "renamed"
+	GroupChatKindGroupUpdated         GroupChatKind = "group_updated" // deskripsi / avatar diubah
+	GroupChatKindMemberLeft           GroupChatKind = "member_left"
+	GroupChatKindOwnershipTransferred GroupChatKind = "ownership_transferred"
+	GroupChatKindGroupDeleted         GroupChatKind = "group_deleted"
+)
+
+// IsSystem cek apakah pesan adalah system message
+func (k GroupChatKind) IsSystem() bool {
+	return k != "" && k != GroupChatKindMessage
+}
+
 // GroupChatMessage entitas pesan group chat
 type GroupChatMessage struct {
-	GroupId   uuid.UUID `json:"id"`
-	MessageId uint64    `json:"message_id"`
-	UserId    uuid.UUID `json:"user_id"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
-	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	GroupId   uuid.UUID       `json:"id"`
+	MessageId uint64          `json:"message_id"`
+	UserId    uuid.UUID       `json:"user_id"`
+	Kind      GroupChatKind   `json:"kind"`
+	Content   string          `json:"content"`
+	Format    MessageFormat   `json:"format"`
+	Entities  []MessageEntity `json:"entities,omitempty"`
+	PinnedAt  *time.Time      `json:"pinned_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at,omitempty"`
 }
 
 // GroupChatMessages array of pesan group chat
@@ -20,7 +45,9 @@ type GroupChatMessages struct {
 	Messages []GroupChatMessage `json:"messages"`
 }
 
+// GroupChatMsgRequest diisi GroupId, GroupName hanya untuk client lama (deprecated)
 type GroupChatMsgRequest struct {
-	GroupName string `json:"group_name"`
-	UserName  string `json:"user_name"`
+	GroupId   uuid.UUID `json:"group_id"`
+	GroupName string    `json:"group_name"`
+	UserName  string    `json:"user_name"`
 }
@@ -0,0 +1,29 @@
+package entity
+
+type (
+	MessageFormat     string
+	MessageEntityType string
+)
+
+const (
+	MessageFormatPlain    MessageFormat = "plain"
+	MessageFormatMarkdown MessageFormat = "markdown"
+
+	MessageEntityBold     MessageEntityType = "bold"
+	MessageEntityItalic   MessageEntityType = "italic"
+	MessageEntityCode     MessageEntityType = "code"
+	MessageEntityPre      MessageEntityType = "pre"
+	MessageEntityTextLink MessageEntityType = "text_link"
+	MessageEntityMention  MessageEntityType = "mention"
+)
+
+// MessageEntity bagian dari teks pesan yang diberi format (bold, code, link, mention).
+// Offset & Length dalam satuan UTF-16 code unit, sama seperti index string di javascript,
+// sehingga client bisa langsung render tanpa parsing markdown lagi.
+type MessageEntity struct {
+	Type     MessageEntityType `json:"type"`
+	Offset   int               `json:"offset"`
+	Length   int               `json:"length"`
+	Url      string            `json:"url,omitempty"`      // diisi untuk text_link
+	Username string            `json:"username,omitempty"` // diisi untuk mention
+}
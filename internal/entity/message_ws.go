@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"github.com/google/uuid"
 	"time"
 )
 
@@ -20,6 +21,15 @@ type MessageWs struct {
 	MsgFriendsOnlineStatus MessageFriendsOnlineStatus `json:"msg_friends_online_status,omitempty"`
 	MsgGroupChat           MessageGroupChat           `json:"group_chat,omitempty"`
 	MsgGroupChatBot        MessageGroupChatBot        `json:"group_chat_bot,omitempty"`
+	MsgDraft               MessageDraft               `json:"draft,omitempty"`
+	MsgForceDisconnect     MessageForceDisconnect     `json:"force_disconnect,omitempty"`
+	MsgGroupJoinRequest    MessageGroupJoinRequest    `json:"group_join_request,omitempty"`
+	MsgGroupUpdated        MessageGroupUpdated        `json:"group_updated,omitempty"`
+	MsgChannelPost         MessageChannelPost         `json:"channel_post,omitempty"`
+	MsgChannelSubscription MessageChannelSubscription `json:"channel_subscription,omitempty"`
+	MsgTyping              MessageTyping              `json:"typing,omitempty"`
+	MsgFriendRequest       MessageFriendRequest       `json:"friend_request,omitempty"`
+	MsgProfileUpdated      MessageProfileUpdated      `json:"profile_updated,omitempty"`
 }
 
 // MessagePrivateChat message untuk private chat
@@ -28,17 +38,22 @@ type MessagePrivateChat struct {
 	SenderUsername    string `json:"sender_username"`
 	RecipientUsername string `json:"recipient_username"`
 	//GroupId           string      `json:"group_id"`
-	Message   string    `json:"message"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
+	Muted     bool            `json:"muted,omitempty"` // recipient mute percakapan, client tidak memunculkan notifikasi
+	Message   string          `json:"message"`
+	Format    MessageFormat   `json:"format,omitempty"`
+	Entities  []MessageEntity `json:"entities,omitempty"`
+	CreatedAt time.Time       `json:"created_at,omitempty"`
 }
 
 // MessageOnlineStatusFanout Message ws untuk fanout user online status ke semua kontak user
 type MessageOnlineStatusFanout struct {
-	FriendId          string `json:"friend_id"`
-	FriendUsername    string `json:"friend_username"`
-	FriendEmail       string `json:"friend_email"`
-	Online            bool   `json:"online"`
-	UserToGetNotified string `json:"user_to_get_notified,omitempty"`
+	FriendId          string      `json:"friend_id"`
+	FriendUsername    string      `json:"friend_username"`
+	FriendEmail       string      `json:"friend_email"`
+	Online            bool        `json:"online"`
+	LastSeenAt        *time.Time  `json:"last_seen_at,omitempty"` // diisi ketika offline jika diizinkan setting privacy
+	Status            *UserStatus `json:"status,omitempty"`       // away / busy / dnd & teks status user
+	UserToGetNotified string      `json:"user_to_get_notified,omitempty"`
 }
 
 // MessageFriendsOnlineStatus Message ws untuk melihat status online semua kontak/teman dari usernya
@@ -50,30 +65,136 @@ type MessageFriendsOnlineStatus struct {
 
 // MessageGroupChat Message untuk group chat
 type MessageGroupChat struct {
-	GroupName         string    `json:"group_name"`
-	MessageId         uint64    `json:"message_id,omitempty"`
-	SenderUsername    string    `json:"sender_username"`
-	RecipientUsername string    `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
-	Content           string    `json:"message"`
-	CreatedAt         time.Time `json:"created_at,omitempty"`
+	GroupId            uuid.UUID       `json:"group_id"`
+	GroupName          string          `json:"group_name"` // deprecated, hanya untuk client lama yang belum mengirim group_id
+	MessageId          uint64          `json:"message_id,omitempty"`
+	SenderUsername     string          `json:"sender_username"`
+	RecipientUsername  string          `json:"recipient_username,omitempty"`  // diisi ketika broadcast ke channel broadcast/ channell redis
+	RecipientUsernames []string        `json:"recipient_usernames,omitempty"` // semua recipient di 1 chat-server, 1 message per chat-server
+	MutedUsernames     []string        `json:"muted_usernames,omitempty"`     // recipient di RecipientUsernames yang mute group
+	Muted              bool            `json:"muted,omitempty"`               // recipient mute group, client tidak memunculkan notifikasi
+	BlockerUsernames   []string        `json:"blocker_usernames,omitempty"`   // recipient di RecipientUsernames yang memblokir sender
+	SenderBlocked      bool            `json:"sender_blocked,omitempty"`      // recipient memblokir sender, client bisa menyembunyikan pesan
+	Kind               GroupChatKind   `json:"kind,omitempty"`                // kosong / message untuk pesan biasa, selain itu system message
+	Content            string          `json:"message"`
+	Format             MessageFormat   `json:"format,omitempty"`
+	Entities           []MessageEntity `json:"entities,omitempty"`
+	CreatedAt          time.Time       `json:"created_at,omitempty"`
 }
 
 // MessageGroupChatBot message untuk memanggil chatbot didalam groupChat
 type MessageGroupChatBot struct {
-	GroupName         string    `json:"group_name"`
-	MessageId         uint64    `json:"message_id,omitempty"`
+	GroupId            uuid.UUID `json:"group_id"`
+	GroupName          string    `json:"group_name"` // deprecated, hanya untuk client lama yang belum mengirim group_id
+	MessageId          uint64    `json:"message_id,omitempty"`
+	SenderUsername     string    `json:"sender_username"`
+	RecipientUsername  string    `json:"recipient_username,omitempty"`  // diisi ketika broadcast ke channel broadcast/ channell redis
+	RecipientUsernames []string  `json:"recipient_usernames,omitempty"` // semua recipient di 1 chat-server, 1 message per chat-server
+	Content            string    `json:"message"`
+	CreatedAt          time.Time `json:"created_at,omitempty"`
+}
+
+// MessageDraft message ws untuk sinkronisasi draft pesan ke semua device user
+// diisi salah satu dari FriendUsername (private chat) atau GroupId (group chat), Content kosong berarti draft dihapus
+type MessageDraft struct {
 	SenderUsername    string    `json:"sender_username"`
+	FriendUsername    string    `json:"friend_username,omitempty"`
+	GroupId           uuid.UUID `json:"group_id,omitempty"`
+	GroupName         string    `json:"group_name,omitempty"` // deprecated, pakai GroupId
+	Content           string    `json:"content"`
+	UpdatedAt         time.Time `json:"updated_at,omitempty"`
+	OriginSessionId   string    `json:"origin_session_id,omitempty"`  // koneksi websocket yg mengirim draft, tidak dikirim balik ke koneksi ini
+	RecipientUsername string    `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
+}
+
+// MessageForceDisconnect message ws untuk menutup semua koneksi websocket user (misal akun user di suspend)
+type MessageForceDisconnect struct {
+	Reason            string `json:"reason"`
+	RecipientUsername string `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
+}
+
+// MessageGroupJoinRequest message ws join request group,
+// dikirim ke owner & admin group ketika ada request baru dan ke user yang request ketika request di approve / deny
+type MessageGroupJoinRequest struct {
+	RequestId         uuid.UUID              `json:"request_id"`
+	GroupId           uuid.UUID              `json:"group_id"`
+	GroupName         string                 `json:"group_name"`
+	Username          string                 `json:"username"` // user yang request join
+	Status            GroupJoinRequestStatus `json:"status"`
+	RecipientUsername string                 `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
+}
+
+// MessageGroupUpdated message ws ketika info group berubah atau group dihapus, dikirim ke semua member group
+type MessageGroupUpdated struct {
+	GroupId           uuid.UUID `json:"group_id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	AvatarUrl         string    `json:"avatar_url"`
+	Deleted           bool      `json:"deleted,omitempty"`
 	RecipientUsername string    `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
-	Content           string    `json:"message"`
-	CreatedAt         time.Time `json:"created_at,omitempty"`
+}
+
+// MessageChannelPost pesan di channel broadcast, dikirim 1 kali ke setiap chat-server yang punya subscriber online
+// lalu chat-server mengirim ke semua subscriber channel yang terhubung ke chat-server tsb
+type MessageChannelPost struct {
+	ChannelId      uuid.UUID       `json:"channel_id"`
+	ChannelName    string          `json:"channel_name"`
+	MessageId      uint64          `json:"message_id,omitempty"`
+	SenderUsername string          `json:"sender_username"`
+	Content        string          `json:"message"`
+	Format         MessageFormat   `json:"format,omitempty"`
+	Entities       []MessageEntity `json:"entities,omitempty"`
+	CreatedAt      time.Time       `json:"created_at,omitempty"`
+}
+
+// MessageChannelSubscription message ws ketika user subscribe / unsubscribe channel,
+// chat-server tempat user terhubung memperbarui daftar subscriber channel yang online
+type MessageChannelSubscription struct {
+	ChannelId         uuid.UUID `json:"channel_id"`
+	Subscribed        bool      `json:"subscribed"`
+	RecipientUsername string    `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
+}
+
+// MessageTyping typing indicator di private chat (friend_username) atau group (group_id),
+// tidak dikirim di group yang masuk mode large group
+type MessageTyping struct {
+	FriendUsername     string    `json:"friend_username,omitempty"`
+	GroupId            uuid.UUID `json:"group_id,omitempty"`
+	SenderUsername     string    `json:"sender_username"`
+	Typing             bool      `json:"typing"`
+	RecipientUsername  string    `json:"recipient_username,omitempty"`  // diisi ketika broadcast ke channel broadcast/ channell redis
+	RecipientUsernames []string  `json:"recipient_usernames,omitempty"` // semua recipient group di 1 chat-server
+}
+
+// MessageFriendRequest message ws friend request, dikirim ke recipient ketika ada request baru / request di cancel
+// dan ke sender ketika request di accept / decline
+type MessageFriendRequest struct {
+	RequestId         uuid.UUID           `json:"request_id"`
+	SenderUsername    string              `json:"sender_username"`
+	TargetUsername    string              `json:"target_username"` // user yang dikirimi friend request
+	Status            FriendRequestStatus `json:"status"`
+	RecipientUsername string              `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
+}
+
+// MessageProfileUpdated message ws perubahan profil user, dikirim ke kontak user yang sedang online
+type MessageProfileUpdated struct {
+	Username          string `json:"username"`
+	DisplayName       string `json:"display_name"`
+	AvatarUrl         string `json:"avatar_url"`
+	Bio               string `json:"bio"`
+	Location          string `json:"location"`
+	Website           string `json:"website"`
+	RecipientUsername string `json:"recipient_username,omitempty"` // diisi ketika broadcast ke channel broadcast/ channell redis
 }
 
 // Friend Struktur data user
 type Friend struct {
-	FriendId       string `json:"friend_id"`
-	FriendUsername string `json:"friend_username"`
-	FriendEmail    string `json:"friend_email"`
-	Online         bool   `json:"online"`
+	FriendId       string      `json:"friend_id"`
+	FriendUsername string      `json:"friend_username"`
+	FriendEmail    string      `json:"friend_email"`
+	Online         bool        `json:"online"`
+	LastSeenAt     *time.Time  `json:"last_seen_at,omitempty"` // diisi ketika offline jika diizinkan setting privacy
+	Status         *UserStatus `json:"status,omitempty"`       // away / busy / dnd & teks status user
 }
 
 type (
@@ -91,4 +212,14 @@ const (
 	MessageTypeGroupChatJoin       MessageType = "group_chat_join"
 	MessageTypeFriendsOnlineStatus MessageType = "friends_online_status"
 	MessageTypeGroupChatBot        MessageType = "group_chatbot"
+	MessageTypeDraftUpdate         MessageType = "draft_update"
+	MessageTypeForceDisconnect     MessageType = "force_disconnect"
+	MessageTypeGroupJoinRequest    MessageType = "group_join_request"
+	MessageTypeGroupJoinDecision   MessageType = "group_join_decision"
+	MessageTypeGroupUpdated        MessageType = "group_updated"
+	MessageTypeChannelPost         MessageType = "channel_post"
+	MessageTypeChannelSubscription MessageType = "channel_subscription"
+	MessageTypeTyping              MessageType = "typing"
+	MessageTypeFriendRequest       MessageType = "friend_request"
+	MessageTypeProfileUpdated      MessageType = "profile_updated"
 )
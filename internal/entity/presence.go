@@ -0,0 +1,61 @@
+package entity
+
+import "time"
+
+// PresenceStatus status ketersediaan yang dipilih sendiri oleh user
+type PresenceStatus string
+
+const (
+	PresenceAvailable    PresenceStatus = "available"
+	PresenceAway         PresenceStatus = "away"
+	PresenceBusy         PresenceStatus = "busy"
+	PresenceDoNotDisturb PresenceStatus = "dnd"
+)
+
+func (p PresenceStatus) Valid() bool {
+	return p == PresenceAvailable || p == PresenceAway || p == PresenceBusy || p == PresenceDoNotDisturb
+}
+
+// UserStatus status user (presence + teks bebas), ExpiresAt nil berarti status tidak kadaluarsa
+type UserStatus struct {
+	Presence  PresenceStatus `json:"presence"`
+	Text      string         `json:"text,omitempty"`
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
+	Idle      bool           `json:"idle,omitempty"` // otomatis away karena user tidak aktif
+}
+
+// DefaultUserStatus status user yang belum memilih status / statusnya sudah kadaluarsa
+func DefaultUserStatus() UserStatus {
+	return UserStatus{Presence: PresenceAvailable}
+}
+
+// WithIdle status user yang tidak aktif, status available berubah menjadi away.
+// Status busy / dnd yang dipilih user tetap dipakai
+func (s UserStatus) WithIdle(idle bool) UserStatus {
+	if !idle {
+		return s
+	}
+	s.Idle = true
+	if s.Presence == "" || s.Presence == PresenceAvailable {
+		s.Presence = PresenceAway
+	}
+	return s
+}
+
+// IsDefault status sama dengan status default, tidak perlu disimpan
+func (s UserStatus) IsDefault() bool {
+	return (s.Presence == "" || s.Presence == PresenceAvailable) && s.Text == ""
+}
+
+// Expired status sudah melewati waktu kadaluarsanya
+func (s UserStatus) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !s.ExpiresAt.After(now)
+}
+
+// UserStatusReqUc request ubah status user login di usecase, ExpiresIn 0 berarti status tidak kadaluarsa
+type UserStatusReqUc struct {
+	UserName  string         `json:"user_name"`
+	Presence  PresenceStatus `json:"presence"`
+	Text      string         `json:"text"`
+	ExpiresIn time.Duration  `json:"expires_in"`
+}
@@ -7,24 +7,29 @@ import (
 
 // PrivateChat messages
 type PrivateChatMessage struct {
-	MessageId   uint64    `json:"message_id"`
-	MessageFrom uuid.UUID `json:"message_from"`
-	MessageTo   uuid.UUID `json:"message_to"`
-	Content     string    `json:"content"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	DeletedAt   time.Time `json:"deleted_at"`
+	MessageId   uint64          `json:"message_id"`
+	MessageFrom uuid.UUID       `json:"message_from"`
+	MessageTo   uuid.UUID       `json:"message_to"`
+	Content     string          `json:"content"`
+	Format      MessageFormat   `json:"format"`
+	Entities    []MessageEntity `json:"entities,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   time.Time       `json:"deleted_at"`
 }
 
 type PrivateChatUsers struct {
 	Message map[uuid.UUID][]PrivateChatMessage `json:"message"`
+	Drafts  []Draft                            `json:"drafts"`
 }
 
 type InsertPrivateChatRequest struct {
-	MessageId   uint64    `json:"message_id"`
-	MessageFrom uuid.UUID `json:"message_from"`
-	MessageTo   uuid.UUID `json:"message_to"`
-	Content     string    `json:"content"`
+	MessageId   uint64          `json:"message_id"`
+	MessageFrom uuid.UUID       `json:"message_from"`
+	MessageTo   uuid.UUID       `json:"message_to"`
+	Content     string          `json:"content"`
+	Format      MessageFormat   `json:"format"`
+	Entities    []MessageEntity `json:"entities"`
 }
 
 // query ke db
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type (
+	ReportStatus string
+)
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusResolved  ReportStatus = "resolved"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// Report laporan user terhadap pesan / user lain
+type Report struct {
+	Id               uuid.UUID        `json:"id"`
+	ReporterId       uuid.UUID        `json:"reporter_id"` // uuid.Nil jika report dibuat oleh content filter
+	ReportedUserId   uuid.UUID        `json:"reported_user_id"`
+	ConversationType ConversationType `json:"conversation_type,omitempty"` // kosong jika yang dilaporkan user
+	MessageId        uint64           `json:"message_id,omitempty"`
+	GroupId          uuid.UUID        `json:"group_id,omitempty"`
+	Reason           string           `json:"reason"`
+	Status           ReportStatus     `json:"status"`
+	ResolvedBy       uuid.UUID        `json:"resolved_by,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// CreateReportReqUc request membuat report di usecase
+// untuk melaporkan pesan isi ConversationType & MessageId (& GroupId untuk group chat),
+// untuk melaporkan user isi ReportedUsername
+type CreateReportReqUc struct {
+	ReporterUsername string           `json:"reporter_username"`
+	ReportedUsername string           `json:"reported_username"`
+	ConversationType ConversationType `json:"conversation_type"`
+	MessageId        uint64           `json:"message_id"`
+	GroupId          uuid.UUID        `json:"group_id"`
+	GroupName        string           `json:"group_name"` // deprecated, pakai GroupId
+	Reason           string           `json:"reason"`
+}
+
+// GetReportsReqUc request admin untuk melihat daftar report
+type GetReportsReqUc struct {
+	AdminUsername string       `json:"admin_username"`
+	Status        ReportStatus `json:"status"`
+}
+
+// ResolveReportReqUc request admin untuk menutup report
+type ResolveReportReqUc struct {
+	AdminUsername string       `json:"admin_username"`
+	ReportId      uuid.UUID    `json:"report_id"`
+	Status        ReportStatus `json:"status"`
+}
+
+// HideMessageReqUc request admin untuk menyembunyikan (soft delete) pesan
+type HideMessageReqUc struct {
+	AdminUsername    string           `json:"admin_username"`
+	ConversationType ConversationType `json:"conversation_type"`
+	MessageId        uint64           `json:"message_id"`
+	GroupId          uuid.UUID        `json:"group_id"`
+}
+
+// SuspendUserReqUc request admin untuk suspend / unsuspend akun user
+type SuspendUserReqUc struct {
+	AdminUsername string `json:"admin_username"`
+	Username      string `json:"username"`
+	Suspend       bool   `json:"suspend"`
+	Reason        string `json:"reason"`
+}
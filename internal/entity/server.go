@@ -5,3 +5,10 @@ var ChatServerNameGlobal *ServerName
 type ServerName struct {
 	ChatServerName string
 }
+
+// UserPresence status online, status yang dipilih user & lokasi chat-server user
+type UserPresence struct {
+	Online         bool
+	Status         UserStatus
+	ServerLocation string
+}
@@ -1 +1,177 @@
 package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PrivacyVisibility siapa saja yang boleh melihat / menemukan data user
+type PrivacyVisibility string
+
+const (
+	PrivacyEveryone PrivacyVisibility = "everyone"
+	PrivacyContacts PrivacyVisibility = "contacts"
+	PrivacyNobody   PrivacyVisibility = "nobody"
+)
+
+func (v PrivacyVisibility) Valid() bool {
+	return v == PrivacyEveryone || v == PrivacyContacts || v == PrivacyNobody
+}
+
+// Allows apakah viewer boleh melihat data user, isContact true jika viewer adalah kontak user
+func (v PrivacyVisibility) Allows(isContact bool) bool {
+	switch v {
+	case PrivacyEveryone:
+		return true
+	case PrivacyContacts:
+		return isContact
+	}
+	return false
+}
+
+// PrivacySetting nama setting privacy yang bisa diberi pengecualian per user
+type PrivacySetting string
+
+const (
+	PrivacySettingOnlineStatus PrivacySetting = "online_status"
+	PrivacySettingLastSeen     PrivacySetting = "last_seen"
+)
+
+// PrivacyException pengecualian setting privacy untuk 1 user. Allow true berarti user selalu boleh melihat,
+// false berarti user tidak pernah boleh melihat walaupun diizinkan setting
+type PrivacyException struct {
+	UserId   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Allow    bool      `json:"allow"`
+}
+
+// PrivacySettings setting privacy user.
+// Discoverable siapa yang bisa menemukan user lewat search, OnlineStatus siapa yang bisa melihat status online user,
+// LastSeen siapa yang bisa melihat last seen user
+type PrivacySettings struct {
+	Discoverable           PrivacyVisibility  `json:"discoverable"`
+	OnlineStatus           PrivacyVisibility  `json:"online_status"`
+	LastSeen               PrivacyVisibility  `json:"last_seen"`
+	OnlineStatusExceptions []PrivacyException `json:"online_status_exceptions"`
+	LastSeenExceptions     []PrivacyException `json:"last_seen_exceptions"`
+	UpdatedAt              time.Time          `json:"updated_at,omitempty"`
+}
+
+// DefaultPrivacySettings setting privacy user yang belum pernah mengubah settingnya
+func DefaultPrivacySettings() PrivacySettings {
+	return PrivacySettings{
+		Discoverable: PrivacyEveryone,
+		OnlineStatus: PrivacyEveryone,
+		LastSeen:     PrivacyEveryone,
+	}
+}
+
+// CanSeeOnlineStatus apakah viewer boleh melihat status online user, pengecualian didahulukan dari setting
+func (s PrivacySettings) CanSeeOnlineStatus(viewerId uuid.UUID, isContact bool) bool {
+	return visibleTo(s.OnlineStatus, s.OnlineStatusExceptions, viewerId, isContact)
+}
+
+// CanSeeLastSeen apakah viewer boleh melihat last seen user, pengecualian didahulukan dari setting
+func (s PrivacySettings) CanSeeLastSeen(viewerId uuid.UUID, isContact bool) bool {
+	return visibleTo(s.LastSeen, s.LastSeenExceptions, viewerId, isContact)
+}
+
+func visibleTo(v PrivacyVisibility, exceptions []PrivacyException, viewerId uuid.UUID, isContact bool) bool {
+	for _, exception := range exceptions {
+		if exception.UserId == viewerId {
+			return exception.Allow
+		}
+	}
+	return v.Allows(isContact)
+}
+
+// PrivacyExceptionsReqUc username pengecualian 1 setting privacy, AlwaysShare selalu boleh melihat,
+// NeverShare tidak pernah boleh melihat
+type PrivacyExceptionsReqUc struct {
+	AlwaysShare []string `json:"always_share"`
+	NeverShare  []string `json:"never_share"`
+}
+
+// PrivacySettingsReqUc request ubah setting privacy di usecase, kosong / nil berarti tidak diubah
+type PrivacySettingsReqUc struct {
+	UserName               string                  `json:"user_name"`
+	Discoverable           PrivacyVisibility       `json:"discoverable"`
+	OnlineStatus           PrivacyVisibility       `json:"online_status"`
+	LastSeen               PrivacyVisibility       `json:"last_seen"`
+	OnlineStatusExceptions *PrivacyExceptionsReqUc `json:"online_status_exceptions"`
+	LastSeenExceptions     *PrivacyExceptionsReqUc `json:"last_seen_exceptions"`
+}
+
+// UserSearchResult user yang ditemukan lewat search username / display name
+type UserSearchResult struct {
+	UserId      uuid.UUID `json:"user_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	AvatarUrl   string    `json:"avatar_url"`
+}
+
+// UserSearchReqUc request search user di usecase
+type UserSearchReqUc struct {
+	UserName string `json:"user_name"`
+	Query    string `json:"query"`
+	Limit    int    `json:"limit"`
+}
+
+// PublicProfile profil user yang bisa dilihat user lain, LastSeenAt kosong jika disembunyikan setting privacy
+type PublicProfile struct {
+	UserId      uuid.UUID  `json:"user_id"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"display_name"`
+	AvatarUrl   string     `json:"avatar_url"`
+	Bio         string     `json:"bio"`
+	Location    string     `json:"location"`
+	Website     string     `json:"website"`
+	LastSeenAt  *time.Time `json:"last_seen_at,omitempty"`
+	IsContact   bool       `json:"is_contact"`
+}
+
+// PublicProfileReqUc request profil publik user lain di usecase
+type PublicProfileReqUc struct {
+	UserName        string `json:"user_name"`
+	ProfileUsername string `json:"profile_username"`
+}
+
+// MutualGroup group yang diikuti user login & user lain
+type MutualGroup struct {
+	Id        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	AvatarUrl string    `json:"avatar_url"`
+}
+
+// Mutual group & kontak yang sama antara user login & user lain
+type Mutual struct {
+	Groups   []MutualGroup      `json:"groups"`
+	Contacts []UserSearchResult `json:"contacts"`
+}
+
+// UpdateProfileReqUc request ubah profil user login di usecase, field nil berarti tidak diubah
+type UpdateProfileReqUc struct {
+	UserName    string  `json:"user_name"`
+	DisplayName *string `json:"display_name"`
+	Bio         *string `json:"bio"`
+	Location    *string `json:"location"`
+	Website     *string `json:"website"`
+}
+
+// Avatar file avatar yang diupload user
+type Avatar struct {
+	ContentType string    `json:"content_type"`
+	Data        []byte    `json:"-"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AvatarUploadReqUc request upload avatar user login di usecase
+type AvatarUploadReqUc struct {
+	UserName string `json:"user_name"`
+	Data     []byte `json:"-"`
+}
+
+// ProfileLimits batas ukuran avatar yang bisa diupload (dari config)
+type ProfileLimits struct {
+	AvatarMaxSize int64
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/lintangbs/chat-be/internal/util/jwt"
@@ -16,8 +17,14 @@ const (
 	AuthorizationPayloadKey = "authorization_payload"
 )
 
-// AuthMiddleware creates a gin middleware for authorization
-func AuthMiddleware(tokenMaker jwt.JwtTokenMaker) gin.HandlerFunc {
+// SuspensionChecker checks whether a user account is suspended
+type SuspensionChecker interface {
+	IsUserSuspended(ctx context.Context, username string) (bool, error)
+}
+
+// AuthMiddleware creates a gin middleware for authorization.
+// Requests from suspended users are rejected even if their access token is still valid
+func AuthMiddleware(tokenMaker jwt.JwtTokenMaker, suspension SuspensionChecker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(AuthorizationHeaderKey)
 
@@ -48,6 +55,18 @@ func AuthMiddleware(tokenMaker jwt.JwtTokenMaker) gin.HandlerFunc {
 			return
 		}
 
+		suspended, err := suspension.IsUserSuspended(ctx.Request.Context(), payload.Username)
+		if err != nil {
+			err = errors.New("failed to check account status")
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if suspended {
+			err = errors.New("your account has been suspended")
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+
 		ctx.Set(AuthorizationPayloadKey, payload)
 		ctx.Next()
 	}
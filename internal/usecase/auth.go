@@ -2,13 +2,22 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/lintangbs/chat-be/internal/entity"
 	"github.com/lintangbs/chat-be/internal/util"
 	"github.com/lintangbs/chat-be/internal/util/jwt"
+	"log"
 	"time"
 )
 
+var (
+	UserSuspendedErr = errors.New("your account has been suspended")
+)
+
+// suspendedCacheTTL lama cache status suspend akun user di redis
+const suspendedCacheTTL = time.Minute
+
 type AuthUseCase struct {
 	userRepo      UserRepo
 	jwtTokenMaker jwt.JwtTokenMaker
@@ -16,11 +25,12 @@ type AuthUseCase struct {
 	otpRepo       OtpRepo
 	pubSubRds     PubSubRedis
 	userRdsRepo   UserRedisRepo
+	emailHasher   EmailHasher
 }
 
 func NewAuthUseCase(r UserRepo, j jwt.JwtTokenMaker, s SessionRepo,
 	otpRepo OtpRepo,
-	redis PubSubRedis, userRdsRepo UserRedisRepo) *AuthUseCase {
+	redis PubSubRedis, userRdsRepo UserRedisRepo, emailHasher EmailHasher) *AuthUseCase {
 	return &AuthUseCase{
 		userRepo:      r,
 		jwtTokenMaker: j,
@@ -28,6 +38,7 @@ func NewAuthUseCase(r UserRepo, j jwt.JwtTokenMaker, s SessionRepo,
 		otpRepo:       otpRepo,
 		pubSubRds:     redis,
 		userRdsRepo:   userRdsRepo,
+		emailHasher:   emailHasher,
 	}
 }
 
@@ -44,6 +55,12 @@ func (uc *AuthUseCase) Register(ctx context.Context, c entity.CreateUserRequest)
 		return entity.UserResponse{}, fmt.Errorf("AuthUseCase - Register - uc.userRepo.CreateUser: %w", err)
 	}
 
+	// hash email untuk contact discovery, jika gagal dihitung ulang oleh ContactUseCase.RunEmailHashIndexer
+	emailHash := uc.emailHasher.Index(uc.emailHasher.ClientHash(createdUser.Email))
+	if err = uc.userRepo.SetEmailHash(ctx, createdUser.Id, emailHash, uc.emailHasher.Key()); err != nil {
+		log.Println("AuthUseCase - Register - uc.userRepo.SetEmailHash: ", err)
+	}
+
 	return createdUser, nil
 }
 
@@ -61,6 +78,11 @@ func (uc *AuthUseCase) Login(ctx context.Context, l entity.LoginUserRequest) (en
 		return entity.LoginUserResponse{}, fmt.Errorf("AuthUseCase - Login - util.CheckPassword: %w", err)
 	}
 
+	if isSuspended(user) {
+		// forbidden, akun user di suspend oleh admin
+		return entity.LoginUserResponse{}, fmt.Errorf("AuthUseCase - Login: %w", UserSuspendedErr)
+	}
+
 	accessToken, accessPayload, err := uc.jwtTokenMaker.CreateToken(
 		user.Username,
 		7*time.Hour,
@@ -217,3 +239,25 @@ func (uc *AuthUseCase) DeleteRefreshToken(ctx context.Context, d entity.DeleteRe
 	}
 	return nil
 }
+
+// IsUserSuspended cek apakah akun user sedang di suspend, dipakai auth middleware.
+// Status dicache di redis, cache di update langsung oleh ModerationUseCase.SuspendUser
+func (uc *AuthUseCase) IsUserSuspended(ctx context.Context, username string) (bool, error) {
+	suspended, found, err := uc.userRdsRepo.GetUserSuspended(username)
+	if err != nil {
+		log.Println("AuthUseCase - IsUserSuspended - uc.userRdsRepo.GetUserSuspended: ", err)
+	}
+	if found {
+		return suspended, nil
+	}
+
+	user, err := uc.userRepo.GetUserByUsername(username)
+	if err != nil {
+		return false, fmt.Errorf("AuthUseCase - IsUserSuspended - uc.userRepo.GetUserByUsername: %w", err)
+	}
+	suspended = isSuspended(user)
+	if err = uc.userRdsRepo.SetUserSuspended(username, suspended, suspendedCacheTTL); err != nil {
+		log.Println("AuthUseCase - IsUserSuspended - uc.userRdsRepo.SetUserSuspended: ", err)
+	}
+	return suspended, nil
+}
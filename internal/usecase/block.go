@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+)
+
+var (
+	CannotBlockYourselfErr = errors.New("you can not block yourself")
+	BlockedByUserErr       = errors.New("this user does not accept messages or requests from you")
+)
+
+// BlockUseCase block / unblock user
+type BlockUseCase struct {
+	blockRepo BlockRepo
+	uRepo     UserRepo
+	pubSub    PubSubRedis
+	usrRedis  UserRedisRepo
+}
+
+func NewBlockUseCase(blockRepo BlockRepo, uRepo UserRepo, pubSub PubSubRedis, usrRedis UserRedisRepo) *BlockUseCase {
+	return &BlockUseCase{
+		blockRepo: blockRepo,
+		uRepo:     uRepo,
+		pubSub:    pubSub,
+		usrRedis:  usrRedis,
+	}
+}
+
+// GetBlockedUsers mendapatkan semua user yang diblokir user
+func (uc *BlockUseCase) GetBlockedUsers(ctx context.Context, username string) ([]entity.BlockedUser, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("BlockUseCase - GetBlockedUsers - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	blocked, err := uc.blockRepo.GetBlockedUsers(ctx, userLogin.Id)
+	if err != nil {
+		return nil, fmt.Errorf("BlockUseCase - GetBlockedUsers - uc.blockRepo.GetBlockedUsers: %w", err)
+	}
+	return blocked, nil
+}
+
+// Block memblokir user. User yang diblokir tidak bisa mengirim private chat & friend request,
+// menambahkan user ke group dan melihat status online user. Pesan group di group yang sama tetap dikirim.
+// Jika user yang diblokir kontak user, user langsung terlihat offline oleh user yang diblokir
+func (uc *BlockUseCase) Block(ctx context.Context, e entity.BlockReqUc) (entity.BlockedUser, error) {
+	userLogin, blocked, err := uc.users(e)
+	if err != nil {
+		return entity.BlockedUser{}, fmt.Errorf("BlockUseCase - Block - uc.users: %w", err)
+	}
+	if err = uc.blockRepo.Block(ctx, userLogin.Id, blocked.Id); err != nil {
+		return entity.BlockedUser{}, fmt.Errorf("BlockUseCase - Block - uc.blockRepo.Block: %w", err)
+	}
+	if err = uc.uRepo.GetUserFriend(ctx, blocked.Username, userLogin.Username); err == nil {
+		uc.notifyOffline(userLogin, blocked)
+	}
+	return entity.BlockedUser{UserId: blocked.Id, Username: blocked.Username}, nil
+}
+
+// notifyOffline mengirim status offline user ke semua koneksi websocket user yang diblokir
+func (uc *BlockUseCase) notifyOffline(user entity.GetUser, recipient entity.GetUser) {
+	msgWs := &entity.MessageWs{
+		Type: entity.MessageTypeOnlineStatusFanOut,
+		MsgOnlineStatusFanout: entity.MessageOnlineStatusFanout{
+			FriendId:          user.Id.String(),
+			FriendUsername:    user.Username,
+			FriendEmail:       user.Email,
+			Online:            false,
+			UserToGetNotified: recipient.Username,
+		},
+	}
+
+	servers, err := uc.usrRedis.GetUserSessionServers(recipient.Id.String())
+	if err != nil {
+		log.Println("BlockUseCase - notifyOffline - uc.usrRedis.GetUserSessionServers: ", err)
+		return
+	}
+	for _, server := range servers {
+		if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+			log.Println("BlockUseCase - notifyOffline - uc.pubSub.PublishToChannel: ", err)
+		}
+	}
+}
+
+// Unblock membuka blokir user
+func (uc *BlockUseCase) Unblock(ctx context.Context, e entity.BlockReqUc) error {
+	userLogin, blocked, err := uc.users(e)
+	if err != nil {
+		return fmt.Errorf("BlockUseCase - Unblock - uc.users: %w", err)
+	}
+	if err = uc.blockRepo.Unblock(ctx, userLogin.Id, blocked.Id); err != nil {
+		return fmt.Errorf("BlockUseCase - Unblock - uc.blockRepo.Unblock: %w", err)
+	}
+	return nil
+}
+
+// users mendapatkan user login & user yang di block / unblock
+func (uc *BlockUseCase) users(e entity.BlockReqUc) (entity.GetUser, entity.GetUser, error) {
+	if e.UserName == e.BlockedUsername {
+		return entity.GetUser{}, entity.GetUser{}, CannotBlockYourselfErr
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.GetUser{}, entity.GetUser{}, err
+	}
+	blocked, err := uc.uRepo.GetUserByUsername(e.BlockedUsername)
+	if err != nil {
+		return entity.GetUser{}, entity.GetUser{}, err
+	}
+	return userLogin, blocked, nil
+}
+
+// isBlocked cek apakah blockerId memblokir blockedId.
+// Jika error, caller tidak boleh mengirim pesan (blokir tidak boleh hilang karena error db)
+func (c *ChatHub) isBlocked(blockerId uuid.UUID, blockedId uuid.UUID) (bool, error) {
+	blocked, err := c.blockRepo.IsBlocked(context.Background(), blockerId, blockedId)
+	if err != nil {
+		log.Println("isBlocked - c.blockRepo.IsBlocked: ", err)
+		return false, fmt.Errorf("isBlocked - c.blockRepo.IsBlocked: %w", err)
+	}
+	return blocked, nil
+}
+
+// blockedBy id semua user yang memblokir userId, false jika gagal diambil
+// (caller harus menganggap semua user memblokir userId)
+func (c *ChatHub) blockedBy(userId uuid.UUID) (map[uuid.UUID]bool, bool) {
+	blockerIds, err := c.blockRepo.GetBlockerIds(context.Background(), userId)
+	if err != nil {
+		log.Println("blockedBy - c.blockRepo.GetBlockerIds: ", err)
+		return nil, false
+	}
+	blockers := make(map[uuid.UUID]bool, len(blockerIds))
+	for _, blockerId := range blockerIds {
+		blockers[blockerId] = true
+	}
+	return blockers, true
+}
+
+// blockedContacts id semua user yang diblokir userId, false jika gagal diambil
+// (caller tidak boleh fanout status online userId)
+func (c *ChatHub) blockedContacts(userId uuid.UUID) (map[uuid.UUID]bool, bool) {
+	blockedUsers, err := c.blockRepo.GetBlockedUsers(context.Background(), userId)
+	if err != nil {
+		log.Println("blockedContacts - c.blockRepo.GetBlockedUsers: ", err)
+		return nil, false
+	}
+	blocked := make(map[uuid.UUID]bool, len(blockedUsers))
+	for _, blockedUser := range blockedUsers {
+		blocked[blockedUser.UserId] = true
+	}
+	return blocked, true
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"strings"
+)
+
+const (
+	defaultChannelMessagesLimit = 50
+	maxChannelMessagesLimit     = 100
+)
+
+var (
+	InvalidChannelNameErr      = errors.New("channel name can not be empty")
+	ChannelPermissionDeniedErr = errors.New("only the channel owner can do this")
+	InvalidChannelRoleErr      = errors.New("role must be admin or subscriber")
+	OwnerCannotUnsubscribeErr  = errors.New("the channel owner can not unsubscribe")
+	CannotChangeOwnChannelRole = errors.New("you can not change your own role")
+)
+
+// ChannelUseCase bussines logic channel broadcast, posting pesan lewat websocket (lihat ChatHub.publishChannelPost)
+type ChannelUseCase struct {
+	chRepo   ChannelRepo
+	uRepo    UserRepo
+	pubSub   PubSubRedis
+	usrRedis UserRedisRepo
+}
+
+func NewChannelUseCase(chRepo ChannelRepo, uRepo UserRepo, pubSub PubSubRedis, usrRedis UserRedisRepo) *ChannelUseCase {
+	return &ChannelUseCase{
+		chRepo:   chRepo,
+		uRepo:    uRepo,
+		pubSub:   pubSub,
+		usrRedis: usrRedis,
+	}
+}
+
+// CreateChannel membuat channel baru, pembuat channel menjadi owner
+func (uc *ChannelUseCase) CreateChannel(ctx context.Context, e entity.CreateChannelReqUc) (entity.Channel, error) {
+	name := strings.TrimSpace(e.Name)
+	if name == "" {
+		return entity.Channel{}, fmt.Errorf("ChannelUseCase - CreateChannel: %w", InvalidChannelNameErr)
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.Channel{}, fmt.Errorf("ChannelUseCase - CreateChannel - uc.uRepo.GetUserByUsername: %w", err)
+	}
+
+	channel, err := uc.chRepo.CreateChannel(ctx, entity.Channel{
+		Name:        name,
+		Description: e.Description,
+		OwnerId:     userLogin.Id,
+	})
+	if err != nil {
+		return entity.Channel{}, fmt.Errorf("ChannelUseCase - CreateChannel - uc.chRepo.CreateChannel: %w", err)
+	}
+	uc.notifySubscription(userLogin, channel.Id, true)
+	return channel, nil
+}
+
+// GetUserChannels mendapatkan semua channel yang di subscribe user
+func (uc *ChannelUseCase) GetUserChannels(ctx context.Context, username string) ([]entity.Channel, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("ChannelUseCase - GetUserChannels - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	channels, err := uc.chRepo.GetUserChannels(ctx, userLogin.Id)
+	if err != nil {
+		return nil, fmt.Errorf("ChannelUseCase - GetUserChannels - uc.chRepo.GetUserChannels: %w", err)
+	}
+	return channels, nil
+}
+
+// Subscribe user subscribe channel, koneksi websocket user yang online langsung menerima pesan channel
+func (uc *ChannelUseCase) Subscribe(ctx context.Context, e entity.ChannelReqUc) error {
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return fmt.Errorf("ChannelUseCase - Subscribe - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if _, err = uc.chRepo.GetChannelById(ctx, e.ChannelId); err != nil {
+		return fmt.Errorf("ChannelUseCase - Subscribe - uc.chRepo.GetChannelById: %w", err)
+	}
+
+	if err = uc.chRepo.Subscribe(ctx, e.ChannelId, userLogin.Id); err != nil {
+		return fmt.Errorf("ChannelUseCase - Subscribe - uc.chRepo.Subscribe: %w", err)
+	}
+	uc.notifySubscription(userLogin, e.ChannelId, true)
+	return nil
+}
+
+// Unsubscribe user berhenti subscribe channel, owner tidak bisa unsubscribe
+func (uc *ChannelUseCase) Unsubscribe(ctx context.Context, e entity.ChannelReqUc) error {
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return fmt.Errorf("ChannelUseCase - Unsubscribe - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	role, err := uc.chRepo.GetMemberRole(ctx, e.ChannelId, userLogin.Id)
+	if err != nil {
+		return fmt.Errorf("ChannelUseCase - Unsubscribe - uc.chRepo.GetMemberRole: %w", err)
+	}
+	if role == entity.ChannelRoleOwner {
+		return fmt.Errorf("ChannelUseCase - Unsubscribe: %w", OwnerCannotUnsubscribeErr)
+	}
+
+	if err = uc.chRepo.Unsubscribe(ctx, e.ChannelId, userLogin.Id); err != nil {
+		return fmt.Errorf("ChannelUseCase - Unsubscribe - uc.chRepo.Unsubscribe: %w", err)
+	}
+	uc.notifySubscription(userLogin, e.ChannelId, false)
+	return nil
+}
+
+// ChangeMemberRole promote subscriber menjadi admin / demote admin menjadi subscriber, hanya owner yang bisa
+func (uc *ChannelUseCase) ChangeMemberRole(ctx context.Context, e entity.ChangeChannelRoleReqUc) error {
+	if e.Role != entity.ChannelRoleAdmin && e.Role != entity.ChannelRoleSubscriber {
+		return fmt.Errorf("ChannelUseCase - ChangeMemberRole: %w", InvalidChannelRoleErr)
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return fmt.Errorf("ChannelUseCase - ChangeMemberRole - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	role, err := uc.chRepo.GetMemberRole(ctx, e.ChannelId, userLogin.Id)
+	if err != nil {
+		return fmt.Errorf("ChannelUseCase - ChangeMemberRole - uc.chRepo.GetMemberRole: %w", err)
+	}
+	if role != entity.ChannelRoleOwner {
+		return fmt.Errorf("ChannelUseCase - ChangeMemberRole: %w", ChannelPermissionDeniedErr)
+	}
+	member, err := uc.uRepo.GetUserByUsername(e.Member)
+	if err != nil {
+		return fmt.Errorf("ChannelUseCase - ChangeMemberRole - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if member.Id == userLogin.Id {
+		return fmt.Errorf("ChannelUseCase - ChangeMemberRole: %w", CannotChangeOwnChannelRole)
+	}
+
+	if err = uc.chRepo.SetMemberRole(ctx, e.ChannelId, member.Id, e.Role); err != nil {
+		return fmt.Errorf("ChannelUseCase - ChangeMemberRole - uc.chRepo.SetMemberRole: %w", err)
+	}
+	return nil
+}
+
+// GetMessages history pesan channel, hanya untuk subscriber channel
+func (uc *ChannelUseCase) GetMessages(ctx context.Context, e entity.ChannelMessagesReqUc) (entity.ChannelMessages, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.ChannelMessages{}, fmt.Errorf("ChannelUseCase - GetMessages - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if _, err = uc.chRepo.GetMemberRole(ctx, e.ChannelId, userLogin.Id); err != nil {
+		return entity.ChannelMessages{}, fmt.Errorf("ChannelUseCase - GetMessages - uc.chRepo.GetMemberRole: %w", err)
+	}
+
+	limit := e.Limit
+	if limit <= 0 {
+		limit = defaultChannelMessagesLimit
+	}
+	if limit > maxChannelMessagesLimit {
+		limit = maxChannelMessagesLimit
+	}
+	msgs, err := uc.chRepo.GetMessagesAfter(ctx, e.ChannelId, e.AfterId, limit)
+	if err != nil {
+		return entity.ChannelMessages{}, fmt.Errorf("ChannelUseCase - GetMessages - uc.chRepo.GetMessagesAfter: %w", err)
+	}
+	return msgs, nil
+}
+
+// notifySubscription memberi tahu chat-server tempat user terhubung bahwa user subscribe / unsubscribe channel
+func (uc *ChannelUseCase) notifySubscription(user entity.GetUser, channelId uuid.UUID, subscribed bool) {
+	msgWs := &entity.MessageWs{
+		Type: entity.MessageTypeChannelSubscription,
+		MsgChannelSubscription: entity.MessageChannelSubscription{
+			ChannelId:         channelId,
+			Subscribed:        subscribed,
+			RecipientUsername: user.Username,
+		},
+	}
+	servers, err := uc.usrRedis.GetUserSessionServers(user.Id.String())
+	if err != nil {
+		log.Println("ChannelUseCase - notifySubscription - uc.usrRedis.GetUserSessionServers: ", err)
+		return
+	}
+	for _, server := range servers {
+		if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+			log.Println("ChannelUseCase - notifySubscription - uc.pubSub.PublishToChannel: ", err)
+		}
+	}
+}
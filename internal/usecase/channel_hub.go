@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"time"
+)
+
+const (
+	// inboxSize buffer inbox setiap koneksi websocket
+	inboxSize = 256
+	// channelPostQueueSize antrian pesan channel yang belum dikirim ke subscriber di chat-server ini
+	channelPostQueueSize = 1024
+	// channelServerHeartbeatInterval harus lebih kecil dari ttl set chat-server channel di redis
+	channelServerHeartbeatInterval = 20 * time.Second
+)
+
+// postToChannel admin / owner channel posting pesan ke channel.
+// Pesan tidak di fanout per subscriber, tapi dipublish sekali ke setiap chat-server yang punya subscriber channel yang online
+func (u *User) postToChannel(msgWs *entity.MessageWs) {
+	post := &msgWs.MsgChannelPost
+	post.SenderUsername = u.Name
+
+	userId, _ := uuid.Parse(u.UserId)
+	channel, err := u.Chat.chRepo.GetChannelById(context.Background(), post.ChannelId)
+	if err != nil {
+		post.Content = err.Error()
+		u.Write(websocket.TextMessage, msgWs)
+		return
+	}
+	role, err := u.Chat.chRepo.GetMemberRole(context.Background(), channel.Id, userId)
+	if err != nil {
+		post.Content = err.Error()
+		u.Write(websocket.TextMessage, msgWs)
+		return
+	}
+	if !role.CanPost() {
+		post.Content = ChannelPermissionDeniedErr.Error()
+		u.Write(websocket.TextMessage, msgWs)
+		return
+	}
+
+	// validasi format pesan & sanitasi markdown
+	format, content, entities, err := formatMessage(post.Format, post.Content)
+	if err != nil {
+		post.Content = err.Error()
+		u.Write(websocket.TextMessage, msgWs)
+		return
+	}
+	filterRes := u.Chat.contentFilter.Run(content, entityLinks(entities)...)
+	if filterRes.Rejected {
+		post.Content = filterRes.Reason
+		u.Write(websocket.TextMessage, msgWs)
+		return
+	}
+
+	post.MessageId, _ = u.Chat.idGen.GenerateId() // generate message id menggunakan sonyflake
+	post.ChannelName = channel.Name
+	post.Content = filterRes.Content
+	post.Format = format
+	post.Entities = entities
+	post.CreatedAt = time.Now()
+
+	_, err = u.Chat.chRepo.InsertMessage(context.Background(), entity.ChannelMessage{
+		ChannelId: channel.Id,
+		MessageId: post.MessageId,
+		UserId:    userId,
+		Content:   post.Content,
+		Format:    post.Format,
+		Entities:  post.Entities,
+		CreatedAt: post.CreatedAt,
+	})
+	if err != nil {
+		post.Content = err.Error()
+		u.Write(websocket.TextMessage, msgWs)
+		return
+	}
+
+	u.Chat.publishChannelPost(msgWs)
+}
+
+// publishChannelPost mengirim pesan channel sekali ke setiap chat-server yang ada di set chat-server channel
+func (c *ChatHub) publishChannelPost(msgWs *entity.MessageWs) {
+	servers, err := c.chRedis.GetChannelServers(msgWs.MsgChannelPost.ChannelId.String())
+	if err != nil {
+		log.Println("publishChannelPost - c.chRedis.GetChannelServers: ", err)
+		return
+	}
+	for _, server := range servers {
+		if server == entity.ChatServerNameGlobal.ChatServerName {
+			c.channelPosts <- msgWs
+			continue
+		}
+		c.PubSub.PublishToChannel(server, msgWs)
+	}
+}
+
+// RunChannelDelivery mengirim pesan channel ke subscriber secara berurutan di luar loop Run,
+// agar channel dengan banyak subscriber tidak menahan register & broadcast user lain
+func (c *ChatHub) RunChannelDelivery() {
+	for msgWs := range c.channelPosts {
+		c.deliverChannelPost(msgWs)
+	}
+}
+
+// RunChannelServerHeartbeat memperbarui ttl chat-server ini di set chat-server semua channel yang punya subscriber online,
+// chat-server yang crash otomatis tidak dianggap punya subscriber setelah ttl habis
+func (c *ChatHub) RunChannelServerHeartbeat() {
+	ticker := time.NewTicker(channelServerHeartbeatInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		c.channelMu.Lock()
+		channelIds := make([]string, 0, len(c.channelSubs))
+		for channelId := range c.channelSubs {
+			channelIds = append(channelIds, channelId.String())
+		}
+		c.channelMu.Unlock()
+
+		if err := c.chRedis.RefreshChannelServers(channelIds); err != nil {
+			log.Println("RunChannelServerHeartbeat - c.chRedis.RefreshChannelServers: ", err)
+		}
+	}
+}
+
+// deliverChannelPost mengirim pesan channel ke semua subscriber channel yang terhubung ke chat-server ini
+func (c *ChatHub) deliverChannelPost(msgWs *entity.MessageWs) {
+	c.channelMu.Lock()
+	subs := make([]*User, 0, len(c.channelSubs[msgWs.MsgChannelPost.ChannelId]))
+	for user := range c.channelSubs[msgWs.MsgChannelPost.ChannelId] {
+		subs = append(subs, user)
+	}
+	c.channelMu.Unlock()
+
+	for _, user := range subs {
+		select {
+		case user.inbox <- msgWs:
+		default:
+			// inbox penuh (client lambat), pesan tidak ditunggu agar subscriber lain tetap menerima pesan
+			log.Println("deliverChannelPost - inbox full, dropping channel post for: ", user.Name)
+		}
+	}
+}
+
+// updateChannelSubscription memperbarui subscriber channel yang online di chat-server ini
+// setelah user subscribe / unsubscribe channel lewat REST api, lalu meneruskan message ke client.
+// Dijalankan di goroutine sendiri karena ada I/O redis
+func (c *ChatHub) updateChannelSubscription(msgWs *entity.MessageWs) {
+	sub := msgWs.MsgChannelSubscription
+	c.mu.RLock()
+	var users []*User
+	for _, user := range c.us {
+		if user.Name == sub.RecipientUsername {
+			users = append(users, user)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, user := range users {
+		if sub.Subscribed {
+			c.subscribeChannels(user, []uuid.UUID{sub.ChannelId})
+		} else {
+			c.unsubscribeChannels(user, []uuid.UUID{sub.ChannelId})
+		}
+		select {
+		case user.inbox <- msgWs:
+		default:
+			log.Println("updateChannelSubscription - inbox full, dropping message for: ", user.Name)
+		}
+	}
+}
+
+// joinUserChannels mendaftarkan koneksi user baru ke semua channel yang di subscribe user
+func (c *ChatHub) joinUserChannels(user *User) {
+	userId, _ := uuid.Parse(user.UserId)
+	channelIds, err := c.chRepo.GetSubscribedChannelIds(context.Background(), userId)
+	if err != nil {
+		log.Println("joinUserChannels - c.chRepo.GetSubscribedChannelIds: ", err)
+		return
+	}
+	c.subscribeChannels(user, channelIds)
+}
+
+// leaveUserChannels menghapus koneksi user dari semua channel ketika koneksi websocket ditutup
+func (c *ChatHub) leaveUserChannels(user *User) {
+	c.channelMu.Lock()
+	// koneksi tidak didaftarkan lagi oleh updateChannelSubscription yang berjalan bersamaan
+	user.channelsLeft = true
+	var channelIds []uuid.UUID
+	for channelId, subs := range c.channelSubs {
+		if subs[user] {
+			channelIds = append(channelIds, channelId)
+		}
+	}
+	c.channelMu.Unlock()
+	c.unsubscribeChannels(user, channelIds)
+}
+
+// subscribeChannels chat-server ini ditambahkan ke set chat-server channel ketika subscriber pertama channel terhubung
+func (c *ChatHub) subscribeChannels(user *User, channelIds []uuid.UUID) {
+	for _, channelId := range channelIds {
+		c.channelServerMu.Lock()
+		c.channelMu.Lock()
+		if user.channelsLeft {
+			c.channelMu.Unlock()
+			c.channelServerMu.Unlock()
+			return
+		}
+		subs, ok := c.channelSubs[channelId]
+		if !ok {
+			subs = make(map[*User]bool)
+			c.channelSubs[channelId] = subs
+		}
+		subs[user] = true
+		first := !ok
+		c.channelMu.Unlock()
+
+		if first {
+			if err := c.chRedis.AddChannelServer(channelId.String()); err != nil {
+				log.Println("subscribeChannels - c.chRedis.AddChannelServer: ", err)
+			}
+		}
+		c.channelServerMu.Unlock()
+	}
+}
+
+// unsubscribeChannels chat-server ini dihapus dari set chat-server channel ketika sudah tidak ada subscriber channel yang online
+func (c *ChatHub) unsubscribeChannels(user *User, channelIds []uuid.UUID) {
+	for _, channelId := range channelIds {
+		c.channelServerMu.Lock()
+		c.channelMu.Lock()
+		subs, ok := c.channelSubs[channelId]
+		if !ok {
+			c.channelMu.Unlock()
+			c.channelServerMu.Unlock()
+			continue
+		}
+		delete(subs, user)
+		last := len(subs) == 0
+		if last {
+			delete(c.channelSubs, channelId)
+		}
+		c.channelMu.Unlock()
+
+		if last {
+			if err := c.chRedis.RemoveChannelServer(channelId.String()); err != nil {
+				log.Println("unsubscribeChannels - c.chRedis.RemoveChannelServer: ", err)
+			}
+		}
+		c.channelServerMu.Unlock()
+	}
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/lintangbs/chat-be/internal/entity"
 	sonyflake2 "github.com/lintangbs/chat-be/internal/util/sonyflake"
@@ -24,28 +25,52 @@ type User struct {
 	io   sync.Mutex
 	Conn *websocket.Conn
 
-	Id   uint
-	Name string
-	Chat *ChatHub
+	Id        uint
+	Name      string
+	UserId    string
+	SessionId string // id unik setiap koneksi websocket, 1 user bisa punya banyak koneksi (device)
+	Chat      *ChatHub
 
 	inbox chan *entity.MessageWs
+
+	// koneksi sudah keluar dari semua channel, dilindungi ChatHub.channelMu
+	channelsLeft bool
 }
 
 var sf *sonyflake.Sonyflake
 
 // ChatHub utk menyimpan semua client websocket yang terhubung ke chat-server ini
 type ChatHub struct {
-	mu        sync.RWMutex
-	seq       uint
-	PubSub    PubSubRedis
-	Rds       *redispkg.Redis
-	edenAiApi EdenAiApi
-	userPg    UserRepo
-	usrRedis  UserRedisRepo
-	pChat     PrivateChatRepo
-	idGen     sonyflake2.IdGenerator
-	gpRepo    GroupRepo
-	gcRepo    GroupChatRepo
+	mu            sync.RWMutex
+	seq           uint
+	PubSub        PubSubRedis
+	Rds           *redispkg.Redis
+	edenAiApi     EdenAiApi
+	userPg        UserRepo
+	usrRedis      UserRedisRepo
+	pChat         PrivateChatRepo
+	idGen         sonyflake2.IdGenerator
+	gpRepo        GroupRepo
+	gRedis        GroupRedisRepo
+	gcRepo        GroupChatRepo
+	settingRepo   ConversationSettingRepo
+	blockRepo     BlockRepo
+	profileRepo   UserProfileRepo
+	idleTimeout   time.Duration
+	groupLimits   entity.GroupLimits
+	draftRepo     DraftRepo
+	reportRepo    ReportRepo
+	contentFilter ContentFilter
+	chRepo        ChannelRepo
+	chRedis       ChannelRedisRepo
+
+	// subscriber channel yang terhubung ke chat-server ini
+	channelMu   sync.Mutex
+	channelSubs map[uuid.UUID]map[*User]bool
+	// menjaga urutan subscriber pertama / terakhir channel dengan SADD / SREM set chat-server channel di redis
+	channelServerMu sync.Mutex
+	// pesan channel yang dikirim ke subscriber oleh RunChannelDelivery, di luar loop Run
+	channelPosts chan *entity.MessageWs
 
 	us        []*User
 	broadcast chan *entity.MessageWs
@@ -66,21 +91,45 @@ func NewChat(pubSub PubSubRedis,
 	idGen sonyflake2.IdGenerator,
 	gpRepo GroupRepo,
 	gcRepo GroupChatRepo,
+	draftRepo DraftRepo,
+	reportRepo ReportRepo,
+	contentFilter ContentFilter,
+	chRepo ChannelRepo,
+	chRedis ChannelRedisRepo,
+	gRedis GroupRedisRepo,
+	settingRepo ConversationSettingRepo,
+	blockRepo BlockRepo,
+	profileRepo UserProfileRepo,
+	groupLimits entity.GroupLimits,
+	idleTimeout time.Duration,
 ) *ChatHub {
 
 	return &ChatHub{PubSub: pubSub,
 
-		edenAiApi:  ed,
-		userPg:     userPg,
-		Rds:        rds,
-		usrRedis:   ud,
-		broadcast:  make(chan *entity.MessageWs),
-		unregister: make(chan *User),
-		register:   make(chan *User),
-		pChat:      pc,
-		idGen:      idGen,
-		gpRepo:     gpRepo,
-		gcRepo:     gcRepo,
+		edenAiApi:     ed,
+		userPg:        userPg,
+		Rds:           rds,
+		usrRedis:      ud,
+		broadcast:     make(chan *entity.MessageWs),
+		unregister:    make(chan *User),
+		register:      make(chan *User),
+		pChat:         pc,
+		idGen:         idGen,
+		gpRepo:        gpRepo,
+		gcRepo:        gcRepo,
+		draftRepo:     draftRepo,
+		reportRepo:    reportRepo,
+		contentFilter: contentFilter,
+		chRepo:        chRepo,
+		chRedis:       chRedis,
+		gRedis:        gRedis,
+		settingRepo:   settingRepo,
+		blockRepo:     blockRepo,
+		profileRepo:   profileRepo,
+		idleTimeout:   idleTimeout,
+		groupLimits:   groupLimits,
+		channelSubs:   make(map[uuid.UUID]map[*User]bool),
+		channelPosts:  make(chan *entity.MessageWs, channelPostQueueSize),
 	}
 }
 
@@ -88,11 +137,12 @@ func (c *ChatHub) Run() {
 	for {
 		select {
 		case user := <-c.register:
+			c.mu.Lock()
 			user.Id = c.seq
-			user.Name = user.Name
 
 			c.us = append(c.us, user)
 			c.seq++
+			c.mu.Unlock()
 
 		case user := <-c.unregister:
 			c.mu.Lock()
@@ -108,6 +158,11 @@ func (c *ChatHub) Run() {
 			c.us = without
 			c.mu.Unlock()
 
+			// kurangi jumlah koneksi user di chat-server ini,
+			// I/O redis & db di luar loop agar tidak menahan register & broadcast user lain
+			go c.removeSessionServer(user)
+			go c.leaveUserChannels(user)
+
 		case message := <-c.broadcast:
 			// menerima message da	ri user lain yg chat-servernya sama dg user
 			// mengirim ke user dg username sama dg recipient username di messageWs
@@ -117,6 +172,29 @@ func (c *ChatHub) Run() {
 }
 
 func (c *ChatHub) sendToSpecificUserInboxInServer(message *entity.MessageWs) {
+	switch message.Type {
+	case entity.MessageTypeChannelPost:
+		// pesan channel dikirim ke subscriber channel, bukan berdasarkan recipient username
+		c.channelPosts <- message
+		return
+	case entity.MessageTypeChannelSubscription:
+		go c.updateChannelSubscription(message)
+		return
+	case entity.MessageTypeTyping:
+		c.deliverTyping(message)
+		return
+	case entity.MessageTypeGroupChat:
+		if len(message.MsgGroupChat.RecipientUsernames) > 0 {
+			c.deliverGroupChat(message)
+			return
+		}
+	case entity.MessageTypeGroupChatBot:
+		if len(message.MsgGroupChatBot.RecipientUsernames) > 0 {
+			c.deliverGroupChatBot(message)
+			return
+		}
+	}
+
 	for _, user := range c.us {
 
 		// mengirim ke user dg username sama dg recipient username di messageWs
@@ -124,6 +202,12 @@ func (c *ChatHub) sendToSpecificUserInboxInServer(message *entity.MessageWs) {
 		rcpFanoutUsername := message.MsgOnlineStatusFanout.UserToGetNotified
 		rcpGroupChat := message.MsgGroupChat.RecipientUsername
 		rcpGroupChatBot := message.MsgGroupChatBot.RecipientUsername
+		rcpDraft := message.MsgDraft.RecipientUsername
+		rcpForceDisconnect := message.MsgForceDisconnect.RecipientUsername
+		rcpGroupJoinRequest := message.MsgGroupJoinRequest.RecipientUsername
+		rcpGroupUpdated := message.MsgGroupUpdated.RecipientUsername
+		rcpFriendRequest := message.MsgFriendRequest.RecipientUsername
+		rcpProfileUpdated := message.MsgProfileUpdated.RecipientUsername
 
 		switch message.Type {
 		case entity.MessageTypePrivateChat:
@@ -151,6 +235,43 @@ func (c *ChatHub) sendToSpecificUserInboxInServer(message *entity.MessageWs) {
 				case user.inbox <- message:
 				}
 			}
+		case entity.MessageTypeDraftUpdate:
+			// draft hanya dikirim ke koneksi lain milik user, tidak ke koneksi yg mengirim draft
+			if user.Name == rcpDraft && user.SessionId != message.MsgDraft.OriginSessionId {
+				select {
+				case user.inbox <- message:
+				}
+			}
+		case entity.MessageTypeForceDisconnect:
+			if user.Name == rcpForceDisconnect {
+				select {
+				case user.inbox <- message:
+				}
+			}
+		case entity.MessageTypeGroupJoinRequest, entity.MessageTypeGroupJoinDecision:
+			if user.Name == rcpGroupJoinRequest {
+				select {
+				case user.inbox <- message:
+				}
+			}
+		case entity.MessageTypeGroupUpdated:
+			if user.Name == rcpGroupUpdated {
+				select {
+				case user.inbox <- message:
+				}
+			}
+		case entity.MessageTypeFriendRequest:
+			if user.Name == rcpFriendRequest {
+				select {
+				case user.inbox <- message:
+				}
+			}
+		case entity.MessageTypeProfileUpdated:
+			if user.Name == rcpProfileUpdated {
+				select {
+				case user.inbox <- message:
+				}
+			}
 		}
 	}
 }
@@ -203,6 +324,10 @@ func (u *User) Receive() error {
 			break
 		}
 
+		if isUserActivity(msgWs.Type) {
+			u.Chat.MarkUserActive(u.UserId, u.Name)
+		}
+
 		switch msgWs.Type {
 		case entity.MessageTypePrivateChatBot:
 			// jika tipe message dari frontend adalah privateChatBot
@@ -227,6 +352,8 @@ func (u *User) Receive() error {
 			// jika tipe message dari frontend private chat dg user lain yang sudah ditambahkan kontaknya
 			msgWs.PrivateChat.MessageId, _ = u.Chat.idGen.GenerateId()
 			msgWs.PrivateChat.CreatedAt = time.Now()
+			// sender selalu user koneksi ini, sender_username dari client tidak dipakai
+			msgWs.PrivateChat.SenderUsername = u.Name
 			friendUsername := msgWs.PrivateChat.RecipientUsername
 
 			isFriendErr := u.Chat.userPg.GetUserFriend(
@@ -246,9 +373,53 @@ func (u *User) Receive() error {
 				continue
 
 			}
-			friend, _ := u.Chat.userPg.GetUserByUsername(friendUsername)
+			// validasi format pesan & sanitasi markdown
+			format, content, entities, err := formatMessage(msgWs.PrivateChat.Format, msgWs.PrivateChat.Message)
+			if err != nil {
+				msgWs.PrivateChat.Message = err.Error()
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			msgWs.PrivateChat.Format = format
+			msgWs.PrivateChat.Entities = entities
+
+			// content filter sebelum pesan disimpan & dikirim
+			filterRes := u.Chat.contentFilter.Run(content, entityLinks(entities)...)
+			if filterRes.Rejected {
+				msgWs.PrivateChat.Message = filterRes.Reason
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			msgWs.PrivateChat.Message = filterRes.Content
+
+			friend, err := u.Chat.userPg.GetUserByUsername(friendUsername)
+			if err != nil {
+				msgWs.PrivateChat.Message = err.Error()
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
 			isFriendInSameServer, friendServerLocation := u.Chat.isFriendInSameServer(friend.Id.String())
-			sender, err := u.Chat.userPg.GetUserByUsername(msgWs.PrivateChat.SenderUsername)
+			sender, err := u.Chat.userPg.GetUserByUsername(u.Name)
+			if err != nil {
+				msgWs.PrivateChat.Message = err.Error()
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			blocked, err := u.Chat.isBlocked(friend.Id, sender.Id)
+			if err != nil {
+				// status blokir tidak bisa dicek, pesan tidak dikirim
+				msgWs.PrivateChat.Message = err.Error()
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			if blocked {
+				// sender diblokir recipient, pesan tidak disimpan & tidak dikirim
+				msgWs.PrivateChat.Message = BlockedByUserErr.Error()
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			// pesan tetap dikirim walaupun friend mute percakapan, hanya ditandai muted
+			msgWs.PrivateChat.Muted = u.Chat.isConversationMuted(friend.Id, entity.ConversationTypePrivate, sender.Id)
 
 			//	 Save Private Chat message to db
 			pc := entity.InsertPrivateChatRequest{
@@ -256,11 +427,20 @@ func (u *User) Receive() error {
 				MessageTo:   friend.Id,
 				MessageFrom: sender.Id,
 				Content:     msgWs.PrivateChat.Message,
+				Format:      msgWs.PrivateChat.Format,
+				Entities:    msgWs.PrivateChat.Entities,
 			}
 			_, err = u.Chat.pChat.InsertPrivateChat(pc)
 			if err != nil {
 				log.Println("Recive() - u.Chat.pChat.InsertPrivateChat:", err)
 			}
+			u.Chat.flagMessage(filterRes.Flags, entity.Report{
+				ReportedUserId:   sender.Id,
+				ConversationType: entity.ConversationTypePrivate,
+				MessageId:        msgWs.PrivateChat.MessageId,
+			})
+			// pesan sudah terkirim, hapus draft di semua device user
+			u.clearDraft(entity.ConversationTypePrivate, friend.Id, friend.Username, "")
 			if isFriendInSameServer == true {
 				// Jika friend/recipient message berada di chat-server yg sama dg chat-server user
 				u.Chat.broadcast <- msgWs
@@ -273,8 +453,12 @@ func (u *User) Receive() error {
 		case entity.MessageTypeGroupChat:
 			// Jika tipe message dari frontend adalah group chat
 			msgWs.MsgGroupChat.MessageId, _ = u.Chat.idGen.GenerateId() // generate message id menggunakan sonyflake
+			// system message hanya dibuat oleh server
+			msgWs.MsgGroupChat.Kind = ""
+			// sender selalu user koneksi ini, sender_username dari client tidak dipakai untuk cek permission
+			msgWs.MsgGroupChat.SenderUsername = u.Name
 			// mendapatkan entitas user sender dari db
-			sender, err := u.Chat.userPg.GetUserByUsername(msgWs.MsgGroupChat.SenderUsername)
+			sender, err := u.Chat.userPg.GetUserByUsername(u.Name)
 			if err != nil {
 				msgWs.MsgGroupChat.Content = err.Error()
 				msgWs.PrivateChat.CreatedAt = time.Now()
@@ -282,76 +466,113 @@ func (u *User) Receive() error {
 				continue
 			}
 			// mendapatkan entitas group dari db
-			groupDb, err := u.Chat.gpRepo.GetGroupByName(msgWs.MsgGroupChat.GroupName, sender.Id)
+			groupDb, err := findGroup(u.Chat.gpRepo, msgWs.MsgGroupChat.GroupId, msgWs.MsgGroupChat.GroupName, sender.Id)
 			if err != nil {
 				msgWs.MsgGroupChat.Content = err.Error()
 				msgWs.PrivateChat.CreatedAt = time.Now()
 				u.Write(websocket.TextMessage, msgWs)
 				continue
 			}
-			// mendapatkan groupchat members
-			group, err := u.Chat.gpRepo.GetGroupMembers(groupDb.Id, sender.Id)
+			// client lama hanya mengirim group_name, client baru mendapat group_id & nama group terbaru
+			msgWs.MsgGroupChat.GroupId = groupDb.Id
+			msgWs.MsgGroupChat.GroupName = groupDb.Name
+			// mendapatkan groupchat members dari cache
+			members, err := groupRecipients(u.Chat.gpRepo, u.Chat.gRedis, groupDb.Id)
 			if err != nil {
 				msgWs.MsgGroupChat.Content = err.Error()
 				msgWs.PrivateChat.CreatedAt = time.Now()
 				u.Write(websocket.TextMessage, msgWs)
 				continue
 			}
+			// validasi format pesan & sanitasi markdown
+			format, content, entities, err := formatMessage(msgWs.MsgGroupChat.Format, msgWs.MsgGroupChat.Content)
+			if err != nil {
+				msgWs.MsgGroupChat.Content = err.Error()
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			msgWs.MsgGroupChat.Format = format
+			msgWs.MsgGroupChat.Entities = entities
+
+			// content filter sebelum pesan disimpan & dikirim
+			filterRes := u.Chat.contentFilter.Run(content, entityLinks(entities)...)
+			if filterRes.Rejected {
+				msgWs.MsgGroupChat.Content = filterRes.Reason
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			msgWs.MsgGroupChat.Content = filterRes.Content
 
 			gcMessageDb := entity.GroupChatMessage{
 				GroupId:   groupDb.Id,
 				MessageId: msgWs.MsgGroupChat.MessageId,
 				UserId:    sender.Id,
 				Content:   msgWs.MsgGroupChat.Content,
+				Format:    msgWs.MsgGroupChat.Format,
+				Entities:  msgWs.MsgGroupChat.Entities,
 			}
 			// inser chat ke table groupchat
 			u.Chat.gcRepo.InsertNewChat(gcMessageDb)
+			u.Chat.flagMessage(filterRes.Flags, entity.Report{
+				ReportedUserId:   sender.Id,
+				ConversationType: entity.ConversationTypeGroup,
+				MessageId:        msgWs.MsgGroupChat.MessageId,
+				GroupId:          groupDb.Id,
+			})
+			// pesan sudah terkirim, hapus draft di semua device user
+			u.clearDraft(entity.ConversationTypeGroup, groupDb.Id, "", groupDb.Name)
 
 			// fanout message ke semua member group chat
-			groupMembers := group.Members
-			for _, memberId := range groupMembers {
-				friend, _ := u.Chat.userPg.GetUserById(memberId)
-				isFriendInSameServer, friendServerLocation := u.Chat.isFriendInSameServer(memberId.String())
-				msgWs.MsgGroupChat.RecipientUsername = friend.Username
-				if friend.Username == msgWs.MsgGroupChat.SenderUsername {
-					continue
-				}
-				if isFriendInSameServer == true {
-					// Jika friend/recipient message berada di chat-server yg sama dg chat-server user
-					u.Chat.broadcast <- msgWs
-					continue
-				}
-
-				// jika teman user berada di server yg berbeda dg server user sender
-				// publish ke chat-server teman
-				u.Chat.PubSub.PublishToChannel(friendServerLocation, msgWs)
-			}
+			u.Chat.FanoutGroupChat(msgWs, members, msgWs.MsgGroupChat.SenderUsername)
 
 		case entity.MessageTypeGroupChatBot:
 			msgWs.MsgGroupChatBot.MessageId, _ = u.Chat.idGen.GenerateId() // generate message id menggunakan sonyflake
 			msgWs.MsgGroupChatBot.CreatedAt = time.Now()
-			resTextChatBot, err := u.Chat.edenAiApi.GenerateText(msgWs.MsgGroupChatBot.Content)
+			// sender selalu user koneksi ini, sender_username dari client tidak dipakai untuk cek permission
+			msgWs.MsgGroupChatBot.SenderUsername = u.Name
+			sender, err := u.Chat.userPg.GetUserByUsername(u.Name)
 			if err != nil {
-				err = u.Write(websocket.TextMessage, msgWs)
+				msgWs.MsgGroupChatBot.Content = err.Error()
+				u.Write(websocket.TextMessage, msgWs)
 				continue
 			}
-			sender, err := u.Chat.userPg.GetUserByUsername(msgWs.MsgGroupChatBot.SenderUsername)
+			groupDb, err := findGroup(u.Chat.gpRepo, msgWs.MsgGroupChatBot.GroupId, msgWs.MsgGroupChatBot.GroupName, sender.Id)
 			if err != nil {
 				msgWs.MsgGroupChatBot.Content = err.Error()
 				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			msgWs.MsgGroupChatBot.GroupId = groupDb.Id
+			msgWs.MsgGroupChatBot.GroupName = groupDb.Name
+			// cek permission invoke chatbot sesuai role user di group
+			role, err := u.Chat.gpRepo.GetMemberRole(groupDb.Id, sender.Id)
+			if err == nil && !role.Can(entity.GroupPermissionInvokeChatbot) {
+				err = GroupPermissionDeniedErr
 			}
-			groupDb, err := u.Chat.gpRepo.GetGroupByName(msgWs.MsgGroupChatBot.GroupName, sender.Id)
 			if err != nil {
 				msgWs.MsgGroupChatBot.Content = err.Error()
 				u.Write(websocket.TextMessage, msgWs)
 				continue
 			}
-			group, err := u.Chat.gpRepo.GetGroupMembers(groupDb.Id, sender.Id)
+			groupMembers, err := groupRecipients(u.Chat.gpRepo, u.Chat.gRedis, groupDb.Id)
 			if err != nil {
 				msgWs.MsgGroupChatBot.Content = err.Error()
 				u.Write(websocket.TextMessage, msgWs)
 				continue
 			}
+			// content filter prompt sebelum dikirim ke chatbot, disimpan & dikirim ke member group
+			filterRes := u.Chat.contentFilter.Run(msgWs.MsgGroupChatBot.Content)
+			if filterRes.Rejected {
+				msgWs.MsgGroupChatBot.Content = filterRes.Reason
+				u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
+			msgWs.MsgGroupChatBot.Content = filterRes.Content
+			resTextChatBot, err := u.Chat.edenAiApi.GenerateText(msgWs.MsgGroupChatBot.Content)
+			if err != nil {
+				err = u.Write(websocket.TextMessage, msgWs)
+				continue
+			}
 
 			// insert message prompt dari user
 			gcMessageDb := entity.GroupChatMessage{
@@ -361,6 +582,12 @@ func (u *User) Receive() error {
 				Content:   msgWs.MsgGroupChatBot.Content,
 			}
 			u.Chat.gcRepo.InsertNewChat(gcMessageDb)
+			u.Chat.flagMessage(filterRes.Flags, entity.Report{
+				ReportedUserId:   sender.Id,
+				ConversationType: entity.ConversationTypeGroup,
+				MessageId:        msgWs.MsgGroupChatBot.MessageId,
+				GroupId:          groupDb.Id,
+			})
 
 			// insert message jawaban chatbot
 			newMsgId, _ := u.Chat.idGen.GenerateId()
@@ -373,28 +600,22 @@ func (u *User) Receive() error {
 			u.Chat.gcRepo.InsertNewChat(gcMessageDb)
 
 			//	fanout messsage ke semua member group
-			groupMembers := group.Members
 			msgWs.MsgGroupChatBot.SenderUsername = "ChatBot-edenAI-GPT"
 			msgWs.MsgGroupChatBot.Content = resTextChatBot
 			u.Write(websocket.TextMessage, msgWs)
-			for _, memberId := range groupMembers {
-				friend, _ := u.Chat.userPg.GetUserById(memberId)
-				isFriendInSameServer, friendServerLocation := u.Chat.isFriendInSameServer(memberId.String())
-				msgWs.MsgGroupChatBot.RecipientUsername = friend.Username
-				if friend.Username == msgWs.MsgGroupChatBot.SenderUsername {
-					continue
-				}
-				if isFriendInSameServer == true {
-					// Jika friend/recipient message berada di chat-server yg sama dg chat-server user
-					u.Chat.broadcast <- msgWs
-					continue
-				}
+			u.Chat.FanoutGroupChatBot(msgWs, groupMembers, u.Name)
 
-				// jika teman user berada di server yg berbeda dg server user sender
-				// publish ke chat-server teman
-				u.Chat.PubSub.PublishToChannel(friendServerLocation, msgWs)
-			}
+		case entity.MessageTypeDraftUpdate:
+			// draft pesan yang belum dikirim, disinkronkan ke semua device user
+			u.updateDraft(msgWs)
 
+		case entity.MessageTypeChannelPost:
+			// posting pesan ke channel broadcast, hanya owner & admin channel
+			u.postToChannel(msgWs)
+
+		case entity.MessageTypeTyping:
+			// typing indicator private chat / group
+			u.sendTyping(msgWs)
 		}
 	}
 	return nil
@@ -412,6 +633,10 @@ func (u *User) pongHandler(pongMsg string) error {
 
 	// Set User online in Redis
 	u.Chat.usrRedis.UserSetOnline(user.Id.String())
+	// heartbeat koneksi user di chat-server ini
+	if err = u.Chat.usrRedis.RefreshUserSessionServer(user.Id.String()); err != nil {
+		log.Println("pongHandler - u.Chat.usrRedis.RefreshUserSessionServer: ", err)
+	}
 
 	// Fanout User Online Status ke semua kontaknya
 	u.Chat.userOnlineStatusFanout(u.Name, true)
@@ -432,10 +657,29 @@ func (c *ChatHub) isFriendInSameServer(friendId string) (bool, string) {
 }
 
 // userOnlineStatusFanout fanout user online status ke semua kontaknya
+// yang diizinkan melihat status online user oleh setting privacy user
 func (c *ChatHub) userOnlineStatusFanout(username string, online bool) {
 	// Fanout User Online Status ke semua kontaknya
 	userDb, _ := c.userPg.GetUserFriends(context.Background(), username)
+	// kontak yang diblokir user tidak boleh melihat status online user
+	blocked, ok := c.blockedContacts(userDb.Id)
+	if !ok {
+		return
+	}
+	settings, ok := c.privacySettings(userDb.Id)
+	if !ok {
+		return
+	}
+	status := c.userStatus(userDb.Id.String())
+	var lastSeenAt *time.Time
+	if !online {
+		now := time.Now()
+		lastSeenAt = &now
+	}
 	for _, uFriend := range userDb.Friends {
+		if blocked[uFriend.Id] || !settings.CanSeeOnlineStatus(uFriend.Id, true) {
+			continue
+		}
 		// send notification ke semua kontaknya bahwa user masih online
 		// user yg online (user yang mengirim pong message)
 		msgOnlineStatusFanout := entity.MessageOnlineStatusFanout{
@@ -443,45 +687,90 @@ func (c *ChatHub) userOnlineStatusFanout(username string, online bool) {
 			FriendUsername:    userDb.Username,
 			FriendEmail:       userDb.Email,
 			Online:            online,
+			Status:            &status,
 			UserToGetNotified: uFriend.Username,
 		}
+		if settings.CanSeeLastSeen(uFriend.Id, true) {
+			msgOnlineStatusFanout.LastSeenAt = lastSeenAt
+		}
 		msgWs := &entity.MessageWs{
 			Type:                  entity.MessageTypeOnlineStatusFanOut,
 			MsgOnlineStatusFanout: msgOnlineStatusFanout,
 		}
-		isFriendInSameServer, friendServerLocation := c.isFriendInSameServer(uFriend.Id.String())
-		if isFriendInSameServer == true {
-			// Jika friend/recipient message berada di chat-server yg sama dg chat-server user sender
-			c.broadcast <- msgWs
-			continue
-		}
+		c.sendOnlineStatus(uFriend.Id.String(), msgWs)
+	}
+}
 
-		// jika chat server friend/recipient berbeda dg chat-server user sender
-		c.PubSub.PublishToChannel(friendServerLocation, msgWs)
+// sendOnlineStatus mengirim status online user ke 1 kontak di chat-server tempat kontak terhubung
+func (c *ChatHub) sendOnlineStatus(friendId string, msgWs *entity.MessageWs) {
+	isFriendInSameServer, friendServerLocation := c.isFriendInSameServer(friendId)
+	if isFriendInSameServer == true {
+		// Jika friend/recipient message berada di chat-server yg sama dg chat-server user sender
+		c.broadcast <- msgWs
+		return
 	}
+
+	// jika chat server friend/recipient berbeda dg chat-server user sender
+	c.PubSub.PublishToChannel(friendServerLocation, msgWs)
 }
 
 // getAllFriendsOnlineStatus user akan mendapatkan status online/tidaknya semua teman/kontaknya
+// beserta status (away / busy / dnd) & last seen kontak yang offline, sesuai setting privacy masing-masing kontak
 func (u *User) getAllFriendsOnlineStatus(ctx context.Context, username string) {
 	userDb, _ := u.Chat.userPg.GetUserFriends(ctx, username)
 	totFriend := len(userDb.Friends)
 	totOnline := 0
+	// kontak yang memblokir user selalu terlihat offline, semua kontak terlihat offline jika blokir gagal diambil
+	blockers, blockersOk := u.Chat.blockedBy(userDb.Id)
+
+	friendIds := make([]uuid.UUID, len(userDb.Friends))
+	friendUserIds := make([]string, len(userDb.Friends))
+	for i, uFriend := range userDb.Friends {
+		friendIds[i] = uFriend.Id
+		friendUserIds[i] = uFriend.Id.String()
+	}
+	// status online & status semua kontak diambil sekaligus dalam 1 round-trip ke redis
+	presences, err := u.Chat.usrRedis.GetUsersPresence(friendUserIds)
+	if err != nil {
+		log.Println("getAllFriendsOnlineStatus - u.Chat.usrRedis.GetUsersPresence: ", err)
+		presences = make([]entity.UserPresence, len(friendUserIds))
+	}
+	settings, err := u.Chat.profileRepo.GetPrivacySettingsByUserIds(ctx, friendIds)
+	if err != nil {
+		log.Println("getAllFriendsOnlineStatus - u.Chat.profileRepo.GetPrivacySettingsByUserIds: ", err)
+	}
+	lastSeen, err := u.Chat.profileRepo.GetLastSeen(ctx, friendIds)
+	if err != nil {
+		log.Println("getAllFriendsOnlineStatus - u.Chat.profileRepo.GetLastSeen: ", err)
+	}
 
 	var messageWsFriendsStatus entity.MessageFriendsOnlineStatus
 	var friends []entity.Friend
 	// Set online status setiap kontak/teman  user
-	for _, uFriend := range userDb.Friends {
-		isFriendOnline := u.Chat.usrRedis.UserIsOnline(uFriend.Id.String())
+	for i, uFriend := range userDb.Friends {
+		// setting privacy kontak gagal diambil, status online kontak disembunyikan
+		setting, ok := settings[uFriend.Id]
+		canSeePresence := ok && blockersOk && !blockers[uFriend.Id] && setting.CanSeeOnlineStatus(userDb.Id, true)
+		isFriendOnline := canSeePresence && presences[i].Online
 		if isFriendOnline == true {
 			totOnline += 1
 		}
 
-		friends = append(friends, entity.Friend{
+		friend := entity.Friend{
 			FriendId:       uFriend.Id.String(),
 			FriendUsername: uFriend.Username,
 			FriendEmail:    uFriend.Email,
 			Online:         isFriendOnline,
-		})
+		}
+		if canSeePresence {
+			status := presences[i].Status
+			friend.Status = &status
+		}
+		if friendLastSeen, found := lastSeen[uFriend.Id]; found && ok && blockersOk && !isFriendOnline && !blockers[uFriend.Id] &&
+			setting.CanSeeLastSeen(userDb.Id, true) {
+			friend.LastSeenAt = &friendLastSeen
+		}
+		friends = append(friends, friend)
 	}
 	messageWsFriendsStatus.TotalFriends = totFriend
 	messageWsFriendsStatus.TotalOnline = totOnline
@@ -499,19 +788,26 @@ func (u *User) getAllFriendsOnlineStatus(ctx context.Context, username string) {
 func (c *ChatHub) Register(ctx context.Context, conn *websocket.Conn, username string, userId string,
 ) *User {
 	user := &User{
-		Chat:  c,
-		Conn:  conn,
-		inbox: make(chan *entity.MessageWs),
-		Name:  username,
+		Chat:      c,
+		Conn:      conn,
+		inbox:     make(chan *entity.MessageWs, inboxSize),
+		Name:      username,
+		UserId:    userId,
+		SessionId: uuid.New().String(),
 	}
 
 	user.Chat.register <- user
+	// daftarkan koneksi ke channel yang di subscribe user
+	c.joinUserChannels(user)
 
 	// Register user chat-server location in redis
 	c.usrRedis.SetUserServerLocation(userId)
+	c.usrRedis.AddUserSessionServer(userId)
 
 	// Set User Online status (key,value) in redis
 	c.usrRedis.UserSetOnline(userId)
+	// membuka koneksi baru dianggap aktivitas user
+	c.usrRedis.TouchUserActivity(userId)
 
 	// fanout user online status ke semua kontaknya
 	c.userOnlineStatusFanout(username, true)
@@ -577,6 +873,13 @@ func (u *User) writePump() {
 		case msgWs := <-u.inbox:
 			// menerima message dari inbox user, llau send wesbsocket message to client/user
 			u.Write(websocket.TextMessage, msgWs)
+
+			if msgWs.Type == entity.MessageTypeForceDisconnect {
+				// akun user di suspend, tutup koneksi websocket user
+				closeMsg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, msgWs.MsgForceDisconnect.Reason)
+				u.Conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(time.Second))
+				return
+			}
 		}
 	}
 }
@@ -2,29 +2,59 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	CannotAddYourselfErr             = errors.New("you can not send a friend request to yourself")
+	FriendRequestPermissionDeniedErr = errors.New("you are not allowed to respond to this friend request")
+	InvalidContactNicknameErr        = errors.New("nickname can not be longer than 64 characters")
+	InvalidContactNoteErr            = errors.New("note can not be longer than 1000 characters")
+	InvalidEmailHashErr              = errors.New("hashes must be hex encoded sha256 of salt + lowercase email")
+	DiscoveryBatchTooLargeErr        = errors.New("too many hashes in one request")
+	DiscoveryRateLimitErr            = errors.New("contact discovery quota exceeded, try again later")
+)
+
+const (
+	maxContactNicknameLength = 64
+	maxContactNoteLength     = 1000
 )
 
 // Bussines logic untuk mengelola kontak dari user
 type ContactUseCase struct {
 	userRepo UserRepo
+	frRepo   FriendRequestRepo
+	blkRepo  BlockRepo
+	pubSub   PubSubRedis
+	usrRedis UserRedisRepo
+	// pencarian user dari hash email address book
+	profileRepo    UserProfileRepo
+	discoveryRedis ContactDiscoveryRedisRepo
+	emailHasher    EmailHasher
+	discovery      entity.ContactDiscoveryLimits
 }
 
-func NewContactUseCase(u UserRepo) *ContactUseCase {
+func NewContactUseCase(u UserRepo, fr FriendRequestRepo, blk BlockRepo, pubSub PubSubRedis, usrRedis UserRedisRepo,
+	profileRepo UserProfileRepo, discoveryRedis ContactDiscoveryRedisRepo, emailHasher EmailHasher,
+	discovery entity.ContactDiscoveryLimits) *ContactUseCase {
 	return &ContactUseCase{
-		userRepo: u,
-	}
-}
-
-// Menambah teman di dalam kontak usernya
-func (uc *ContactUseCase) AddContact(ctx context.Context, a entity.AddFriendRequest) (entity.UserResponse, error) {
-	user, err := uc.userRepo.AddFriend(ctx, a.MyUsername, a.FriendUsername)
-	if err != nil {
-		// Bad request User Not Found
-		return entity.UserResponse{}, fmt.Errorf("AuthUseCase - AddContact - uc.userRepo.AddFriend: %w", err)
+		userRepo:       u,
+		frRepo:         fr,
+		blkRepo:        blk,
+		pubSub:         pubSub,
+		usrRedis:       usrRedis,
+		profileRepo:    profileRepo,
+		discoveryRedis: discoveryRedis,
+		emailHasher:    emailHasher,
+		discovery:      discovery,
 	}
-	return user, nil
 }
 
 // mendapatkan semua kontak yang dimiliki user.
@@ -37,3 +67,239 @@ func (uc *ContactUseCase) GetContact(ctx context.Context, g entity.GetContactReq
 
 	return user, nil
 }
+
+// RemoveContact menghapus kontak di kedua arah. Status online kedua user langsung dikirim offline ke satu sama lain
+// & tidak di fanout lagi
+func (uc *ContactUseCase) RemoveContact(ctx context.Context, a entity.AddFriendRequest) error {
+	user, err := uc.userRepo.GetUserByUsername(a.MyUsername)
+	if err != nil {
+		return fmt.Errorf("ContactUseCase - RemoveContact - uc.userRepo.GetUserByUsername: %w", err)
+	}
+	friend, err := uc.userRepo.GetUserByUsername(a.FriendUsername)
+	if err != nil {
+		return fmt.Errorf("ContactUseCase - RemoveContact - uc.userRepo.GetUserByUsername: %w", err)
+	}
+	if err = uc.userRepo.RemoveContact(ctx, user.Id, friend.Id); err != nil {
+		return fmt.Errorf("ContactUseCase - RemoveContact - uc.userRepo.RemoveContact: %w", err)
+	}
+
+	uc.notifyOffline(user, friend)
+	uc.notifyOffline(friend, user)
+	return nil
+}
+
+// UpdateContact mengubah nickname / catatan pribadi user untuk kontaknya, hanya terlihat oleh user
+func (uc *ContactUseCase) UpdateContact(ctx context.Context, e entity.UpdateContactReqUc) (entity.UserResponse, error) {
+	if e.Nickname != nil && utf8.RuneCountInString(*e.Nickname) > maxContactNicknameLength {
+		return entity.UserResponse{}, fmt.Errorf("ContactUseCase - UpdateContact: %w", InvalidContactNicknameErr)
+	}
+	if e.Note != nil && utf8.RuneCountInString(*e.Note) > maxContactNoteLength {
+		return entity.UserResponse{}, fmt.Errorf("ContactUseCase - UpdateContact: %w", InvalidContactNoteErr)
+	}
+	user, err := uc.userRepo.GetUserByUsername(e.MyUsername)
+	if err != nil {
+		return entity.UserResponse{}, fmt.Errorf("ContactUseCase - UpdateContact - uc.userRepo.GetUserByUsername: %w", err)
+	}
+	friend, err := uc.userRepo.GetUserByUsername(e.FriendUsername)
+	if err != nil {
+		return entity.UserResponse{}, fmt.Errorf("ContactUseCase - UpdateContact - uc.userRepo.GetUserByUsername: %w", err)
+	}
+
+	if e.Nickname != nil {
+		nickname := strings.TrimSpace(*e.Nickname)
+		e.Nickname = &nickname
+	}
+	contact, err := uc.userRepo.UpdateContact(ctx, user.Id, friend.Id, e.Nickname, e.Note)
+	if err != nil {
+		return entity.UserResponse{}, fmt.Errorf("ContactUseCase - UpdateContact - uc.userRepo.UpdateContact: %w", err)
+	}
+	return contact, nil
+}
+
+// SendFriendRequest mengirim friend request ke FriendUsername, user baru masuk kontak setelah request di accept.
+// Jika FriendUsername sudah lebih dulu mengirim request ke user, request tsb langsung di accept
+func (uc *ContactUseCase) SendFriendRequest(ctx context.Context, a entity.AddFriendRequest) (entity.FriendRequest, error) {
+	if a.MyUsername == a.FriendUsername {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - SendFriendRequest: %w", CannotAddYourselfErr)
+	}
+	sender, err := uc.userRepo.GetUserByUsername(a.MyUsername)
+	if err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - SendFriendRequest - uc.userRepo.GetUserByUsername: %w", err)
+	}
+	target, err := uc.userRepo.GetUserByUsername(a.FriendUsername)
+	if err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - SendFriendRequest - uc.userRepo.GetUserByUsername: %w", err)
+	}
+	blocked, err := uc.blkRepo.IsBlocked(ctx, target.Id, sender.Id)
+	if err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - SendFriendRequest - uc.blkRepo.IsBlocked: %w", err)
+	}
+	if blocked {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - SendFriendRequest: %w", BlockedByUserErr)
+	}
+	if err = uc.userRepo.GetUserFriend(ctx, a.MyUsername, a.FriendUsername); err == nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - SendFriendRequest - uc.userRepo.GetUserFriend: %w", repo.AlreadyYourInYourContactErr)
+	}
+
+	if reverseReq, err := uc.frRepo.GetPendingFriendRequest(ctx, target.Id, sender.Id); err == nil {
+		// kedua user saling mengirim request
+		return uc.accept(ctx, reverseReq)
+	}
+
+	friendReq, err := uc.frRepo.CreateFriendRequest(ctx, sender.Id, target.Id)
+	if err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - SendFriendRequest - uc.frRepo.CreateFriendRequest: %w", err)
+	}
+	uc.notifyFriendRequest(friendReq, entity.GetUser{Id: target.Id, Username: target.Username})
+	return friendReq, nil
+}
+
+// GetFriendRequests mendapatkan semua friend request pending yang dikirim ke / oleh user
+func (uc *ContactUseCase) GetFriendRequests(ctx context.Context, g entity.GetContactRequest) (entity.FriendRequests, error) {
+	user, err := uc.userRepo.GetUserByUsername(g.MyUsername)
+	if err != nil {
+		return entity.FriendRequests{}, fmt.Errorf("ContactUseCase - GetFriendRequests - uc.userRepo.GetUserByUsername: %w", err)
+	}
+
+	friendReqs, err := uc.frRepo.GetPendingFriendRequests(ctx, user.Id)
+	if err != nil {
+		return entity.FriendRequests{}, fmt.Errorf("ContactUseCase - GetFriendRequests - uc.frRepo.GetPendingFriendRequests: %w", err)
+	}
+	res := entity.FriendRequests{
+		Incoming: []entity.FriendRequest{},
+		Outgoing: []entity.FriendRequest{},
+	}
+	for _, friendReq := range friendReqs {
+		if friendReq.RecipientId == user.Id {
+			res.Incoming = append(res.Incoming, friendReq)
+		} else {
+			res.Outgoing = append(res.Outgoing, friendReq)
+		}
+	}
+	return res, nil
+}
+
+// AcceptFriendRequest accept friend request, hanya recipient request yang bisa.
+// Sender & recipient menjadi kontak satu sama lain
+func (uc *ContactUseCase) AcceptFriendRequest(ctx context.Context, e entity.FriendRequestReqUc) (entity.FriendRequest, error) {
+	friendReq, err := uc.friendRequest(ctx, e, false)
+	if err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - AcceptFriendRequest - uc.friendRequest: %w", err)
+	}
+	return uc.accept(ctx, friendReq)
+}
+
+// DeclineFriendRequest decline friend request, hanya recipient request yang bisa
+func (uc *ContactUseCase) DeclineFriendRequest(ctx context.Context, e entity.FriendRequestReqUc) (entity.FriendRequest, error) {
+	friendReq, err := uc.friendRequest(ctx, e, false)
+	if err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - DeclineFriendRequest - uc.friendRequest: %w", err)
+	}
+	if err = uc.frRepo.SetFriendRequestStatus(ctx, friendReq.Id, entity.FriendRequestDeclined); err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - DeclineFriendRequest - uc.frRepo.SetFriendRequestStatus: %w", err)
+	}
+	friendReq = decided(friendReq, entity.FriendRequestDeclined)
+	uc.notifyFriendRequest(friendReq, entity.GetUser{Id: friendReq.SenderId, Username: friendReq.SenderUsername})
+	return friendReq, nil
+}
+
+// CancelFriendRequest cancel friend request yang belum dijawab, hanya sender request yang bisa
+func (uc *ContactUseCase) CancelFriendRequest(ctx context.Context, e entity.FriendRequestReqUc) (entity.FriendRequest, error) {
+	friendReq, err := uc.friendRequest(ctx, e, true)
+	if err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - CancelFriendRequest - uc.friendRequest: %w", err)
+	}
+	if err = uc.frRepo.SetFriendRequestStatus(ctx, friendReq.Id, entity.FriendRequestCancelled); err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - CancelFriendRequest - uc.frRepo.SetFriendRequestStatus: %w", err)
+	}
+	friendReq = decided(friendReq, entity.FriendRequestCancelled)
+	uc.notifyFriendRequest(friendReq, entity.GetUser{Id: friendReq.RecipientId, Username: friendReq.RecipientUsername})
+	return friendReq, nil
+}
+
+// accept menyimpan status accepted & membuat kontak dua arah lalu menotifikasi sender request
+func (uc *ContactUseCase) accept(ctx context.Context, friendReq entity.FriendRequest) (entity.FriendRequest, error) {
+	if err := uc.frRepo.AcceptFriendRequest(ctx, friendReq); err != nil {
+		return entity.FriendRequest{}, fmt.Errorf("ContactUseCase - accept - uc.frRepo.AcceptFriendRequest: %w", err)
+	}
+	friendReq = decided(friendReq, entity.FriendRequestAccepted)
+	uc.notifyFriendRequest(friendReq, entity.GetUser{Id: friendReq.SenderId, Username: friendReq.SenderUsername})
+	return friendReq, nil
+}
+
+// friendRequest mendapatkan friend request yang masih pending milik user,
+// asSender true berarti user harus sender request, false berarti user harus recipient request
+func (uc *ContactUseCase) friendRequest(ctx context.Context, e entity.FriendRequestReqUc, asSender bool) (entity.FriendRequest, error) {
+	user, err := uc.userRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.FriendRequest{}, err
+	}
+	friendReq, err := uc.frRepo.GetFriendRequest(ctx, e.RequestId)
+	if err != nil {
+		return entity.FriendRequest{}, err
+	}
+	if (asSender && friendReq.SenderId != user.Id) || (!asSender && friendReq.RecipientId != user.Id) {
+		return entity.FriendRequest{}, FriendRequestPermissionDeniedErr
+	}
+	if friendReq.Status != entity.FriendRequestPending {
+		return entity.FriendRequest{}, repo.FriendRequestAlreadyDecidedErr
+	}
+	return friendReq, nil
+}
+
+// notifyFriendRequest mengirim message friend request ke semua koneksi websocket recipient di chat-server manapun
+func (uc *ContactUseCase) notifyFriendRequest(friendReq entity.FriendRequest, recipient entity.GetUser) {
+	msgWs := &entity.MessageWs{
+		Type: entity.MessageTypeFriendRequest,
+		MsgFriendRequest: entity.MessageFriendRequest{
+			RequestId:         friendReq.Id,
+			SenderUsername:    friendReq.SenderUsername,
+			TargetUsername:    friendReq.RecipientUsername,
+			Status:            friendReq.Status,
+			RecipientUsername: recipient.Username,
+		},
+	}
+
+	servers, err := uc.usrRedis.GetUserSessionServers(recipient.Id.String())
+	if err != nil {
+		log.Println("ContactUseCase - notifyFriendRequest - uc.usrRedis.GetUserSessionServers: ", err)
+		return
+	}
+	for _, server := range servers {
+		if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+			log.Println("ContactUseCase - notifyFriendRequest - uc.pubSub.PublishToChannel: ", err)
+		}
+	}
+}
+
+// notifyOffline mengirim status offline user ke semua koneksi websocket recipient yang sudah bukan kontak user
+func (uc *ContactUseCase) notifyOffline(user entity.GetUser, recipient entity.GetUser) {
+	msgWs := &entity.MessageWs{
+		Type: entity.MessageTypeOnlineStatusFanOut,
+		MsgOnlineStatusFanout: entity.MessageOnlineStatusFanout{
+			FriendId:          user.Id.String(),
+			FriendUsername:    user.Username,
+			FriendEmail:       user.Email,
+			Online:            false,
+			UserToGetNotified: recipient.Username,
+		},
+	}
+
+	servers, err := uc.usrRedis.GetUserSessionServers(recipient.Id.String())
+	if err != nil {
+		log.Println("ContactUseCase - notifyOffline - uc.usrRedis.GetUserSessionServers: ", err)
+		return
+	}
+	for _, server := range servers {
+		if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+			log.Println("ContactUseCase - notifyOffline - uc.pubSub.PublishToChannel: ", err)
+		}
+	}
+}
+
+func decided(friendReq entity.FriendRequest, status entity.FriendRequestStatus) entity.FriendRequest {
+	decidedAt := time.Now()
+	friendReq.Status = status
+	friendReq.DecidedAt = &decidedAt
+	return friendReq
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"strings"
+	"time"
+)
+
+// emailHashIndexBatch jumlah user yang hash emailnya dihitung per query
+const emailHashIndexBatch = 500
+
+// GetDiscoverySalt mendapatkan salt global untuk hash email address book
+func (uc *ContactUseCase) GetDiscoverySalt(ctx context.Context) entity.ContactDiscoverySalt {
+	return entity.ContactDiscoverySalt{Salt: uc.emailHasher.Salt()}
+}
+
+// DiscoverContacts mengembalikan user terdaftar yang hash emailnya ada di hash address book user login.
+// Setiap hash unik memakai kuota user & kuota ip agar endpoint tidak bisa dipakai untuk enumerasi email
+func (uc *ContactUseCase) DiscoverContacts(ctx context.Context, e entity.ContactDiscoveryReqUc) (entity.ContactDiscoveryResult, error) {
+	hashes, err := uc.discoveryHashes(e.Hashes)
+	if err != nil {
+		return entity.ContactDiscoveryResult{}, fmt.Errorf("ContactUseCase - DiscoverContacts: %w", err)
+	}
+	userLogin, err := uc.userRepo.GetUserByUsername(e.MyUsername)
+	if err != nil {
+		return entity.ContactDiscoveryResult{}, fmt.Errorf("ContactUseCase - DiscoverContacts - uc.userRepo.GetUserByUsername: %w", err)
+	}
+
+	quota, err := uc.consumeDiscoveryQuota("user."+userLogin.Id.String(), "ip."+e.ClientIp, int64(len(hashes)))
+	if err != nil {
+		return quota, fmt.Errorf("ContactUseCase - DiscoverContacts: %w", err)
+	}
+
+	// hash client dicocokkan dengan hmac hash email di database
+	byIndex := make(map[string]string, len(hashes))
+	indexes := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		index := uc.emailHasher.Index(hash)
+		byIndex[index] = hash
+		indexes = append(indexes, index)
+	}
+	matches, err := uc.profileRepo.FindUsersByEmailHashes(ctx, userLogin.Id, indexes)
+	if err != nil {
+		return entity.ContactDiscoveryResult{}, fmt.Errorf("ContactUseCase - DiscoverContacts - uc.profileRepo.FindUsersByEmailHashes: %w", err)
+	}
+	for i := range matches {
+		matches[i].Hash = byIndex[matches[i].Hash]
+	}
+	quota.Matches = matches
+	return quota, nil
+}
+
+// consumeDiscoveryQuota memakai n hash dari kuota user & kuota ip, jika salah satu tidak cukup tidak ada kuota yang dipakai.
+// Sisa kuota & waktu reset tetap dikembalikan saat kuota habis agar client tahu kapan bisa mencoba lagi
+func (uc *ContactUseCase) consumeDiscoveryQuota(userSubject string, ipSubject string, n int64) (entity.ContactDiscoveryResult, error) {
+	userAllowed, userRemaining, userRetryAfter, err := uc.discoveryRedis.ConsumeQuota(userSubject, n, uc.discovery.MaxHashesPerWindow, uc.discovery.Window)
+	if err != nil {
+		return entity.ContactDiscoveryResult{}, fmt.Errorf("uc.discoveryRedis.ConsumeQuota: %w", err)
+	}
+	quota := entity.ContactDiscoveryResult{RemainingQuota: userRemaining, QuotaResetAt: time.Now().Add(userRetryAfter)}
+	if !userAllowed {
+		return quota, DiscoveryRateLimitErr
+	}
+
+	ipAllowed, ipRemaining, ipRetryAfter, err := uc.discoveryRedis.ConsumeQuota(ipSubject, n, uc.discovery.MaxHashesPerIpWindow, uc.discovery.Window)
+	if err != nil {
+		return entity.ContactDiscoveryResult{}, fmt.Errorf("uc.discoveryRedis.ConsumeQuota: %w", err)
+	}
+	if ipRemaining < quota.RemainingQuota {
+		quota.RemainingQuota = ipRemaining
+	}
+	if resetAt := time.Now().Add(ipRetryAfter); resetAt.After(quota.QuotaResetAt) {
+		quota.QuotaResetAt = resetAt
+	}
+	if !ipAllowed {
+		if err = uc.discoveryRedis.ReleaseQuota(userSubject, n); err != nil {
+			return entity.ContactDiscoveryResult{}, fmt.Errorf("uc.discoveryRedis.ReleaseQuota: %w", err)
+		}
+		return quota, DiscoveryRateLimitErr
+	}
+	return quota, nil
+}
+
+// discoveryHashes validasi & hapus duplikat hash, setiap hash harus hex sha256 (64 karakter)
+func (uc *ContactUseCase) discoveryHashes(hashes []string) ([]string, error) {
+	if len(hashes) == 0 {
+		return nil, InvalidEmailHashErr
+	}
+	if uc.discovery.MaxBatchSize > 0 && len(hashes) > uc.discovery.MaxBatchSize {
+		return nil, DiscoveryBatchTooLargeErr
+	}
+	seen := make(map[string]bool, len(hashes))
+	unique := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 {
+			return nil, InvalidEmailHashErr
+		}
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		unique = append(unique, hash)
+	}
+	return unique, nil
+}
+
+// RunEmailHashIndexer menghitung hash email user yang belum memakai key sekarang setiap interval
+// (user yang hashnya gagal disimpan saat register, user lama & setelah salt / secret diganti)
+func (uc *ContactUseCase) RunEmailHashIndexer(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := uc.indexEmailHashes(context.Background()); err != nil {
+			log.Println("ContactUseCase - RunEmailHashIndexer - uc.indexEmailHashes: ", err)
+		}
+		<-ticker.C
+	}
+}
+
+func (uc *ContactUseCase) indexEmailHashes(ctx context.Context) error {
+	key := uc.emailHasher.Key()
+	for {
+		users, err := uc.userRepo.GetUsersWithStaleEmailHash(ctx, key, emailHashIndexBatch)
+		if err != nil {
+			return fmt.Errorf("uc.userRepo.GetUsersWithStaleEmailHash: %w", err)
+		}
+		for _, user := range users {
+			hash := uc.emailHasher.Index(uc.emailHasher.ClientHash(user.Email))
+			if err = uc.userRepo.SetEmailHash(ctx, user.UserId, hash, key); err != nil {
+				return fmt.Errorf("uc.userRepo.SetEmailHash: %w", err)
+			}
+		}
+		if len(users) < emailHashIndexBatch {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/internal/util/contentfilter"
+	"log"
+	"strings"
+)
+
+// flagMessage memasukkan pesan yang di flag oleh content filter ke moderation queue (table reports)
+func (c *ChatHub) flagMessage(flags []contentfilter.Flag, report entity.Report) {
+	if len(flags) == 0 {
+		return
+	}
+	var reasons []string
+	for _, f := range flags {
+		reasons = append(reasons, fmt.Sprintf("content filter %s: %s", f.Filter, f.Reason))
+	}
+	report.Reason = strings.Join(reasons, "; ")
+
+	if _, err := c.reportRepo.CreateReport(context.Background(), report); err != nil {
+		log.Println("flagMessage - c.reportRepo.CreateReport: ", err)
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"time"
+)
+
+var (
+	InvalidMutedUntilErr = errors.New("muted_until must be in the future")
+)
+
+// ConversationSettingUseCase mute & archive percakapan per user
+type ConversationSettingUseCase struct {
+	settingRepo ConversationSettingRepo
+	uRepo       UserRepo
+	gRepo       GroupRepo
+}
+
+func NewConversationSettingUseCase(settingRepo ConversationSettingRepo, uRepo UserRepo, gRepo GroupRepo) *ConversationSettingUseCase {
+	return &ConversationSettingUseCase{
+		settingRepo: settingRepo,
+		uRepo:       uRepo,
+		gRepo:       gRepo,
+	}
+}
+
+// GetSettings mendapatkan semua percakapan user yang di mute / archive
+func (uc *ConversationSettingUseCase) GetSettings(ctx context.Context, username string) ([]entity.ConversationSetting, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("ConversationSettingUseCase - GetSettings - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	settings, err := uc.settingRepo.GetSettings(ctx, userLogin.Id)
+	if err != nil {
+		return nil, fmt.Errorf("ConversationSettingUseCase - GetSettings - uc.settingRepo.GetSettings: %w", err)
+	}
+	return settings, nil
+}
+
+// Mute mute percakapan tanpa batas waktu / sampai MutedUntil.
+// Pesan tetap dikirim, hanya ditandai muted agar client tidak memunculkan notifikasi
+func (uc *ConversationSettingUseCase) Mute(ctx context.Context, e entity.ConversationSettingReqUc) (entity.ConversationSetting, error) {
+	if e.MutedUntil != nil && !e.MutedUntil.After(time.Now()) {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Mute: %w", InvalidMutedUntilErr)
+	}
+	setting, err := uc.conversation(e)
+	if err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Mute - uc.conversation: %w", err)
+	}
+	if err = uc.settingRepo.SetMuted(ctx, setting.UserId, setting.ConversationType, setting.ConversationId, true, e.MutedUntil); err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Mute - uc.settingRepo.SetMuted: %w", err)
+	}
+	setting.Muted = true
+	setting.MutedUntil = e.MutedUntil
+	return setting, nil
+}
+
+// Unmute menghapus mute percakapan
+func (uc *ConversationSettingUseCase) Unmute(ctx context.Context, e entity.ConversationSettingReqUc) (entity.ConversationSetting, error) {
+	setting, err := uc.conversation(e)
+	if err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Unmute - uc.conversation: %w", err)
+	}
+	if err = uc.settingRepo.SetMuted(ctx, setting.UserId, setting.ConversationType, setting.ConversationId, false, nil); err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Unmute - uc.settingRepo.SetMuted: %w", err)
+	}
+	return setting, nil
+}
+
+// Archive archive percakapan, pesan baru tetap dikirim seperti biasa
+func (uc *ConversationSettingUseCase) Archive(ctx context.Context, e entity.ConversationSettingReqUc) (entity.ConversationSetting, error) {
+	setting, err := uc.conversation(e)
+	if err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Archive - uc.conversation: %w", err)
+	}
+	if err = uc.settingRepo.SetArchived(ctx, setting.UserId, setting.ConversationType, setting.ConversationId, true); err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Archive - uc.settingRepo.SetArchived: %w", err)
+	}
+	setting.Archived = true
+	return setting, nil
+}
+
+// Unarchive mengeluarkan percakapan dari archive
+func (uc *ConversationSettingUseCase) Unarchive(ctx context.Context, e entity.ConversationSettingReqUc) (entity.ConversationSetting, error) {
+	setting, err := uc.conversation(e)
+	if err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Unarchive - uc.conversation: %w", err)
+	}
+	if err = uc.settingRepo.SetArchived(ctx, setting.UserId, setting.ConversationType, setting.ConversationId, false); err != nil {
+		return entity.ConversationSetting{}, fmt.Errorf("ConversationSettingUseCase - Unarchive - uc.settingRepo.SetArchived: %w", err)
+	}
+	return setting, nil
+}
+
+// conversation mendapatkan percakapan dari request, user harus berteman / member group
+func (uc *ConversationSettingUseCase) conversation(e entity.ConversationSettingReqUc) (entity.ConversationSetting, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.ConversationSetting{}, err
+	}
+	convType, convId, convName, err := findConversation(uc.uRepo, uc.gRepo, userLogin.Username, userLogin.Id, e.FriendUsername, e.GroupId, "")
+	if err != nil {
+		return entity.ConversationSetting{}, err
+	}
+	return entity.ConversationSetting{
+		UserId:           userLogin.Id,
+		ConversationType: convType,
+		ConversationId:   convId,
+		ConversationName: convName,
+		UpdatedAt:        time.Now(),
+	}, nil
+}
+
+// isConversationMuted cek apakah recipient mute percakapan, error dianggap tidak mute agar notifikasi tidak hilang
+func (c *ChatHub) isConversationMuted(userId uuid.UUID, convType entity.ConversationType, convId uuid.UUID) bool {
+	muted, err := c.settingRepo.IsMuted(context.Background(), userId, convType, convId)
+	if err != nil {
+		log.Println("isConversationMuted - c.settingRepo.IsMuted: ", err)
+		return false
+	}
+	return muted
+}
+
+// mutedGroupMembers id member yang mute group
+func (c *ChatHub) mutedGroupMembers(groupId uuid.UUID) map[uuid.UUID]bool {
+	userIds, err := c.settingRepo.GetMutedUsers(context.Background(), entity.ConversationTypeGroup, groupId)
+	if err != nil {
+		log.Println("mutedGroupMembers - c.settingRepo.GetMutedUsers: ", err)
+	}
+	muted := make(map[uuid.UUID]bool, len(userIds))
+	for _, userId := range userIds {
+		muted[userId] = true
+	}
+	return muted
+}
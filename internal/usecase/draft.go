@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"time"
+)
+
+var (
+	InvalidDraftErr         = errors.New("draft must have either friend_username or group_id")
+	ConversationRequiredErr = errors.New("either friend_username or group_id is required")
+)
+
+// updateDraft menyimpan draft pesan dari client lalu mengirim draft tsb ke semua koneksi websocket user yang lain
+// (device lain), content kosong berarti draft dihapus
+func (u *User) updateDraft(msgWs *entity.MessageWs) {
+	draftMsg := &msgWs.MsgDraft
+	draftMsg.SenderUsername = u.Name
+
+	convType, convId, err := u.draftConversation(draftMsg.FriendUsername, draftMsg.GroupId, draftMsg.GroupName)
+	if err != nil {
+		draftMsg.Content = err.Error()
+		u.Write(websocket.TextMessage, msgWs)
+		return
+	}
+
+	userId, _ := uuid.Parse(u.UserId)
+	draftMsg.UpdatedAt = time.Now()
+	if draftMsg.Content == "" {
+		_, err = u.Chat.draftRepo.DeleteDraft(context.Background(), userId, convType, convId)
+	} else {
+		err = u.Chat.draftRepo.SaveDraft(context.Background(), entity.Draft{
+			UserId:           userId,
+			ConversationType: convType,
+			ConversationId:   convId,
+			Content:          draftMsg.Content,
+			UpdatedAt:        draftMsg.UpdatedAt,
+		})
+	}
+	if err != nil {
+		log.Println("updateDraft - u.Chat.draftRepo: ", err)
+		return
+	}
+
+	if convType == entity.ConversationTypeGroup {
+		draftMsg.GroupId = convId
+	}
+	draftMsg.OriginSessionId = u.SessionId
+	draftMsg.RecipientUsername = u.Name
+	u.Chat.sendToUserSessions(u.UserId, msgWs)
+}
+
+// clearDraft menghapus draft percakapan setelah pesan terkirim & memberi tahu device user yang lain.
+// Device lain hanya diberi tahu jika memang ada draft yang dihapus
+func (u *User) clearDraft(convType entity.ConversationType, convId uuid.UUID, friendUsername string, groupName string) {
+	userId, _ := uuid.Parse(u.UserId)
+	deleted, err := u.Chat.draftRepo.DeleteDraft(context.Background(), userId, convType, convId)
+	if err != nil {
+		log.Println("clearDraft - u.Chat.draftRepo.DeleteDraft: ", err)
+		return
+	}
+	if !deleted {
+		return
+	}
+
+	msgDraft := entity.MessageDraft{
+		SenderUsername:    u.Name,
+		FriendUsername:    friendUsername,
+		GroupName:         groupName,
+		UpdatedAt:         time.Now(),
+		OriginSessionId:   u.SessionId,
+		RecipientUsername: u.Name,
+	}
+	if convType == entity.ConversationTypeGroup {
+		msgDraft.GroupId = convId
+	}
+	msgWs := &entity.MessageWs{
+		Type:     entity.MessageTypeDraftUpdate,
+		MsgDraft: msgDraft,
+	}
+	u.Chat.sendToUserSessions(u.UserId, msgWs)
+}
+
+// draftConversation mendapatkan tipe & id percakapan dari draft, user harus berteman / member group
+func (u *User) draftConversation(friendUsername string, groupId uuid.UUID, groupName string) (entity.ConversationType, uuid.UUID, error) {
+	userId, _ := uuid.Parse(u.UserId)
+	convType, convId, _, err := findConversation(u.Chat.userPg, u.Chat.gpRepo, u.Name, userId, friendUsername, groupId, groupName)
+	if err == ConversationRequiredErr {
+		err = InvalidDraftErr
+	}
+	return convType, convId, err
+}
+
+// findConversation mendapatkan tipe, id & nama percakapan (friend_username atau group), user harus berteman / member group
+func findConversation(userPg UserRepo, gpRepo GroupRepo, username string, userId uuid.UUID, friendUsername string,
+	groupId uuid.UUID, groupName string) (entity.ConversationType, uuid.UUID, string, error) {
+	switch {
+	case friendUsername != "":
+		if err := userPg.GetUserFriend(context.Background(), username, friendUsername); err != nil {
+			return "", uuid.Nil, "", err
+		}
+		friend, err := userPg.GetUserByUsername(friendUsername)
+		if err != nil {
+			return "", uuid.Nil, "", err
+		}
+		return entity.ConversationTypePrivate, friend.Id, friend.Username, nil
+	case groupId != uuid.Nil || groupName != "":
+		group, err := findGroup(gpRepo, groupId, groupName, userId)
+		if err != nil {
+			return "", uuid.Nil, "", err
+		}
+		return entity.ConversationTypeGroup, group.Id, group.Name, nil
+	}
+	return "", uuid.Nil, "", ConversationRequiredErr
+}
+
+// sendToUserSessions mengirim message ke semua koneksi websocket milik user di semua chat-server
+func (c *ChatHub) sendToUserSessions(userId string, msgWs *entity.MessageWs) {
+	servers, err := c.usrRedis.GetUserSessionServers(userId)
+	if err != nil {
+		log.Println("sendToUserSessions - c.usrRedis.GetUserSessionServers: ", err)
+		return
+	}
+	for _, server := range servers {
+		if server == entity.ChatServerNameGlobal.ChatServerName {
+			c.broadcast <- msgWs
+			continue
+		}
+		c.PubSub.PublishToChannel(server, msgWs)
+	}
+}
@@ -0,0 +1,388 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"html"
+	"io"
+	"log"
+	"time"
+)
+
+const (
+	// exportSyncLimit jumlah pesan maksimal yang di export langsung di dalam request,
+	// di atas itu export dijalankan di background dan user mendapat link download
+	exportSyncLimit = 2000
+	// exportBatchSize jumlah pesan yang diambil dari db setiap query
+	exportBatchSize = 500
+	// exportScheduleTimeout waktu tunggu worker export yang kosong
+	exportScheduleTimeout = 3 * time.Second
+	// exportMaxMessages jumlah pesan maksimal dalam 1 export
+	exportMaxMessages = 100000
+	// exportMaxSize ukuran maksimal file export background yang disimpan di redis
+	exportMaxSize = 32 << 20
+)
+
+var (
+	InvalidExportFormatErr  = errors.New("format must be one of json, txt, html")
+	InvalidExportRequestErr = errors.New("either friendUsername or groupId is required")
+	ExportJobForbiddenErr   = errors.New("export job belongs to another user")
+	ExportJobFailedErr      = errors.New("export job failed, please request a new export")
+	ExportTooLargeErr       = errors.New("conversation is too large to export, maximum is 100000 messages or 32MB")
+)
+
+// ExportFileName nama file hasil export
+func ExportFileName(e entity.ExportMessagesRequest) string {
+	name := e.FriendUsername
+	if e.GroupId != uuid.Nil {
+		name = "group-" + e.GroupId.String()
+	} else if e.GroupName != "" {
+		name = e.GroupName
+	}
+	ext := string(e.Format)
+	if e.Format == entity.ExportFormatJSON {
+		ext = "jsonl"
+	}
+	return fmt.Sprintf("chat-%s.%s", name, ext)
+}
+
+// ExportContentType content type dari setiap format export
+func ExportContentType(format entity.ExportFormat) string {
+	switch format {
+	case entity.ExportFormatJSON:
+		return "application/x-ndjson; charset=utf-8"
+	case entity.ExportFormatHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// ExportMessages export seluruh history private chat / group chat ke w.
+// Jika jumlah pesan lebih dari exportSyncLimit, export dijalankan di background & tidak ada yang ditulis ke w,
+// job yang dikembalikan berstatus pending dan berisi link download.
+func (uc *MessageuseCase) ExportMessages(ctx context.Context, e entity.ExportMessagesRequest, w io.Writer) (entity.ExportJob, error) {
+	if e.Format != entity.ExportFormatJSON && e.Format != entity.ExportFormatText && e.Format != entity.ExportFormatHTML {
+		return entity.ExportJob{}, fmt.Errorf("MessageuseCase - ExportMessages: %w", InvalidExportFormatErr)
+	}
+
+	conv, err := uc.exportConversation(e)
+	if err != nil {
+		return entity.ExportJob{}, err
+	}
+
+	if conv.count > exportMaxMessages {
+		return entity.ExportJob{}, fmt.Errorf("MessageuseCase - ExportMessages: %w", ExportTooLargeErr)
+	}
+
+	if conv.count > exportSyncLimit {
+		job := entity.ExportJob{
+			Id:        uuid.New(),
+			Username:  e.Username,
+			Format:    e.Format,
+			Status:    entity.ExportJobStatusPending,
+			FileName:  ExportFileName(e),
+			CreatedAt: time.Now(),
+		}
+		if err = uc.exportRepo.CreateExportJob(ctx, job); err != nil {
+			return entity.ExportJob{}, fmt.Errorf("MessageuseCase - ExportMessages - uc.exportRepo.CreateExportJob: %w", err)
+		}
+
+		err = uc.exportPool.ScheduleTimeout(exportScheduleTimeout, func() {
+			uc.runExportJob(job, conv)
+		})
+		if err != nil {
+			uc.exportRepo.SetExportJobFailed(context.Background(), job.Id)
+			return entity.ExportJob{}, fmt.Errorf("MessageuseCase - ExportMessages - uc.exportPool.ScheduleTimeout: %w", err)
+		}
+
+		job.DownloadLink = "/v1/messages/export/" + job.Id.String()
+		return job, nil
+	}
+
+	if err = uc.writeExport(conv, e.Format, w); err != nil {
+		return entity.ExportJob{}, fmt.Errorf("MessageuseCase - ExportMessages - uc.writeExport: %w", err)
+	}
+
+	return entity.ExportJob{
+		Username: e.Username,
+		Format:   e.Format,
+		Status:   entity.ExportJobStatusDone,
+		FileName: ExportFileName(e),
+	}, nil
+}
+
+// GetExportJob mendapatkan status job export & isi filenya jika export sudah selesai
+func (uc *MessageuseCase) GetExportJob(ctx context.Context, e entity.GetExportJobRequest) (entity.ExportJob, []byte, error) {
+	job, err := uc.exportRepo.GetExportJob(ctx, e.Id)
+	if err != nil {
+		return entity.ExportJob{}, nil, fmt.Errorf("MessageuseCase - GetExportJob - uc.exportRepo.GetExportJob: %w", err)
+	}
+	if job.Username != e.Username {
+		return entity.ExportJob{}, nil, fmt.Errorf("MessageuseCase - GetExportJob: %w", ExportJobForbiddenErr)
+	}
+	job.DownloadLink = "/v1/messages/export/" + job.Id.String()
+
+	switch job.Status {
+	case entity.ExportJobStatusFailed:
+		return entity.ExportJob{}, nil, fmt.Errorf("MessageuseCase - GetExportJob: %w", ExportJobFailedErr)
+	case entity.ExportJobStatusPending:
+		return job, nil, nil
+	}
+
+	data, err := uc.exportRepo.GetExportJobData(ctx, e.Id)
+	if err != nil {
+		return entity.ExportJob{}, nil, fmt.Errorf("MessageuseCase - GetExportJob - uc.exportRepo.GetExportJobData: %w", err)
+	}
+	return job, data, nil
+}
+
+// runExportJob menjalankan export di background lalu menyimpan hasilnya di redis,
+// export dihentikan & job gagal jika ukuran file lebih dari exportMaxSize
+func (uc *MessageuseCase) runExportJob(job entity.ExportJob, conv exportConversation) {
+	buf := &limitedBuffer{max: exportMaxSize}
+	if err := uc.writeExport(conv, job.Format, buf); err != nil {
+		log.Println("MessageuseCase - runExportJob - uc.writeExport: ", err)
+		uc.exportRepo.SetExportJobFailed(context.Background(), job.Id)
+		return
+	}
+	if err := uc.exportRepo.SetExportJobDone(context.Background(), job.Id, buf.Bytes()); err != nil {
+		log.Println("MessageuseCase - runExportJob - uc.exportRepo.SetExportJobDone: ", err)
+		uc.exportRepo.SetExportJobFailed(context.Background(), job.Id)
+	}
+}
+
+// limitedBuffer bytes.Buffer yang menolak write jika ukurannya melebihi max
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		return 0, ExportTooLargeErr
+	}
+	return b.Buffer.Write(p)
+}
+
+// exportConversation sumber pesan yang akan di export
+type exportConversation struct {
+	title string
+	count int64
+	// next mengembalikan batch pesan selanjutnya dengan id > afterId
+	next func(afterId uint64) ([]entity.ExportMessage, uint64, error)
+}
+
+// exportConversation membuat sumber pesan dari private chat atau group chat yang di request user
+func (uc *MessageuseCase) exportConversation(e entity.ExportMessagesRequest) (exportConversation, error) {
+	user, err := uc.userPgRepo.GetUserByUsername(e.Username)
+	if err != nil {
+		return exportConversation{}, fmt.Errorf("MessageuseCase - ExportMessages - uc.userPgRepo.GetUserByUsername: %w", err)
+	}
+
+	usernames := make(map[uuid.UUID]string)
+	resolveUsername := func(userId uuid.UUID) string {
+		if username, ok := usernames[userId]; ok {
+			return username
+		}
+		u, err := uc.userPgRepo.GetUserById(userId)
+		if err != nil {
+			return userId.String()
+		}
+		usernames[userId] = u.Username
+		return u.Username
+	}
+
+	switch {
+	case e.GroupId != uuid.Nil || e.GroupName != "":
+		group, err := findGroup(uc.gpRepo, e.GroupId, e.GroupName, user.Id)
+		if err != nil {
+			return exportConversation{}, fmt.Errorf("MessageuseCase - ExportMessages - findGroup: %w", err)
+		}
+		count, err := uc.gcRepo.CountMessagesByGroupId(group.Id)
+		if err != nil {
+			return exportConversation{}, fmt.Errorf("MessageuseCase - ExportMessages - uc.gcRepo.CountMessagesByGroupId: %w", err)
+		}
+
+		return exportConversation{
+			title: "Group " + group.Name,
+			count: count,
+			next: func(afterId uint64) ([]entity.ExportMessage, uint64, error) {
+				gcMessages, err := uc.gcRepo.GetMessagesByGroupIdAfter(group.Id, afterId, exportBatchSize)
+				if err != nil {
+					return nil, 0, err
+				}
+				var msgs []entity.ExportMessage
+				for _, msg := range gcMessages.Messages {
+					msgs = append(msgs, entity.ExportMessage{
+						MessageId: msg.MessageId,
+						Sender:    resolveUsername(msg.UserId),
+						Group:     group.Name,
+						Content:   msg.Content,
+						CreatedAt: msg.CreatedAt,
+					})
+					afterId = msg.MessageId
+				}
+				return msgs, afterId, nil
+			},
+		}, nil
+
+	case e.FriendUsername != "":
+		friend, err := uc.userPgRepo.GetUserByUsername(e.FriendUsername)
+		if err != nil {
+			return exportConversation{}, fmt.Errorf("MessageuseCase - ExportMessages - uc.userPgRepo.GetUserByUsername: %w", err)
+		}
+		pcReq := entity.GetPCQueryBySdrAndRcvrRequest{
+			SenderId:   user.Id,
+			ReceiverId: friend.Id,
+		}
+		count, err := uc.pcRepo.CountPrivateChatBySenderAndReceiver(pcReq)
+		if err != nil {
+			return exportConversation{}, fmt.Errorf("MessageuseCase - ExportMessages - uc.pcRepo.CountPrivateChatBySenderAndReceiver: %w", err)
+		}
+
+		return exportConversation{
+			title: "Chat with " + friend.Username,
+			count: count,
+			next: func(afterId uint64) ([]entity.ExportMessage, uint64, error) {
+				pcs, err := uc.pcRepo.GetPrivateChatBySenderAndReceiverAfter(pcReq, afterId, exportBatchSize)
+				if err != nil {
+					return nil, 0, err
+				}
+				var msgs []entity.ExportMessage
+				for _, msg := range pcs.Messages {
+					msgs = append(msgs, entity.ExportMessage{
+						MessageId: msg.MessageId,
+						Sender:    resolveUsername(msg.MessageFrom),
+						Recipient: resolveUsername(msg.MessageTo),
+						Content:   msg.Content,
+						CreatedAt: msg.CreatedAt,
+					})
+					afterId = msg.MessageId
+				}
+				return msgs, afterId, nil
+			},
+		}, nil
+	}
+
+	return exportConversation{}, fmt.Errorf("MessageuseCase - ExportMessages: %w", InvalidExportRequestErr)
+}
+
+// writeExport menulis semua pesan dari conv ke w per batch sesuai format
+func (uc *MessageuseCase) writeExport(conv exportConversation, format entity.ExportFormat, w io.Writer) error {
+	var ew exportWriter
+	switch format {
+	case entity.ExportFormatJSON:
+		ew = &jsonLinesExportWriter{enc: json.NewEncoder(w)}
+	case entity.ExportFormatHTML:
+		ew = &htmlExportWriter{w: w}
+	default:
+		ew = &textExportWriter{w: w}
+	}
+
+	if err := ew.Begin(conv.title); err != nil {
+		return err
+	}
+
+	var afterId uint64
+	for {
+		msgs, lastId, err := conv.next(afterId)
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if err = ew.Write(msg); err != nil {
+				return err
+			}
+		}
+		if len(msgs) < exportBatchSize {
+			break
+		}
+		afterId = lastId
+	}
+
+	return ew.End()
+}
+
+// exportWriter menulis pesan ke file export dengan format tertentu
+type exportWriter interface {
+	Begin(title string) error
+	Write(entity.ExportMessage) error
+	End() error
+}
+
+// jsonLinesExportWriter 1 baris json untuk setiap pesan
+type jsonLinesExportWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonLinesExportWriter) Begin(string) error { return nil }
+
+func (w *jsonLinesExportWriter) Write(msg entity.ExportMessage) error { return w.enc.Encode(msg) }
+
+func (w *jsonLinesExportWriter) End() error { return nil }
+
+// textExportWriter 1 baris teks "[waktu] username: pesan" untuk setiap pesan
+type textExportWriter struct {
+	w io.Writer
+}
+
+func (w *textExportWriter) Begin(title string) error {
+	_, err := fmt.Fprintf(w.w, "%s\n\n", title)
+	return err
+}
+
+func (w *textExportWriter) Write(msg entity.ExportMessage) error {
+	_, err := fmt.Fprintf(w.w, "[%s] %s: %s\n", msg.CreatedAt.Format("2006-01-02 15:04:05"), msg.Sender, msg.Content)
+	return err
+}
+
+func (w *textExportWriter) End() error { return nil }
+
+// htmlExportWriter transcript html yang bisa dibuka tanpa file css/js lain
+type htmlExportWriter struct {
+	w io.Writer
+}
+
+const exportHTMLHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body{font-family:sans-serif;max-width:800px;margin:0 auto;padding:16px;background:#f5f5f5}
+.msg{background:#fff;border-radius:6px;padding:8px 12px;margin:6px 0}
+.sender{font-weight:bold}
+.time{color:#888;font-size:12px;margin-left:8px}
+.content{white-space:pre-wrap;margin-top:4px}
+</style>
+</head>
+<body>
+<h1>%s</h1>
+`
+
+func (w *htmlExportWriter) Begin(title string) error {
+	title = html.EscapeString(title)
+	_, err := fmt.Fprintf(w.w, exportHTMLHead, title, title)
+	return err
+}
+
+func (w *htmlExportWriter) Write(msg entity.ExportMessage) error {
+	_, err := fmt.Fprintf(w.w,
+		"<div class=\"msg\"><span class=\"sender\">%s</span><span class=\"time\">%s</span><div class=\"content\">%s</div></div>\n",
+		html.EscapeString(msg.Sender),
+		msg.CreatedAt.Format("2006-01-02 15:04:05"),
+		html.EscapeString(msg.Content),
+	)
+	return err
+}
+
+func (w *htmlExportWriter) End() error {
+	_, err := io.WriteString(w.w, "</body>\n</html>\n")
+	return err
+}
@@ -2,20 +2,61 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	sonyflake2 "github.com/lintangbs/chat-be/internal/util/sonyflake"
+	"net/url"
+	"strings"
+)
+
+var (
+	GroupPermissionDeniedErr = errors.New("you don't have permission to do this in the group")
+	InvalidGroupRoleErr      = errors.New("role must be admin or member")
+	CannotChangeOwnRoleErr   = errors.New("you can not change your own role")
+	InvalidGroupNameErr      = errors.New("group name can not be empty")
+	GroupIdRequiredErr       = errors.New("group_id is required")
+	InvalidAvatarUrlErr      = errors.New("avatar_url must be an http or https url")
+	OwnerCannotLeaveErr      = errors.New("the owner can not leave the group, transfer ownership or delete the group first")
+	CannotTransferToSelfErr  = errors.New("you are already the owner of this group")
+	GroupMemberLimitErr      = repo.GroupMemberLimitErr
 )
 
 type GroupUseCase struct {
-	gRepo GroupRepo
-	uRepo UserRepo
+	gRepo       GroupRepo
+	uRepo       UserRepo
+	gcRepo      GroupChatRepo
+	inviteRepo  GroupInviteRepo
+	joinRepo    GroupJoinRequestRepo
+	pubSub      PubSubRedis
+	usrRedis    UserRedisRepo
+	idGen       sonyflake2.IdGenerator
+	fanout      GroupChatFanout
+	gRedis      GroupRedisRepo
+	blockRepo   BlockRepo
+	profileRepo UserProfileRepo
+	limits      entity.GroupLimits
 }
 
-func NewGroupUseCase(gRepo GroupRepo, uRepo UserRepo) *GroupUseCase {
+func NewGroupUseCase(gRepo GroupRepo, uRepo UserRepo, gcRepo GroupChatRepo, inviteRepo GroupInviteRepo,
+	joinRepo GroupJoinRequestRepo, pubSub PubSubRedis, usrRedis UserRedisRepo, idGen sonyflake2.IdGenerator, fanout GroupChatFanout,
+	gRedis GroupRedisRepo, blockRepo BlockRepo, profileRepo UserProfileRepo, limits entity.GroupLimits) *GroupUseCase {
 	return &GroupUseCase{
-		gRepo: gRepo,
-		uRepo: uRepo,
+		gRepo:       gRepo,
+		uRepo:       uRepo,
+		gcRepo:      gcRepo,
+		inviteRepo:  inviteRepo,
+		joinRepo:    joinRepo,
+		pubSub:      pubSub,
+		usrRedis:    usrRedis,
+		idGen:       idGen,
+		fanout:      fanout,
+		gRedis:      gRedis,
+		blockRepo:   blockRepo,
+		profileRepo: profileRepo,
+		limits:      limits,
 	}
 }
 
@@ -25,6 +66,11 @@ func (uc *GroupUseCase) CreateGroup(ctx context.Context, e entity.CreateGroupReq
 		return entity.Group{}, fmt.Errorf("GroupUseCase - CreateGroup - uc.uRepo.GetUserByUsername : %w", err)
 	}
 
+	// pembuat group juga termasuk member
+	if !uc.limits.CanAdd(1, len(e.Members)) {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - CreateGroup: %w", GroupMemberLimitErr)
+	}
+
 	var membersId []uuid.UUID
 
 	for _, memberName := range e.Members {
@@ -37,6 +83,9 @@ func (uc *GroupUseCase) CreateGroup(ctx context.Context, e entity.CreateGroupReq
 		if err != nil {
 			return entity.Group{}, fmt.Errorf("GroupUseCase - CreateGroup - uc.uRepo.GetUserByUsername : %w", err)
 		}
+		if err = uc.checkNotBlocked(ctx, member.Id, userLogin.Id); err != nil {
+			return entity.Group{}, fmt.Errorf("GroupUseCase - CreateGroup - uc.checkNotBlocked : %w", err)
+		}
 		membersId = append(membersId, member.Id)
 	}
 	createReq := entity.CreateGroupRequest{
@@ -53,10 +102,26 @@ func (uc *GroupUseCase) CreateGroup(ctx context.Context, e entity.CreateGroupReq
 	return group, nil
 }
 
+// GetUserGroups mendapatkan semua group yang diikuti user
+func (uc *GroupUseCase) GetUserGroups(ctx context.Context, username string) ([]entity.Group, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("GroupUseCase - GetUserGroups - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	groups, err := uc.gRepo.GetUserGroups(userLogin.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GroupUseCase - GetUserGroups - uc.gRepo.GetUserGroups: %w", err)
+	}
+	return groups, nil
+}
+
 func (uc *GroupUseCase) AddNewGroupMember(ctx context.Context, e entity.AddNewGroupMemberReqUc) (entity.Group, error) {
-	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, e.Name, e.UserName)
 	if err != nil {
-		return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.uRepo.GetUserByUsername : %w", err)
+		return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.memberRole : %w", err)
+	}
+	if !role.Can(entity.GroupPermissionAddMember) {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember: %w", GroupPermissionDeniedErr)
 	}
 
 	var membersId []uuid.UUID
@@ -70,42 +135,327 @@ func (uc *GroupUseCase) AddNewGroupMember(ctx context.Context, e entity.AddNewGr
 		if err != nil {
 			return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.uRepo.GetUserByUsername : %w", err)
 		}
+		if err = uc.checkNotBlocked(ctx, member.Id, userLogin.Id); err != nil {
+			return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.checkNotBlocked : %w", err)
+		}
 		membersId = append(membersId, member.Id)
 	}
 
+	// batas member group dicek di transaksi insert member
 	addReq := entity.AddNewGroupMemberReq{
-		Name:    e.Name,
-		UserId:  userLogin.Id,
-		Members: membersId,
+		GroupId:    groupDb.Id,
+		UserId:     userLogin.Id,
+		Members:    membersId,
+		MaxMembers: uc.limits.MaxMembers,
 	}
 
 	group, err := uc.gRepo.AddNewGroupMember(ctx, addReq)
 	if err != nil {
 		return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.gRepo.AddNewGroupMember: %w", err)
 	}
+	uc.invalidateGroupMembers(group.Id)
 
+	uc.systemMessage(group, userLogin, entity.GroupChatKindMemberAdded,
+		fmt.Sprintf("%s added %s", userLogin.Username, strings.Join(e.Members, ", ")), uc.groupMembers(group.Id))
 	return group, nil
 }
 
 func (uc *GroupUseCase) RemoveGroupMember(ctx context.Context, e entity.RemoveGroupMemberReqUc) (entity.Group, error) {
-	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, e.Name, e.UserName)
 	if err != nil {
-		return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.uRepo.GetUserByUsername : %w", err)
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RemoveGroupMember - uc.memberRole : %w", err)
 	}
 	userToRemove, err := uc.uRepo.GetUserByUsername(e.UsertoRemove)
 	if err != nil {
 		return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.uRepo.GetUserByUsername : %w", err)
 	}
+	targetRole, err := uc.gRepo.GetMemberRole(groupDb.Id, userToRemove.Id)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RemoveGroupMember - uc.gRepo.GetMemberRole : %w", err)
+	}
+	// admin hanya bisa remove member biasa, owner bisa remove admin & member
+	if !role.Can(entity.GroupPermissionRemoveMember) || !role.Outranks(targetRole) {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RemoveGroupMember: %w", GroupPermissionDeniedErr)
+	}
 	removeReq := entity.RemoveGroupMemberReq{
-		Name:   e.Name,
-		UserId: userLogin.Id,
-		Member: userToRemove.Id,
+		GroupId: groupDb.Id,
+		UserId:  userLogin.Id,
+		Member:  userToRemove.Id,
 	}
 
+	// member diambil sebelum dihapus agar member yang dihapus juga mendapat system message
+	members := uc.groupMembers(groupDb.Id)
 	group, err := uc.gRepo.RemoveMember(ctx, removeReq)
 	if err != nil {
 		return entity.Group{}, fmt.Errorf("GroupUseCase - AddNewGroupMember - uc.gRepo.RemoveMember: %w", err)
 	}
+	uc.invalidateGroupMembers(groupDb.Id)
+
+	uc.systemMessage(group, userLogin, entity.GroupChatKindMemberRemoved,
+		fmt.Sprintf("%s removed %s", userLogin.Username, userToRemove.Username), members)
+	return group, nil
+}
+
+// ChangeMemberRole promote member menjadi admin / demote admin menjadi member, hanya owner yang bisa
+func (uc *GroupUseCase) ChangeMemberRole(ctx context.Context, e entity.ChangeGroupRoleReqUc) (entity.GroupMember, error) {
+	if e.Role != entity.GroupRoleAdmin && e.Role != entity.GroupRoleMember {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole: %w", InvalidGroupRoleErr)
+	}
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, e.Name, e.UserName)
+	if err != nil {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionManageRoles) {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole: %w", GroupPermissionDeniedErr)
+	}
+
+	member, err := uc.uRepo.GetUserByUsername(e.Member)
+	if err != nil {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if member.Id == userLogin.Id {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole: %w", CannotChangeOwnRoleErr)
+	}
+	targetRole, err := uc.gRepo.GetMemberRole(groupDb.Id, member.Id)
+	if err != nil {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole - uc.gRepo.GetMemberRole: %w", err)
+	}
+	if !role.Outranks(targetRole) {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole: %w", GroupPermissionDeniedErr)
+	}
+
+	if err = uc.gRepo.SetMemberRole(groupDb.Id, member.Id, e.Role); err != nil {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - ChangeMemberRole - uc.gRepo.SetMemberRole: %w", err)
+	}
+	return entity.GroupMember{UserId: member.Id, Role: e.Role}, nil
+}
+
+// RenameGroup mengganti nama group
+func (uc *GroupUseCase) RenameGroup(ctx context.Context, e entity.RenameGroupReqUc) (entity.Group, error) {
+	newName := strings.TrimSpace(e.NewName)
+	if newName == "" {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RenameGroup: %w", InvalidGroupNameErr)
+	}
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, e.Name, e.UserName)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RenameGroup - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionRename) {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RenameGroup: %w", GroupPermissionDeniedErr)
+	}
+
+	group, err := uc.gRepo.RenameGroup(groupDb.Id, newName)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - RenameGroup - uc.gRepo.RenameGroup: %w", err)
+	}
+	group.Description = groupDb.Description
+	group.AvatarUrl = groupDb.AvatarUrl
+
+	members := uc.groupMembers(group.Id)
+	uc.systemMessage(group, userLogin, entity.GroupChatKindRenamed,
+		fmt.Sprintf("%s renamed the group to \"%s\"", userLogin.Username, newName), members)
+	uc.groupUpdated(group, false, members)
+	return group, nil
+}
+
+// UpdateGroup mengubah nama, deskripsi & avatar group, field yang tidak dikirim tidak diubah
+func (uc *GroupUseCase) UpdateGroup(ctx context.Context, e entity.UpdateGroupReqUc) (entity.Group, error) {
+	if e.Name != nil && strings.TrimSpace(*e.Name) == "" {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - UpdateGroup: %w", InvalidGroupNameErr)
+	}
+	if e.AvatarUrl != nil && *e.AvatarUrl != "" && !isHttpUrl(*e.AvatarUrl) {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - UpdateGroup: %w", InvalidAvatarUrlErr)
+	}
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - UpdateGroup - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionEditInfo) || (e.Name != nil && !role.Can(entity.GroupPermissionRename)) {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - UpdateGroup: %w", GroupPermissionDeniedErr)
+	}
+
+	group := groupDb
+	renamed, infoChanged := false, false
+	if e.Name != nil && strings.TrimSpace(*e.Name) != group.Name {
+		group.Name = strings.TrimSpace(*e.Name)
+		renamed = true
+	}
+	if e.Description != nil && *e.Description != group.Description {
+		group.Description = *e.Description
+		infoChanged = true
+	}
+	if e.AvatarUrl != nil && *e.AvatarUrl != group.AvatarUrl {
+		group.AvatarUrl = *e.AvatarUrl
+		infoChanged = true
+	}
+	if !renamed && !infoChanged {
+		return group, nil
+	}
 
+	group, err = uc.gRepo.UpdateGroup(group)
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupUseCase - UpdateGroup - uc.gRepo.UpdateGroup: %w", err)
+	}
+
+	members := uc.groupMembers(group.Id)
+	if renamed {
+		uc.systemMessage(group, userLogin, entity.GroupChatKindRenamed,
+			fmt.Sprintf("%s renamed the group to \"%s\"", userLogin.Username, group.Name), members)
+	}
+	if infoChanged {
+		uc.systemMessage(group, userLogin, entity.GroupChatKindGroupUpdated,
+			fmt.Sprintf("%s updated the group info", userLogin.Username), members)
+	}
+	uc.groupUpdated(group, false, members)
 	return group, nil
 }
+
+// LeaveGroup user keluar dari group, owner harus transfer ownership / hapus group terlebih dahulu
+func (uc *GroupUseCase) LeaveGroup(ctx context.Context, e entity.GroupReqUc) error {
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return fmt.Errorf("GroupUseCase - LeaveGroup - uc.memberRole: %w", err)
+	}
+	if role == entity.GroupRoleOwner {
+		return fmt.Errorf("GroupUseCase - LeaveGroup: %w", OwnerCannotLeaveErr)
+	}
+
+	// member diambil sebelum user keluar agar device lain milik user juga mendapat system message
+	members := uc.groupMembers(groupDb.Id)
+	if err = uc.gRepo.LeaveGroup(groupDb.Id, userLogin.Id); err != nil {
+		return fmt.Errorf("GroupUseCase - LeaveGroup - uc.gRepo.LeaveGroup: %w", err)
+	}
+	uc.invalidateGroupMembers(groupDb.Id)
+	uc.systemMessage(groupDb, userLogin, entity.GroupChatKindMemberLeft,
+		fmt.Sprintf("%s left the group", userLogin.Username), members)
+	return nil
+}
+
+// TransferOwnership owner menyerahkan group ke member lain, owner lama menjadi admin
+func (uc *GroupUseCase) TransferOwnership(ctx context.Context, e entity.TransferOwnershipReqUc) (entity.GroupMember, error) {
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - TransferOwnership - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionTransferOwner) {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - TransferOwnership: %w", GroupPermissionDeniedErr)
+	}
+	newOwner, err := uc.uRepo.GetUserByUsername(e.NewOwner)
+	if err != nil {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - TransferOwnership - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if newOwner.Id == userLogin.Id {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - TransferOwnership: %w", CannotTransferToSelfErr)
+	}
+
+	if err = uc.gRepo.TransferOwnership(groupDb.Id, userLogin.Id, newOwner.Id); err != nil {
+		return entity.GroupMember{}, fmt.Errorf("GroupUseCase - TransferOwnership - uc.gRepo.TransferOwnership: %w", err)
+	}
+	uc.systemMessage(groupDb, userLogin, entity.GroupChatKindOwnershipTransferred,
+		fmt.Sprintf("%s transferred group ownership to %s", userLogin.Username, newOwner.Username), uc.groupMembers(groupDb.Id))
+	return entity.GroupMember{UserId: newOwner.Id, Role: entity.GroupRoleOwner}, nil
+}
+
+// DeleteGroup soft delete group, hanya owner yang bisa
+func (uc *GroupUseCase) DeleteGroup(ctx context.Context, e entity.GroupReqUc) error {
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return fmt.Errorf("GroupUseCase - DeleteGroup - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionDeleteGroup) {
+		return fmt.Errorf("GroupUseCase - DeleteGroup: %w", GroupPermissionDeniedErr)
+	}
+
+	members := uc.groupMembers(groupDb.Id)
+	if err = uc.gRepo.DeleteGroup(groupDb.Id); err != nil {
+		return fmt.Errorf("GroupUseCase - DeleteGroup - uc.gRepo.DeleteGroup: %w", err)
+	}
+	uc.invalidateGroupMembers(groupDb.Id)
+	uc.systemMessage(groupDb, userLogin, entity.GroupChatKindGroupDeleted,
+		fmt.Sprintf("%s deleted the group", userLogin.Username), members)
+	uc.groupUpdated(groupDb, true, members)
+	return nil
+}
+
+// PinMessage pin / unpin pesan di group
+func (uc *GroupUseCase) PinMessage(ctx context.Context, e entity.GroupMessageActionReqUc, pinned bool) error {
+	_, groupDb, role, err := uc.memberRole(e.GroupId, e.Name, e.UserName)
+	if err != nil {
+		return fmt.Errorf("GroupUseCase - PinMessage - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionPinMessage) {
+		return fmt.Errorf("GroupUseCase - PinMessage: %w", GroupPermissionDeniedErr)
+	}
+
+	if err = uc.gcRepo.SetPinned(groupDb.Id, e.MessageId, pinned); err != nil {
+		return fmt.Errorf("GroupUseCase - PinMessage - uc.gcRepo.SetPinned: %w", err)
+	}
+	return nil
+}
+
+// DeleteMessage menghapus pesan di group, pesan milik member lain hanya bisa dihapus role dengan permission delete_message
+func (uc *GroupUseCase) DeleteMessage(ctx context.Context, e entity.GroupMessageActionReqUc) error {
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, e.Name, e.UserName)
+	if err != nil {
+		return fmt.Errorf("GroupUseCase - DeleteMessage - uc.memberRole: %w", err)
+	}
+	msg, err := uc.gcRepo.GetMessageById(groupDb.Id, e.MessageId)
+	if err != nil {
+		return fmt.Errorf("GroupUseCase - DeleteMessage - uc.gcRepo.GetMessageById: %w", err)
+	}
+	if msg.UserId != userLogin.Id && !role.Can(entity.GroupPermissionDeleteMessage) {
+		return fmt.Errorf("GroupUseCase - DeleteMessage: %w", GroupPermissionDeniedErr)
+	}
+
+	if err = uc.gcRepo.DeleteMessage(groupDb.Id, e.MessageId); err != nil {
+		return fmt.Errorf("GroupUseCase - DeleteMessage - uc.gcRepo.DeleteMessage: %w", err)
+	}
+	return nil
+}
+
+// memberRole mendapatkan user yang login, group & role user di group tsb
+func (uc *GroupUseCase) memberRole(groupId uuid.UUID, groupName string, username string) (entity.GetUser, entity.Group, entity.GroupRole, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return entity.GetUser{}, entity.Group{}, "", err
+	}
+	group, err := findGroup(uc.gRepo, groupId, groupName, userLogin.Id)
+	if err != nil {
+		return entity.GetUser{}, entity.Group{}, "", err
+	}
+	role, err := uc.gRepo.GetMemberRole(group.Id, userLogin.Id)
+	if err != nil {
+		return entity.GetUser{}, entity.Group{}, "", err
+	}
+	return userLogin, group, role, nil
+}
+
+// findGroup mendapatkan group by id, user harus member group.
+// groupName hanya dipakai oleh client lama yang belum mengirim group id (masa transisi)
+func findGroup(gRepo GroupRepo, groupId uuid.UUID, groupName string, userId uuid.UUID) (entity.Group, error) {
+	if groupId != uuid.Nil {
+		return gRepo.GetGroupById(groupId, userId)
+	}
+	if groupName != "" {
+		return gRepo.GetGroupByName(groupName, userId)
+	}
+	return entity.Group{}, GroupIdRequiredErr
+}
+
+// checkNotBlocked cek apakah member yang akan ditambahkan ke group tidak memblokir user yang menambahkan
+func (uc *GroupUseCase) checkNotBlocked(ctx context.Context, memberId uuid.UUID, userId uuid.UUID) error {
+	blocked, err := uc.blockRepo.IsBlocked(ctx, memberId, userId)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return BlockedByUserErr
+	}
+	return nil
+}
+
+// isHttpUrl cek apakah s adalah url absolut dengan scheme http / https
+func isHttpUrl(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"time"
+)
+
+// systemMessage menyimpan system message ke group_chats lalu mengirimnya ke semua member group
+// lewat jalur yang sama dengan pesan group chat biasa (MessageTypeGroupChat) dengan kind terisi
+func (uc *GroupUseCase) systemMessage(group entity.Group, actor entity.GetUser, kind entity.GroupChatKind, content string, members []entity.GroupRecipient) {
+	msgId, err := uc.idGen.GenerateId()
+	if err != nil {
+		log.Println("GroupUseCase - systemMessage - uc.idGen.GenerateId: ", err)
+		return
+	}
+	msg, err := uc.gcRepo.InsertNewChat(entity.GroupChatMessage{
+		GroupId:   group.Id,
+		MessageId: msgId,
+		UserId:    actor.Id,
+		Kind:      kind,
+		Content:   content,
+		Format:    entity.MessageFormatPlain,
+	})
+	if err != nil {
+		log.Println("GroupUseCase - systemMessage - uc.gcRepo.InsertNewChat: ", err)
+		return
+	}
+
+	msgWs := &entity.MessageWs{
+		Type: entity.MessageTypeGroupChat,
+		MsgGroupChat: entity.MessageGroupChat{
+			GroupId:        group.Id,
+			GroupName:      group.Name,
+			MessageId:      msg.MessageId,
+			SenderUsername: actor.Username,
+			Kind:           kind,
+			Content:        content,
+			Format:         entity.MessageFormatPlain,
+			CreatedAt:      time.Now(),
+		},
+	}
+	// system message juga dikirim ke device lain milik user yang melakukan perubahan
+	uc.fanout.FanoutGroupChat(msgWs, members, "")
+}
+
+// groupUpdated mengirim info group terbaru ke semua member group
+func (uc *GroupUseCase) groupUpdated(group entity.Group, deleted bool, members []entity.GroupRecipient) {
+	msgWs := &entity.MessageWs{
+		Type: entity.MessageTypeGroupUpdated,
+		MsgGroupUpdated: entity.MessageGroupUpdated{
+			GroupId:     group.Id,
+			Name:        group.Name,
+			Description: group.Description,
+			AvatarUrl:   group.AvatarUrl,
+			Deleted:     deleted,
+		},
+	}
+	uc.publishToMembers(members, func(recipient string) *entity.MessageWs {
+		msgWs.MsgGroupUpdated.RecipientUsername = recipient
+		return msgWs
+	})
+}
+
+// publishToMembers publish message ke semua chat-server tempat member group punya koneksi websocket,
+// msgFor mengisi recipient username message untuk setiap member
+func (uc *GroupUseCase) publishToMembers(members []entity.GroupRecipient, msgFor func(recipient string) *entity.MessageWs) {
+	for _, member := range members {
+		servers, err := uc.usrRedis.GetUserSessionServers(member.UserId.String())
+		if err != nil {
+			log.Println("GroupUseCase - publishToMembers - uc.usrRedis.GetUserSessionServers: ", err)
+			continue
+		}
+		msgWs := msgFor(member.Username)
+		for _, server := range servers {
+			if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+				log.Println("GroupUseCase - publishToMembers - uc.pubSub.PublishToChannel: ", err)
+			}
+		}
+	}
+}
+
+// groupMembers id & username semua member group (dari cache), error diabaikan karena hanya dipakai untuk notifikasi
+func (uc *GroupUseCase) groupMembers(groupId uuid.UUID) []entity.GroupRecipient {
+	members, err := groupRecipients(uc.gRepo, uc.gRedis, groupId)
+	if err != nil {
+		log.Println("GroupUseCase - groupMembers - groupRecipients: ", err)
+		return nil
+	}
+	return members
+}
+
+// invalidateGroupMembers menghapus cache member group setelah member group berubah
+func (uc *GroupUseCase) invalidateGroupMembers(groupId uuid.UUID) {
+	if err := uc.gRedis.InvalidateGroupRecipients(groupId.String()); err != nil {
+		log.Println("GroupUseCase - invalidateGroupMembers - uc.gRedis.InvalidateGroupRecipients: ", err)
+	}
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+)
+
+// groupRecipients mendapatkan id & username member group dari cache redis,
+// jika cache belum ada diambil dari db lalu disimpan ke cache
+func groupRecipients(gRepo GroupRepo, gRedis GroupRedisRepo, groupId uuid.UUID) ([]entity.GroupRecipient, error) {
+	recipients, err := gRedis.GetGroupRecipients(groupId.String())
+	if err != nil {
+		log.Println("groupRecipients - gRedis.GetGroupRecipients: ", err)
+	}
+	if recipients != nil {
+		return recipients, nil
+	}
+
+	recipients, err = gRepo.GetGroupRecipients(groupId)
+	if err != nil {
+		return nil, err
+	}
+	if err = gRedis.SetGroupRecipients(groupId.String(), recipients); err != nil {
+		log.Println("groupRecipients - gRedis.SetGroupRecipients: ", err)
+	}
+	return recipients, nil
+}
+
+// FanoutGroupChat mengirim pesan group chat (termasuk system message) ke semua member group.
+// 1 message dikirim ke setiap chat-server berisi semua recipient di chat-server tsb.
+// skipUsername (pengirim pesan) tidak dikirimi pesan, kosong berarti semua member dikirimi
+func (c *ChatHub) FanoutGroupChat(msgWs *entity.MessageWs, members []entity.GroupRecipient, skipUsername string) {
+	// member yang mute group tetap dikirimi pesan, hanya ditandai muted
+	muted := c.mutedGroupMembers(msgWs.MsgGroupChat.GroupId)
+	// member yang memblokir pengirim tetap dikirimi pesan, client yang menyembunyikan pesan
+	blockers := make(map[uuid.UUID]bool)
+	for _, member := range members {
+		if member.Username == msgWs.MsgGroupChat.SenderUsername {
+			// jika gagal diambil pesan tetap dikirim tanpa ditandai
+			blockers, _ = c.blockedBy(member.UserId)
+			break
+		}
+	}
+
+	for server, recipients := range c.recipientsByServer(members, skipUsername) {
+		var usernames, mutedUsernames, blockerUsernames []string
+		for _, recipient := range recipients {
+			usernames = append(usernames, recipient.Username)
+			if muted[recipient.UserId] {
+				mutedUsernames = append(mutedUsernames, recipient.Username)
+			}
+			if blockers[recipient.UserId] {
+				blockerUsernames = append(blockerUsernames, recipient.Username)
+			}
+		}
+		// setiap chat-server mendapat salinan message, message yang sudah di broadcast tidak boleh diubah lagi
+		msgServer := *msgWs
+		msgServer.MsgGroupChat.RecipientUsername = ""
+		msgServer.MsgGroupChat.RecipientUsernames = usernames
+		msgServer.MsgGroupChat.MutedUsernames = mutedUsernames
+		msgServer.MsgGroupChat.BlockerUsernames = blockerUsernames
+		c.sendToServer(server, &msgServer)
+	}
+}
+
+// FanoutGroupChatBot mengirim jawaban chatbot ke semua member group, 1 message per chat-server seperti FanoutGroupChat.
+// skipUsername (yang memanggil chatbot) tidak dikirimi pesan
+func (c *ChatHub) FanoutGroupChatBot(msgWs *entity.MessageWs, members []entity.GroupRecipient, skipUsername string) {
+	for server, recipients := range c.recipientsByServer(members, skipUsername) {
+		usernames := make([]string, 0, len(recipients))
+		for _, recipient := range recipients {
+			usernames = append(usernames, recipient.Username)
+		}
+		// setiap chat-server mendapat salinan message, message yang sudah di broadcast tidak boleh diubah lagi
+		msgServer := *msgWs
+		msgServer.MsgGroupChatBot.RecipientUsername = ""
+		msgServer.MsgGroupChatBot.RecipientUsernames = usernames
+		c.sendToServer(server, &msgServer)
+	}
+}
+
+// recipientsByServer mengelompokkan member group yang online berdasarkan chat-server tempat member terhubung.
+// Status online & lokasi chat-server semua member diambil sekaligus, member yang offline tidak diikutkan
+func (c *ChatHub) recipientsByServer(members []entity.GroupRecipient, skipUsername string) map[string][]entity.GroupRecipient {
+	var recipients []entity.GroupRecipient
+	userIds := make([]string, 0, len(members))
+	for _, member := range members {
+		if member.Username == skipUsername {
+			continue
+		}
+		recipients = append(recipients, member)
+		userIds = append(userIds, member.UserId.String())
+	}
+
+	presences, err := c.usrRedis.GetUsersPresence(userIds)
+	if err != nil {
+		log.Println("recipientsByServer - c.usrRedis.GetUsersPresence: ", err)
+		return nil
+	}
+
+	serverRecipients := make(map[string][]entity.GroupRecipient)
+	for i, presence := range presences {
+		if !presence.Online || presence.ServerLocation == "" {
+			continue
+		}
+		serverRecipients[presence.ServerLocation] = append(serverRecipients[presence.ServerLocation], recipients[i])
+	}
+	return serverRecipients
+}
+
+// sendToServer mengirim message ke chat-server, lewat broadcast jika chat-server ini, selain itu lewat PubSubRedis
+func (c *ChatHub) sendToServer(server string, msgWs *entity.MessageWs) {
+	if server == entity.ChatServerNameGlobal.ChatServerName {
+		// recipient berada di chat-server yg sama dg chat-server user
+		c.broadcast <- msgWs
+		return
+	}
+	// recipient berada di chat-server lain, publish sekali ke chat-server tsb
+	c.PubSub.PublishToChannel(server, msgWs)
+}
+
+// deliverGroupChat mengirim pesan group chat ke semua recipient di RecipientUsernames yang terhubung ke chat-server ini,
+// setiap koneksi mendapat salinan message dengan recipient_username terisi seperti sebelumnya
+func (c *ChatHub) deliverGroupChat(message *entity.MessageWs) {
+	rcpGroupChat := make(map[string]bool, len(message.MsgGroupChat.RecipientUsernames))
+	for _, username := range message.MsgGroupChat.RecipientUsernames {
+		rcpGroupChat[username] = true
+	}
+	mutedGroupChat := make(map[string]bool, len(message.MsgGroupChat.MutedUsernames))
+	for _, username := range message.MsgGroupChat.MutedUsernames {
+		mutedGroupChat[username] = true
+	}
+
+	blockerGroupChat := make(map[string]bool, len(message.MsgGroupChat.BlockerUsernames))
+	for _, username := range message.MsgGroupChat.BlockerUsernames {
+		blockerGroupChat[username] = true
+	}
+
+	for _, user := range c.us {
+		if !rcpGroupChat[user.Name] {
+			continue
+		}
+		msgUser := *message
+		msgUser.MsgGroupChat.RecipientUsername = user.Name
+		msgUser.MsgGroupChat.RecipientUsernames = nil
+		msgUser.MsgGroupChat.MutedUsernames = nil
+		msgUser.MsgGroupChat.BlockerUsernames = nil
+		msgUser.MsgGroupChat.Muted = mutedGroupChat[user.Name]
+		msgUser.MsgGroupChat.SenderBlocked = blockerGroupChat[user.Name]
+		select {
+		case user.inbox <- &msgUser:
+		}
+	}
+}
+
+// deliverGroupChatBot mengirim jawaban chatbot group ke semua recipient di RecipientUsernames yang terhubung ke chat-server ini
+func (c *ChatHub) deliverGroupChatBot(message *entity.MessageWs) {
+	rcpGroupChatBot := make(map[string]bool, len(message.MsgGroupChatBot.RecipientUsernames))
+	for _, username := range message.MsgGroupChatBot.RecipientUsernames {
+		rcpGroupChatBot[username] = true
+	}
+
+	for _, user := range c.us {
+		if !rcpGroupChatBot[user.Name] {
+			continue
+		}
+		msgUser := *message
+		msgUser.MsgGroupChatBot.RecipientUsername = user.Name
+		msgUser.MsgGroupChatBot.RecipientUsernames = nil
+		select {
+		case user.inbox <- &msgUser:
+		}
+	}
+}
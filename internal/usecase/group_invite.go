@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"time"
+)
+
+const inviteTokenBytes = 18
+
+// MaxInviteExpiry batas waktu kadaluarsa invite link group
+const MaxInviteExpiry = 90 * 24 * time.Hour
+
+var (
+	InvalidInviteErr       = errors.New("max_uses can not be negative and expires_in must be between 0 and 90 days")
+	InviteTokenRequiredErr = errors.New("invite token is required")
+)
+
+// CreateInvite membuat invite link group, hanya owner & admin yang bisa
+func (uc *GroupUseCase) CreateInvite(ctx context.Context, e entity.CreateGroupInviteReqUc) (entity.GroupInvite, error) {
+	if e.MaxUses < 0 || e.ExpiresIn < 0 || e.ExpiresIn > MaxInviteExpiry {
+		return entity.GroupInvite{}, fmt.Errorf("GroupUseCase - CreateInvite: %w", InvalidInviteErr)
+	}
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return entity.GroupInvite{}, fmt.Errorf("GroupUseCase - CreateInvite - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionManageInvites) {
+		return entity.GroupInvite{}, fmt.Errorf("GroupUseCase - CreateInvite: %w", GroupPermissionDeniedErr)
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		return entity.GroupInvite{}, fmt.Errorf("GroupUseCase - CreateInvite - generateInviteToken: %w", err)
+	}
+	invite := entity.GroupInvite{
+		GroupId:          groupDb.Id,
+		Token:            token,
+		CreatedBy:        userLogin.Id,
+		MaxUses:          e.MaxUses,
+		ApprovalRequired: e.ApprovalRequired,
+	}
+	if e.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(e.ExpiresIn)
+		invite.ExpiresAt = &expiresAt
+	}
+
+	invite, err = uc.inviteRepo.CreateInvite(ctx, invite)
+	if err != nil {
+		return entity.GroupInvite{}, fmt.Errorf("GroupUseCase - CreateInvite - uc.inviteRepo.CreateInvite: %w", err)
+	}
+	return invite, nil
+}
+
+// GetInvites mendapatkan semua invite link group yang masih aktif, hanya owner & admin yang bisa
+func (uc *GroupUseCase) GetInvites(ctx context.Context, e entity.GroupInviteReqUc) ([]entity.GroupInvite, error) {
+	_, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return nil, fmt.Errorf("GroupUseCase - GetInvites - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionManageInvites) {
+		return nil, fmt.Errorf("GroupUseCase - GetInvites: %w", GroupPermissionDeniedErr)
+	}
+
+	invites, err := uc.inviteRepo.GetActiveInvites(ctx, groupDb.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GroupUseCase - GetInvites - uc.inviteRepo.GetActiveInvites: %w", err)
+	}
+	return invites, nil
+}
+
+// RevokeInvite revoke invite link group, hanya owner & admin yang bisa
+func (uc *GroupUseCase) RevokeInvite(ctx context.Context, e entity.GroupInviteReqUc) error {
+	_, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return fmt.Errorf("GroupUseCase - RevokeInvite - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionManageInvites) {
+		return fmt.Errorf("GroupUseCase - RevokeInvite: %w", GroupPermissionDeniedErr)
+	}
+
+	if err = uc.inviteRepo.RevokeInvite(ctx, groupDb.Id, e.InviteId); err != nil {
+		return fmt.Errorf("GroupUseCase - RevokeInvite - uc.inviteRepo.RevokeInvite: %w", err)
+	}
+	return nil
+}
+
+// JoinGroup join group lewat invite link, user tidak perlu berteman dengan pembuat invite.
+// Jika invite link butuh approval, dibuat join request pending & owner/admin group dinotifikasi
+func (uc *GroupUseCase) JoinGroup(ctx context.Context, e entity.JoinGroupReqUc) (entity.JoinGroupResult, error) {
+	if e.Token == "" {
+		return entity.JoinGroupResult{}, fmt.Errorf("GroupUseCase - JoinGroup: %w", InviteTokenRequiredErr)
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.JoinGroupResult{}, fmt.Errorf("GroupUseCase - JoinGroup - uc.uRepo.GetUserByUsername: %w", err)
+	}
+
+	invite, err := uc.inviteRepo.GetInviteByToken(ctx, e.Token)
+	if err != nil {
+		return entity.JoinGroupResult{}, fmt.Errorf("GroupUseCase - JoinGroup - uc.inviteRepo.GetInviteByToken: %w", err)
+	}
+	if invite.ApprovalRequired {
+		joinReq, err := uc.requestToJoin(ctx, invite, userLogin)
+		if err != nil {
+			return entity.JoinGroupResult{}, fmt.Errorf("GroupUseCase - JoinGroup - uc.requestToJoin: %w", err)
+		}
+		return entity.JoinGroupResult{
+			Group:       entity.Group{Id: joinReq.GroupId, Name: joinReq.GroupName},
+			JoinRequest: &joinReq,
+		}, nil
+	}
+
+	// batas member group dicek di transaksi redeem invite link
+	group, err := uc.inviteRepo.RedeemInvite(ctx, e.Token, userLogin.Id, uc.limits.MaxMembers)
+	if err != nil {
+		return entity.JoinGroupResult{}, fmt.Errorf("GroupUseCase - JoinGroup - uc.inviteRepo.RedeemInvite: %w", err)
+	}
+	uc.invalidateGroupMembers(group.Id)
+	uc.systemMessage(group, userLogin, entity.GroupChatKindMemberAdded,
+		fmt.Sprintf("%s joined the group via invite link", userLogin.Username), uc.groupMembers(group.Id))
+	return entity.JoinGroupResult{Group: group}, nil
+}
+
+// generateInviteToken token random yang aman dipakai di url
+func generateInviteToken() (string, error) {
+	b := make([]byte, inviteTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/internal/usecase/repo"
+	"log"
+	"time"
+)
+
+// requestToJoin membuat join request pending lalu mengirim notifikasi ke semua owner & admin group
+func (uc *GroupUseCase) requestToJoin(ctx context.Context, invite entity.GroupInvite, user entity.GetUser) (entity.GroupJoinRequest, error) {
+	if _, err := uc.gRepo.GetMemberRole(invite.GroupId, user.Id); err == nil {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupUseCase - requestToJoin - uc.gRepo.GetMemberRole: %w", repo.UserAlreadyMembersErr)
+	}
+
+	joinReq, err := uc.joinRepo.CreateJoinRequest(ctx, entity.GroupJoinRequest{
+		GroupId:  invite.GroupId,
+		UserId:   user.Id,
+		InviteId: invite.Id,
+	})
+	if err != nil {
+		return entity.GroupJoinRequest{}, err
+	}
+
+	admins, err := uc.gRepo.GetGroupAdmins(invite.GroupId)
+	if err != nil {
+		log.Println("GroupUseCase - requestToJoin - uc.gRepo.GetGroupAdmins: ", err)
+		return joinReq, nil
+	}
+	for _, adminId := range admins {
+		admin, err := uc.uRepo.GetUserById(adminId)
+		if err != nil {
+			log.Println("GroupUseCase - requestToJoin - uc.uRepo.GetUserById: ", err)
+			continue
+		}
+		uc.notifyJoinRequest(entity.MessageTypeGroupJoinRequest, joinReq, admin)
+	}
+	return joinReq, nil
+}
+
+// GetJoinRequests mendapatkan semua join request group yang masih pending, hanya owner & admin yang bisa
+func (uc *GroupUseCase) GetJoinRequests(ctx context.Context, e entity.GroupInviteReqUc) ([]entity.GroupJoinRequest, error) {
+	_, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return nil, fmt.Errorf("GroupUseCase - GetJoinRequests - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionApproveJoin) {
+		return nil, fmt.Errorf("GroupUseCase - GetJoinRequests: %w", GroupPermissionDeniedErr)
+	}
+
+	joinReqs, err := uc.joinRepo.GetPendingJoinRequests(ctx, groupDb.Id)
+	if err != nil {
+		return nil, fmt.Errorf("GroupUseCase - GetJoinRequests - uc.joinRepo.GetPendingJoinRequests: %w", err)
+	}
+	return joinReqs, nil
+}
+
+// DecideJoinRequest approve / deny join request, hanya owner & admin yang bisa.
+// Jika di approve user ditambahkan sebagai member group, approve gagal jika invite link request sudah tidak bisa dipakai.
+// User yang request dinotifikasi lewat websocket
+func (uc *GroupUseCase) DecideJoinRequest(ctx context.Context, e entity.DecideJoinRequestReqUc) (entity.GroupJoinRequest, error) {
+	userLogin, groupDb, role, err := uc.memberRole(e.GroupId, "", e.UserName)
+	if err != nil {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupUseCase - DecideJoinRequest - uc.memberRole: %w", err)
+	}
+	if !role.Can(entity.GroupPermissionApproveJoin) {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupUseCase - DecideJoinRequest: %w", GroupPermissionDeniedErr)
+	}
+
+	joinReq, err := uc.joinRepo.GetJoinRequest(ctx, groupDb.Id, e.RequestId)
+	if err != nil {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupUseCase - DecideJoinRequest - uc.joinRepo.GetJoinRequest: %w", err)
+	}
+	if joinReq.Status != entity.GroupJoinRequestPending {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupUseCase - DecideJoinRequest: %w", repo.JoinRequestAlreadyDecidedErr)
+	}
+
+	status := entity.GroupJoinRequestDenied
+	if e.Approve {
+		status = entity.GroupJoinRequestApproved
+		// status, member baru & pemakaian invite link disimpan dalam 1 transaksi, termasuk cek batas member group
+		if err = uc.joinRepo.ApproveJoinRequest(ctx, joinReq, userLogin.Id, uc.limits.MaxMembers); err != nil {
+			return entity.GroupJoinRequest{}, fmt.Errorf("GroupUseCase - DecideJoinRequest - uc.joinRepo.ApproveJoinRequest: %w", err)
+		}
+		uc.invalidateGroupMembers(groupDb.Id)
+	} else if err = uc.joinRepo.SetJoinRequestStatus(ctx, joinReq.Id, status, userLogin.Id); err != nil {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupUseCase - DecideJoinRequest - uc.joinRepo.SetJoinRequestStatus: %w", err)
+	}
+	decidedAt := time.Now()
+	joinReq.Status = status
+	joinReq.DecidedBy = userLogin.Id
+	joinReq.DecidedAt = &decidedAt
+
+	requester := entity.GetUser{Id: joinReq.UserId, Username: joinReq.Username}
+	uc.notifyJoinRequest(entity.MessageTypeGroupJoinDecision, joinReq, requester)
+	if e.Approve {
+		uc.systemMessage(groupDb, userLogin, entity.GroupChatKindMemberAdded,
+			fmt.Sprintf("%s added %s", userLogin.Username, joinReq.Username), uc.groupMembers(groupDb.Id))
+	}
+	return joinReq, nil
+}
+
+// notifyJoinRequest mengirim message join request ke semua koneksi websocket recipient di chat-server manapun
+func (uc *GroupUseCase) notifyJoinRequest(msgType entity.MessageType, joinReq entity.GroupJoinRequest, recipient entity.GetUser) {
+	msgWs := &entity.MessageWs{
+		Type: msgType,
+		MsgGroupJoinRequest: entity.MessageGroupJoinRequest{
+			RequestId:         joinReq.Id,
+			GroupId:           joinReq.GroupId,
+			GroupName:         joinReq.GroupName,
+			Username:          joinReq.Username,
+			Status:            joinReq.Status,
+			RecipientUsername: recipient.Username,
+		},
+	}
+
+	servers, err := uc.usrRedis.GetUserSessionServers(recipient.Id.String())
+	if err != nil {
+		log.Println("GroupUseCase - notifyJoinRequest - uc.usrRedis.GetUserSessionServers: ", err)
+		return
+	}
+	for _, server := range servers {
+		if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+			log.Println("GroupUseCase - notifyJoinRequest - uc.pubSub.PublishToChannel: ", err)
+		}
+	}
+}
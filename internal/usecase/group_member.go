@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+)
+
+const (
+	defaultGroupMembersLimit = 50
+	maxGroupMembersLimit     = 200
+)
+
+// GetGroupMembers mendapatkan member group per halaman, user harus member group.
+// Status online member hanya diisi jika group tidak masuk mode large group
+func (uc *GroupUseCase) GetGroupMembers(ctx context.Context, e entity.GroupMembersReqUc) (entity.GroupMembers, error) {
+	if e.GroupId == uuid.Nil {
+		return entity.GroupMembers{}, fmt.Errorf("GroupUseCase - GetGroupMembers: %w", GroupIdRequiredErr)
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.GroupMembers{}, fmt.Errorf("GroupUseCase - GetGroupMembers - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if _, err = uc.gRepo.GetGroupById(e.GroupId, userLogin.Id); err != nil {
+		return entity.GroupMembers{}, fmt.Errorf("GroupUseCase - GetGroupMembers - uc.gRepo.GetGroupById: %w", err)
+	}
+
+	limit := e.Limit
+	if limit <= 0 {
+		limit = defaultGroupMembersLimit
+	}
+	if limit > maxGroupMembersLimit {
+		limit = maxGroupMembersLimit
+	}
+	total, err := uc.gRepo.CountGroupMembers(e.GroupId)
+	if err != nil {
+		return entity.GroupMembers{}, fmt.Errorf("GroupUseCase - GetGroupMembers - uc.gRepo.CountGroupMembers: %w", err)
+	}
+	members, err := uc.gRepo.GetGroupMembers(e.GroupId, e.AfterId, limit)
+	if err != nil {
+		return entity.GroupMembers{}, fmt.Errorf("GroupUseCase - GetGroupMembers - uc.gRepo.GetGroupMembers: %w", err)
+	}
+
+	res := entity.GroupMembers{
+		GroupId:    e.GroupId,
+		Total:      total,
+		LargeGroup: uc.limits.IsLargeGroup(total),
+		Members:    members,
+	}
+	if len(members) == limit {
+		res.NextAfterId = members[len(members)-1].UserId
+	}
+	if !res.LargeGroup {
+		uc.setMembersOnline(ctx, userLogin, res.Members)
+	}
+	return res, nil
+}
+
+// setMembersOnline mengisi status online member, diambil sekaligus dalam 1 round-trip ke redis.
+// Member yang tidak mengizinkan viewer melihat status onlinenya / memblokir viewer selalu terlihat offline
+func (uc *GroupUseCase) setMembersOnline(ctx context.Context, viewer entity.GetUser, members []entity.GroupMemberDetail) {
+	userIds := make([]string, len(members))
+	memberIds := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		userIds[i] = member.UserId.String()
+		memberIds[i] = member.UserId
+	}
+	presences, err := uc.usrRedis.GetUsersPresence(userIds)
+	if err != nil {
+		log.Println("GroupUseCase - setMembersOnline - uc.usrRedis.GetUsersPresence: ", err)
+		return
+	}
+	settings, err := uc.profileRepo.GetPrivacySettingsByUserIds(ctx, memberIds)
+	if err != nil {
+		log.Println("GroupUseCase - setMembersOnline - uc.profileRepo.GetPrivacySettingsByUserIds: ", err)
+		return
+	}
+	blockerIds, err := uc.blockRepo.GetBlockerIds(ctx, viewer.Id)
+	if err != nil {
+		log.Println("GroupUseCase - setMembersOnline - uc.blockRepo.GetBlockerIds: ", err)
+		return
+	}
+	blockers := make(map[uuid.UUID]bool, len(blockerIds))
+	for _, blockerId := range blockerIds {
+		blockers[blockerId] = true
+	}
+	contacts := make(map[uuid.UUID]bool)
+	if userFriends, err := uc.uRepo.GetUserFriends(ctx, viewer.Username); err == nil {
+		for _, friend := range userFriends.Friends {
+			contacts[friend.Id] = true
+		}
+	}
+
+	for i := range presences {
+		memberId := members[i].UserId
+		online := presences[i].Online
+		if memberId != viewer.Id {
+			online = online && !blockers[memberId] && settings[memberId].CanSeeOnlineStatus(viewer.Id, contacts[memberId])
+		}
+		members[i].Online = &online
+	}
+}
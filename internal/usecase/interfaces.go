@@ -6,9 +6,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/internal/util/contentfilter"
 	"github.com/lintangbs/chat-be/pkg/redispkg"
 	"github.com/redis/go-redis/v9"
+	"io"
 	"net/http"
+	"time"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=./mocks_test.go -package=usecase_test
@@ -37,18 +40,23 @@ type (
 		Login(context.Context, entity.LoginUserRequest) (entity.LoginUserResponse, error)
 		RenewAccessToken(context.Context, entity.RenewAccessTokenRequest) (entity.RenewAccessTokenResponse, error)
 		DeleteRefreshToken(context.Context, entity.DeleteRefreshTokenRequest) error
+		IsUserSuspended(context.Context, string) (bool, error)
 	}
 
 	// AuthRepo
 	UserRepo interface {
 		CreateUser(context.Context, entity.CreateUserRequest) (entity.UserResponse, error)
 		GetUser(context.Context, string) (entity.GetUser, error)
-		AddFriend(context.Context, string, string) (entity.UserResponse, error)
 		GetUserFriends(context.Context, string) (entity.UserResponse, error)
 		GetUserFriend(context.Context, string, string) error
+		RemoveContact(context.Context, uuid.UUID, uuid.UUID) error
+		UpdateContact(context.Context, uuid.UUID, uuid.UUID, *string, *string) (entity.UserResponse, error)
 		//GetAllUsers(context.Context) ([]entity.UserResponse, error)
 		GetUserByUsername(string) (entity.GetUser, error)
 		GetUserById(uuid.UUID) (entity.GetUser, error)
+		SetUserSuspended(context.Context, uuid.UUID, bool) error
+		GetUsersWithStaleEmailHash(context.Context, string, int) ([]entity.UserEmail, error)
+		SetEmailHash(context.Context, uuid.UUID, string, string) error
 	}
 
 	// SessionRepo
@@ -56,6 +64,7 @@ type (
 		CreateSession(context.Context, entity.CreateSessionRequest) (entity.Session, error)
 		GetSession(context.Context, uuid.UUID) (entity.Session, error)
 		DeleteSession(context.Context, uuid.UUID) error
+		DeleteSessionsByUsername(context.Context, string) error
 	}
 
 	// Websocket usecase
@@ -75,14 +84,111 @@ type (
 		SubscribePubSubAndSendToClient(*redispkg.ChannelPubSub)
 	}
 
+	// GroupChatFanout mengirim pesan group chat ke semua member group (diimplementasikan ChatHub)
+	GroupChatFanout interface {
+		FanoutGroupChat(*entity.MessageWs, []entity.GroupRecipient, string)
+	}
+
+	// PresenceFanout mengirim status online & status user ke semua kontaknya
+	// dan mencatat aktivitas user (diimplementasikan ChatHub)
+	PresenceFanout interface {
+		FanoutUserStatus(string)
+		FanoutPrivacyChange(string, entity.PrivacySettings)
+		MarkUserActive(string, string)
+	}
+
 	// EdenAiApi
 	EdenAiApi interface {
 		GenerateText(string) (string, error)
 	}
 
 	Contact interface {
-		AddContact(context.Context, entity.AddFriendRequest) (entity.UserResponse, error)
 		GetContact(context.Context, entity.GetContactRequest) (entity.UserResponse, error)
+		RemoveContact(context.Context, entity.AddFriendRequest) error
+		UpdateContact(context.Context, entity.UpdateContactReqUc) (entity.UserResponse, error)
+		SendFriendRequest(context.Context, entity.AddFriendRequest) (entity.FriendRequest, error)
+		GetFriendRequests(context.Context, entity.GetContactRequest) (entity.FriendRequests, error)
+		AcceptFriendRequest(context.Context, entity.FriendRequestReqUc) (entity.FriendRequest, error)
+		DeclineFriendRequest(context.Context, entity.FriendRequestReqUc) (entity.FriendRequest, error)
+		CancelFriendRequest(context.Context, entity.FriendRequestReqUc) (entity.FriendRequest, error)
+		GetDiscoverySalt(context.Context) entity.ContactDiscoverySalt
+		DiscoverContacts(context.Context, entity.ContactDiscoveryReqUc) (entity.ContactDiscoveryResult, error)
+	}
+
+	// ContactDiscoveryRedisRepo kuota pencarian user dari hash email per user & per ip
+	ContactDiscoveryRedisRepo interface {
+		ConsumeQuota(string, int64, int64, time.Duration) (bool, int64, time.Duration, error)
+		ReleaseQuota(string, int64) error
+	}
+
+	// EmailHasher hash email untuk contact discovery (salt untuk client, hmac dengan secret server untuk database)
+	EmailHasher interface {
+		Salt() string
+		ClientHash(string) string
+		Index(string) string
+		Key() string
+	}
+
+	// BlockRepo user yang diblokir user lain
+	BlockRepo interface {
+		Block(context.Context, uuid.UUID, uuid.UUID) error
+		Unblock(context.Context, uuid.UUID, uuid.UUID) error
+		GetBlockedUsers(context.Context, uuid.UUID) ([]entity.BlockedUser, error)
+		IsBlocked(context.Context, uuid.UUID, uuid.UUID) (bool, error)
+		GetBlockerIds(context.Context, uuid.UUID) ([]uuid.UUID, error)
+	}
+
+	// Block UseCase block / unblock user
+	Block interface {
+		GetBlockedUsers(context.Context, string) ([]entity.BlockedUser, error)
+		Block(context.Context, entity.BlockReqUc) (entity.BlockedUser, error)
+		Unblock(context.Context, entity.BlockReqUc) error
+	}
+
+	// UserProfileRepo profil publik, search & setting privacy user
+	UserProfileRepo interface {
+		SearchUsers(context.Context, uuid.UUID, string, int) ([]entity.UserSearchResult, error)
+		GetProfile(context.Context, string) (entity.PublicProfile, error)
+		GetPrivacySettings(context.Context, uuid.UUID) (entity.PrivacySettings, error)
+		GetPrivacySettingsByUserIds(context.Context, []uuid.UUID) (map[uuid.UUID]entity.PrivacySettings, error)
+		SetPrivacySettings(context.Context, uuid.UUID, entity.PrivacySettings) error
+		SetPrivacyExceptions(context.Context, uuid.UUID, entity.PrivacySetting, []entity.PrivacyException) error
+		SetLastSeen(context.Context, uuid.UUID, time.Time) error
+		GetLastSeen(context.Context, []uuid.UUID) (map[uuid.UUID]time.Time, error)
+		FindUsersByEmailHashes(context.Context, uuid.UUID, []string) ([]entity.ContactDiscoveryMatch, error)
+		GetMutualGroups(context.Context, uuid.UUID, uuid.UUID) ([]entity.MutualGroup, error)
+		GetMutualContacts(context.Context, uuid.UUID, uuid.UUID) ([]entity.UserSearchResult, error)
+		UpdateProfile(context.Context, uuid.UUID, entity.UpdateProfileReqUc) error
+		SetAvatar(context.Context, uuid.UUID, entity.Avatar, string) error
+		RemoveAvatar(context.Context, uuid.UUID) error
+		GetAvatar(context.Context, string) (entity.Avatar, error)
+	}
+
+	// UserProfile UseCase search user, profil publik, group & kontak yang sama, ubah profil & avatar, status user, setting privacy user
+	UserProfile interface {
+		SearchUsers(context.Context, entity.UserSearchReqUc) ([]entity.UserSearchResult, error)
+		GetProfile(context.Context, entity.PublicProfileReqUc) (entity.PublicProfile, error)
+		GetMutual(context.Context, entity.PublicProfileReqUc) (entity.Mutual, error)
+		GetPrivacySettings(context.Context, string) (entity.PrivacySettings, error)
+		UpdatePrivacySettings(context.Context, entity.PrivacySettingsReqUc) (entity.PrivacySettings, error)
+		UpdateProfile(context.Context, entity.UpdateProfileReqUc) (entity.PublicProfile, error)
+		UploadAvatar(context.Context, entity.AvatarUploadReqUc) (entity.PublicProfile, error)
+		AvatarMaxSize() int64
+		RemoveAvatar(context.Context, string) (entity.PublicProfile, error)
+		GetAvatar(context.Context, string) (entity.Avatar, error)
+		GetStatus(context.Context, string) (entity.UserStatus, error)
+		SetStatus(context.Context, entity.UserStatusReqUc) (entity.UserStatus, error)
+		ClearStatus(context.Context, string) error
+	}
+
+	// FriendRequestRepo request pertemanan
+	FriendRequestRepo interface {
+		CreateFriendRequest(context.Context, uuid.UUID, uuid.UUID) (entity.FriendRequest, error)
+		GetFriendRequest(context.Context, uuid.UUID) (entity.FriendRequest, error)
+		GetPendingFriendRequest(context.Context, uuid.UUID, uuid.UUID) (entity.FriendRequest, error)
+		GetPendingFriendRequests(context.Context, uuid.UUID) ([]entity.FriendRequest, error)
+		SetFriendRequestStatus(context.Context, uuid.UUID, entity.FriendRequestStatus) error
+		AcceptFriendRequest(context.Context, entity.FriendRequest) error
 	}
 
 	//	PubSubRedis
@@ -97,6 +203,84 @@ type (
 		UserIsOnline(string) bool
 		SetUserServerLocation(string) error
 		GetUserServerLocation(string) (string, error)
+		AddUserSessionServer(string) error
+		RemoveUserSessionServer(string) (bool, error)
+		RefreshUserSessionServer(string) error
+		GetUserSessionServers(string) ([]string, error)
+		GetUsersPresence([]string) ([]entity.UserPresence, error)
+		SetUserStatus(string, entity.UserStatus) error
+		GetUserStatus(string) (entity.UserStatus, error)
+		ClearUserStatus(string) error
+		PopExpiredUserStatuses(time.Time) ([]string, error)
+		TouchUserActivity(string) (bool, error)
+		PopIdleUsers(time.Time) ([]string, error)
+		RemoveUserActivity(string) error
+		GetUserSuspended(string) (bool, bool, error)
+		SetUserSuspended(string, bool, time.Duration) error
+	}
+
+	// GroupRedisRepo cache member group untuk fanout pesan group
+	GroupRedisRepo interface {
+		GetGroupRecipients(string) ([]entity.GroupRecipient, error)
+		SetGroupRecipients(string, []entity.GroupRecipient) error
+		InvalidateGroupRecipients(string) error
+	}
+
+	// ChannelRedisRepo set chat-server yang punya subscriber channel yang online
+	ChannelRedisRepo interface {
+		AddChannelServer(string) error
+		RefreshChannelServers([]string) error
+		RemoveChannelServer(string) error
+		GetChannelServers(string) ([]string, error)
+	}
+
+	// ChannelRepo channel broadcast
+	ChannelRepo interface {
+		CreateChannel(context.Context, entity.Channel) (entity.Channel, error)
+		GetChannelById(context.Context, uuid.UUID) (entity.Channel, error)
+		GetMemberRole(context.Context, uuid.UUID, uuid.UUID) (entity.ChannelRole, error)
+		Subscribe(context.Context, uuid.UUID, uuid.UUID) error
+		Unsubscribe(context.Context, uuid.UUID, uuid.UUID) error
+		SetMemberRole(context.Context, uuid.UUID, uuid.UUID, entity.ChannelRole) error
+		GetUserChannels(context.Context, uuid.UUID) ([]entity.Channel, error)
+		GetSubscribedChannelIds(context.Context, uuid.UUID) ([]uuid.UUID, error)
+		InsertMessage(context.Context, entity.ChannelMessage) (entity.ChannelMessage, error)
+		GetMessagesAfter(context.Context, uuid.UUID, uint64, int) (entity.ChannelMessages, error)
+	}
+
+	// ConversationSettingRepo setting mute & archive percakapan per user
+	ConversationSettingRepo interface {
+		SetMuted(context.Context, uuid.UUID, entity.ConversationType, uuid.UUID, bool, *time.Time) error
+		SetArchived(context.Context, uuid.UUID, entity.ConversationType, uuid.UUID, bool) error
+		GetSettings(context.Context, uuid.UUID) ([]entity.ConversationSetting, error)
+		GetMutedUsers(context.Context, entity.ConversationType, uuid.UUID) ([]uuid.UUID, error)
+		IsMuted(context.Context, uuid.UUID, entity.ConversationType, uuid.UUID) (bool, error)
+	}
+
+	// ConversationSetting UseCase mute & archive percakapan
+	ConversationSetting interface {
+		GetSettings(context.Context, string) ([]entity.ConversationSetting, error)
+		Mute(context.Context, entity.ConversationSettingReqUc) (entity.ConversationSetting, error)
+		Unmute(context.Context, entity.ConversationSettingReqUc) (entity.ConversationSetting, error)
+		Archive(context.Context, entity.ConversationSettingReqUc) (entity.ConversationSetting, error)
+		Unarchive(context.Context, entity.ConversationSettingReqUc) (entity.ConversationSetting, error)
+	}
+
+	// Channel UseCase channel broadcast
+	Channel interface {
+		CreateChannel(context.Context, entity.CreateChannelReqUc) (entity.Channel, error)
+		GetUserChannels(context.Context, string) ([]entity.Channel, error)
+		Subscribe(context.Context, entity.ChannelReqUc) error
+		Unsubscribe(context.Context, entity.ChannelReqUc) error
+		ChangeMemberRole(context.Context, entity.ChangeChannelRoleReqUc) error
+		GetMessages(context.Context, entity.ChannelMessagesReqUc) (entity.ChannelMessages, error)
+	}
+
+	// DraftRepo menyimpan draft pesan user di redis
+	DraftRepo interface {
+		SaveDraft(context.Context, entity.Draft) error
+		DeleteDraft(context.Context, uuid.UUID, entity.ConversationType, uuid.UUID) (bool, error)
+		GetDrafts(context.Context, uuid.UUID) ([]entity.Draft, error)
 	}
 
 	//	 PrivateChatRepo
@@ -104,6 +288,10 @@ type (
 		InsertPrivateChat(entity.InsertPrivateChatRequest) (entity.PrivateChatMessage, error)
 		GetPrivateChatByUser(entity.GetPrivateChatQueryByUserRequest) (entity.PrivateChatUsers, error)
 		GetPrivateChatBySenderAndReceiver(entity.GetPCQueryBySdrAndRcvrRequest) (entity.PrivateChats, error)
+		CountPrivateChatBySenderAndReceiver(entity.GetPCQueryBySdrAndRcvrRequest) (int64, error)
+		GetPrivateChatBySenderAndReceiverAfter(entity.GetPCQueryBySdrAndRcvrRequest, uint64, int) (entity.PrivateChats, error)
+		GetPrivateChatById(uint64) (entity.PrivateChatMessage, error)
+		DeletePrivateChat(uint64) error
 	}
 
 	//Message  UseCase untuk bussines logic Message
@@ -111,6 +299,8 @@ type (
 		GetMessageByUserLogin(context.Context, entity.GetPrivateChatByUserRequest) (entity.PrivateChatUsers, error)
 		GetMessagesByRecipient(context.Context, entity.GetPCBySdrAndRcvrRequest) (entity.PrivateChats, error)
 		GetMessagesByGroupChat(context.Context, entity.GroupChatMsgRequest) (entity.GroupChatMessages, error)
+		ExportMessages(context.Context, entity.ExportMessagesRequest, io.Writer) (entity.ExportJob, error)
+		GetExportJob(context.Context, entity.GetExportJobRequest) (entity.ExportJob, []byte, error)
 	}
 
 	// Repository for group
@@ -118,20 +308,101 @@ type (
 		CreateGroup(context.Context, entity.CreateGroupRequest) (entity.Group, error)
 		AddNewGroupMember(context.Context, entity.AddNewGroupMemberReq) (entity.Group, error)
 		RemoveMember(context.Context, entity.RemoveGroupMemberReq) (entity.Group, error)
-		GetGroupMembers(uuid.UUID, uuid.UUID) (entity.Group, error)
+		GetGroupMembers(uuid.UUID, uuid.UUID, int) ([]entity.GroupMemberDetail, error)
+		CountGroupMembers(uuid.UUID) (int64, error)
 		GetGroupByName(string, uuid.UUID) (entity.Group, error)
+		GetGroupById(uuid.UUID, uuid.UUID) (entity.Group, error)
+		GetUserGroups(uuid.UUID) ([]entity.Group, error)
+		GetMemberRole(uuid.UUID, uuid.UUID) (entity.GroupRole, error)
+		SetMemberRole(uuid.UUID, uuid.UUID, entity.GroupRole) error
+		RenameGroup(uuid.UUID, string) (entity.Group, error)
+		GetGroupAdmins(uuid.UUID) ([]uuid.UUID, error)
+		UpdateGroup(entity.Group) (entity.Group, error)
+		LeaveGroup(uuid.UUID, uuid.UUID) error
+		TransferOwnership(uuid.UUID, uuid.UUID, uuid.UUID) error
+		DeleteGroup(uuid.UUID) error
+		GetGroupRecipients(uuid.UUID) ([]entity.GroupRecipient, error)
 	}
 
 	// UseCase Group
 	Group interface {
 		CreateGroup(context.Context, entity.CreateGroupReqUc) (entity.Group, error)
+		GetUserGroups(context.Context, string) ([]entity.Group, error)
 		AddNewGroupMember(context.Context, entity.AddNewGroupMemberReqUc) (entity.Group, error)
 		RemoveGroupMember(context.Context, entity.RemoveGroupMemberReqUc) (entity.Group, error)
+		ChangeMemberRole(context.Context, entity.ChangeGroupRoleReqUc) (entity.GroupMember, error)
+		RenameGroup(context.Context, entity.RenameGroupReqUc) (entity.Group, error)
+		UpdateGroup(context.Context, entity.UpdateGroupReqUc) (entity.Group, error)
+		LeaveGroup(context.Context, entity.GroupReqUc) error
+		TransferOwnership(context.Context, entity.TransferOwnershipReqUc) (entity.GroupMember, error)
+		DeleteGroup(context.Context, entity.GroupReqUc) error
+		PinMessage(context.Context, entity.GroupMessageActionReqUc, bool) error
+		DeleteMessage(context.Context, entity.GroupMessageActionReqUc) error
+		CreateInvite(context.Context, entity.CreateGroupInviteReqUc) (entity.GroupInvite, error)
+		GetInvites(context.Context, entity.GroupInviteReqUc) ([]entity.GroupInvite, error)
+		RevokeInvite(context.Context, entity.GroupInviteReqUc) error
+		JoinGroup(context.Context, entity.JoinGroupReqUc) (entity.JoinGroupResult, error)
+		GetJoinRequests(context.Context, entity.GroupInviteReqUc) ([]entity.GroupJoinRequest, error)
+		DecideJoinRequest(context.Context, entity.DecideJoinRequestReqUc) (entity.GroupJoinRequest, error)
+		GetGroupMembers(context.Context, entity.GroupMembersReqUc) (entity.GroupMembers, error)
+	}
+
+	// Repository GroupInvite
+	GroupInviteRepo interface {
+		CreateInvite(context.Context, entity.GroupInvite) (entity.GroupInvite, error)
+		GetInviteByToken(context.Context, string) (entity.GroupInvite, error)
+		GetActiveInvites(context.Context, uuid.UUID) ([]entity.GroupInvite, error)
+		RevokeInvite(context.Context, uuid.UUID, uuid.UUID) error
+		RedeemInvite(context.Context, string, uuid.UUID, int) (entity.Group, error)
+	}
+
+	// Repository GroupJoinRequest
+	GroupJoinRequestRepo interface {
+		CreateJoinRequest(context.Context, entity.GroupJoinRequest) (entity.GroupJoinRequest, error)
+		GetJoinRequest(context.Context, uuid.UUID, uuid.UUID) (entity.GroupJoinRequest, error)
+		GetPendingJoinRequests(context.Context, uuid.UUID) ([]entity.GroupJoinRequest, error)
+		SetJoinRequestStatus(context.Context, uuid.UUID, entity.GroupJoinRequestStatus, uuid.UUID) error
+		ApproveJoinRequest(context.Context, entity.GroupJoinRequest, uuid.UUID, int) error
 	}
 
 	// Repository GroupChat
 	GroupChatRepo interface {
 		GetMessagesByGroupId(uuid.UUID) (entity.GroupChatMessages, error)
 		InsertNewChat(entity.GroupChatMessage) (entity.GroupChatMessage, error)
+		CountMessagesByGroupId(uuid.UUID) (int64, error)
+		GetMessagesByGroupIdAfter(uuid.UUID, uint64, int) (entity.GroupChatMessages, error)
+		GetMessageById(uuid.UUID, uint64) (entity.GroupChatMessage, error)
+		SetPinned(uuid.UUID, uint64, bool) error
+		DeleteMessage(uuid.UUID, uint64) error
+	}
+
+	// ContentFilter pipeline filter pesan private & group chat sebelum disimpan & dikirim
+	ContentFilter interface {
+		Run(content string, links ...string) contentfilter.Result
+	}
+
+	// ReportRepo repository report pesan / user
+	ReportRepo interface {
+		CreateReport(context.Context, entity.Report) (entity.Report, error)
+		GetReports(context.Context, entity.ReportStatus) ([]entity.Report, error)
+		ResolveReport(context.Context, uuid.UUID, entity.ReportStatus, uuid.UUID) (entity.Report, error)
+	}
+
+	// Moderation usecase report & moderasi oleh admin
+	Moderation interface {
+		CreateReport(context.Context, entity.CreateReportReqUc) (entity.Report, error)
+		GetReports(context.Context, entity.GetReportsReqUc) ([]entity.Report, error)
+		ResolveReport(context.Context, entity.ResolveReportReqUc) (entity.Report, error)
+		HideMessage(context.Context, entity.HideMessageReqUc) error
+		SuspendUser(context.Context, entity.SuspendUserReqUc) error
+	}
+
+	// ExportRepo menyimpan job export percakapan di redis
+	ExportRepo interface {
+		CreateExportJob(context.Context, entity.ExportJob) error
+		SetExportJobDone(context.Context, uuid.UUID, []byte) error
+		SetExportJobFailed(context.Context, uuid.UUID) error
+		GetExportJob(context.Context, uuid.UUID) (entity.ExportJob, error)
+		GetExportJobData(context.Context, uuid.UUID) ([]byte, error)
 	}
 )
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/internal/util/gopool"
 )
 
 type MessageuseCase struct {
@@ -11,14 +12,23 @@ type MessageuseCase struct {
 	userPgRepo UserRepo
 	gcRepo     GroupChatRepo
 	gpRepo     GroupRepo
+	exportRepo ExportRepo
+	draftRepo  DraftRepo
+	exportPool *gopool.Pool
+	presence   PresenceFanout
 }
 
-func NewMessageuseCase(pcRepo PrivateChatRepo, upg UserRepo, gcRepo GroupChatRepo, gpRepo GroupRepo) *MessageuseCase {
+func NewMessageuseCase(pcRepo PrivateChatRepo, upg UserRepo, gcRepo GroupChatRepo, gpRepo GroupRepo,
+	exportRepo ExportRepo, draftRepo DraftRepo, exportPool *gopool.Pool, presence PresenceFanout) *MessageuseCase {
 	return &MessageuseCase{
 		pcRepo:     pcRepo,
 		userPgRepo: upg,
 		gcRepo:     gcRepo,
 		gpRepo:     gpRepo,
+		exportRepo: exportRepo,
+		draftRepo:  draftRepo,
+		exportPool: exportPool,
+		presence:   presence,
 	}
 }
 
@@ -36,6 +46,29 @@ func (uc *MessageuseCase) GetMessageByUserLogin(ctx context.Context, e entity.Ge
 		return entity.PrivateChatUsers{}, fmt.Errorf("MessageuseCase - GetMessageByUserLogin - uc.pcRepo.GetPrivateChatByUser: %w", err)
 	}
 
+	drafts, err := uc.draftRepo.GetDrafts(ctx, user.Id)
+	if err != nil {
+		return entity.PrivateChatUsers{}, fmt.Errorf("MessageuseCase - GetMessageByUserLogin - uc.draftRepo.GetDrafts: %w", err)
+	}
+	// isi nama percakapan dari setiap draft, draft dari group yg user sudah bukan member dilewati
+	for _, draft := range drafts {
+		switch draft.ConversationType {
+		case entity.ConversationTypePrivate:
+			friend, err := uc.userPgRepo.GetUserById(draft.ConversationId)
+			if err != nil {
+				continue
+			}
+			draft.ConversationName = friend.Username
+		case entity.ConversationTypeGroup:
+			group, err := uc.gpRepo.GetGroupById(draft.ConversationId, user.Id)
+			if err != nil {
+				continue
+			}
+			draft.ConversationName = group.Name
+		}
+		pc.Drafts = append(pc.Drafts, draft)
+	}
+
 	return pc, nil
 }
 
@@ -58,6 +91,8 @@ func (uc *MessageuseCase) GetMessagesByRecipient(ctx context.Context, e entity.G
 	if err != nil {
 		return entity.PrivateChats{}, err
 	}
+	// membaca pesan termasuk aktivitas user
+	uc.presence.MarkUserActive(sender.Id.String(), sender.Username)
 
 	return pcs, nil
 }
@@ -67,12 +102,14 @@ func (uc *MessageuseCase) GetMessagesByGroupChat(ctx context.Context, e entity.G
 	if err != nil {
 		return entity.GroupChatMessages{}, fmt.Errorf("MessageuseCase - GetMessagesByGroupChat - uc.userPgRepo.GetUserByUsername: %w", err)
 	}
-	group, err := uc.gpRepo.GetGroupByName(e.GroupName, user.Id)
+	group, err := findGroup(uc.gpRepo, e.GroupId, e.GroupName, user.Id)
 	if err != nil {
-		return entity.GroupChatMessages{}, fmt.Errorf("MessageuseCase - GetMessagesByGroupChat - uc.gpRepo.GetGroupByName: %w", err)
+		return entity.GroupChatMessages{}, fmt.Errorf("MessageuseCase - GetMessagesByGroupChat - findGroup: %w", err)
 	}
 
 	gcMessages, err := uc.gcRepo.GetMessagesByGroupId(group.Id)
+	// membaca pesan termasuk aktivitas user
+	uc.presence.MarkUserActive(user.Id.String(), user.Username)
 
 	return gcMessages, nil
 }
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/internal/util/markdown"
+)
+
+var (
+	InvalidMessageFormatErr = errors.New("message format must be plain or markdown")
+)
+
+// formatMessage validasi format pesan dari client. Pesan markdown di parsing menjadi plain text + entities
+// sehingga yang disimpan & dikirim ke penerima hanya subset markdown yang aman.
+func formatMessage(format entity.MessageFormat, content string) (entity.MessageFormat, string, []entity.MessageEntity, error) {
+	switch format {
+	case "", entity.MessageFormatPlain:
+		return entity.MessageFormatPlain, content, nil, nil
+	case entity.MessageFormatMarkdown:
+		plain, entities, err := markdown.Parse(content)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("formatMessage - markdown.Parse: %w", err)
+		}
+		return entity.MessageFormatMarkdown, plain, entities, nil
+	}
+	return "", "", nil, InvalidMessageFormatErr
+}
+
+// entityLinks url semua entity text_link, dijalankan ke content filter bersama content pesan
+func entityLinks(entities []entity.MessageEntity) []string {
+	var links []string
+	for _, e := range entities {
+		if e.Type == entity.MessageEntityTextLink {
+			links = append(links, e.Url)
+		}
+	}
+	return links
+}
@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"strings"
+)
+
+var (
+	NotAdminErr             = errors.New("only admin can access this resource")
+	InvalidReportErr        = errors.New("report must have a reason and either reported_username or a message")
+	NotParticipantErr       = errors.New("you are not a participant of the reported message")
+	InvalidReportStatusErr  = errors.New("status must be resolved or dismissed")
+	InvalidConversationErr  = errors.New("conversation_type must be private or group")
+	CannotReportYourselfErr = errors.New("you can not report yourself")
+)
+
+// ModerationUseCase bussines logic report pesan/user & moderasi oleh admin
+type ModerationUseCase struct {
+	reportRepo  ReportRepo
+	userRepo    UserRepo
+	pcRepo      PrivateChatRepo
+	gcRepo      GroupChatRepo
+	gpRepo      GroupRepo
+	sessionRepo SessionRepo
+	pubSub      PubSubRedis
+	userRdsRepo UserRedisRepo
+}
+
+func NewModerationUseCase(reportRepo ReportRepo, userRepo UserRepo, pcRepo PrivateChatRepo, gcRepo GroupChatRepo,
+	gpRepo GroupRepo, sessionRepo SessionRepo, pubSub PubSubRedis, userRdsRepo UserRedisRepo) *ModerationUseCase {
+	return &ModerationUseCase{
+		reportRepo:  reportRepo,
+		userRepo:    userRepo,
+		pcRepo:      pcRepo,
+		gcRepo:      gcRepo,
+		gpRepo:      gpRepo,
+		sessionRepo: sessionRepo,
+		pubSub:      pubSub,
+		userRdsRepo: userRdsRepo,
+	}
+}
+
+// CreateReport user melaporkan pesan atau user lain
+func (uc *ModerationUseCase) CreateReport(ctx context.Context, e entity.CreateReportReqUc) (entity.Report, error) {
+	if strings.TrimSpace(e.Reason) == "" {
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport: %w", InvalidReportErr)
+	}
+	reporter, err := uc.userRepo.GetUserByUsername(e.ReporterUsername)
+	if err != nil {
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport - uc.userRepo.GetUserByUsername: %w", err)
+	}
+
+	report := entity.Report{
+		ReporterId: reporter.Id,
+		Reason:     e.Reason,
+	}
+
+	switch e.ConversationType {
+	case entity.ConversationTypePrivate:
+		// hanya pengirim / penerima pesan yang boleh melaporkan pesan private chat
+		msg, err := uc.pcRepo.GetPrivateChatById(e.MessageId)
+		if err != nil {
+			return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport - uc.pcRepo.GetPrivateChatById: %w", err)
+		}
+		if msg.MessageFrom != reporter.Id && msg.MessageTo != reporter.Id {
+			return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport: %w", NotParticipantErr)
+		}
+		report.ConversationType = entity.ConversationTypePrivate
+		report.MessageId = msg.MessageId
+		report.ReportedUserId = msg.MessageFrom
+
+	case entity.ConversationTypeGroup:
+		// hanya member group yang boleh melaporkan pesan group chat
+		group, err := findGroup(uc.gpRepo, e.GroupId, e.GroupName, reporter.Id)
+		if err != nil {
+			return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport - findGroup: %w", err)
+		}
+		msg, err := uc.gcRepo.GetMessageById(group.Id, e.MessageId)
+		if err != nil {
+			return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport - uc.gcRepo.GetMessageById: %w", err)
+		}
+		report.ConversationType = entity.ConversationTypeGroup
+		report.MessageId = msg.MessageId
+		report.GroupId = group.Id
+		report.ReportedUserId = msg.UserId
+
+	case "":
+		if e.ReportedUsername == "" {
+			return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport: %w", InvalidReportErr)
+		}
+		reported, err := uc.userRepo.GetUserByUsername(e.ReportedUsername)
+		if err != nil {
+			return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport - uc.userRepo.GetUserByUsername: %w", err)
+		}
+		report.ReportedUserId = reported.Id
+
+	default:
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport: %w", InvalidConversationErr)
+	}
+
+	if report.ReportedUserId == reporter.Id {
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport: %w", CannotReportYourselfErr)
+	}
+
+	report, err = uc.reportRepo.CreateReport(ctx, report)
+	if err != nil {
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - CreateReport - uc.reportRepo.CreateReport: %w", err)
+	}
+	return report, nil
+}
+
+// GetReports admin melihat daftar report, default status open
+func (uc *ModerationUseCase) GetReports(ctx context.Context, e entity.GetReportsReqUc) ([]entity.Report, error) {
+	if _, err := uc.getAdmin(e.AdminUsername); err != nil {
+		return nil, fmt.Errorf("ModerationUseCase - GetReports - uc.getAdmin: %w", err)
+	}
+	if e.Status == "" {
+		e.Status = entity.ReportStatusOpen
+	}
+
+	reports, err := uc.reportRepo.GetReports(ctx, e.Status)
+	if err != nil {
+		return nil, fmt.Errorf("ModerationUseCase - GetReports - uc.reportRepo.GetReports: %w", err)
+	}
+	return reports, nil
+}
+
+// ResolveReport admin menutup report dengan status resolved / dismissed
+func (uc *ModerationUseCase) ResolveReport(ctx context.Context, e entity.ResolveReportReqUc) (entity.Report, error) {
+	admin, err := uc.getAdmin(e.AdminUsername)
+	if err != nil {
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - ResolveReport - uc.getAdmin: %w", err)
+	}
+	if e.Status != entity.ReportStatusResolved && e.Status != entity.ReportStatusDismissed {
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - ResolveReport: %w", InvalidReportStatusErr)
+	}
+
+	report, err := uc.reportRepo.ResolveReport(ctx, e.ReportId, e.Status, admin.Id)
+	if err != nil {
+		return entity.Report{}, fmt.Errorf("ModerationUseCase - ResolveReport - uc.reportRepo.ResolveReport: %w", err)
+	}
+	return report, nil
+}
+
+// HideMessage admin menyembunyikan pesan (soft delete), pesan tidak lagi muncul di history chat
+func (uc *ModerationUseCase) HideMessage(ctx context.Context, e entity.HideMessageReqUc) error {
+	if _, err := uc.getAdmin(e.AdminUsername); err != nil {
+		return fmt.Errorf("ModerationUseCase - HideMessage - uc.getAdmin: %w", err)
+	}
+
+	switch e.ConversationType {
+	case entity.ConversationTypePrivate:
+		if err := uc.pcRepo.DeletePrivateChat(e.MessageId); err != nil {
+			return fmt.Errorf("ModerationUseCase - HideMessage - uc.pcRepo.DeletePrivateChat: %w", err)
+		}
+	case entity.ConversationTypeGroup:
+		if err := uc.gcRepo.DeleteMessage(e.GroupId, e.MessageId); err != nil {
+			return fmt.Errorf("ModerationUseCase - HideMessage - uc.gcRepo.DeleteMessage: %w", err)
+		}
+	default:
+		return fmt.Errorf("ModerationUseCase - HideMessage: %w", InvalidConversationErr)
+	}
+	return nil
+}
+
+// SuspendUser admin suspend / unsuspend akun user.
+// Ketika di suspend semua refresh token user dihapus & semua koneksi websocket user langsung ditutup
+// di chat-server manapun user terhubung
+func (uc *ModerationUseCase) SuspendUser(ctx context.Context, e entity.SuspendUserReqUc) error {
+	if _, err := uc.getAdmin(e.AdminUsername); err != nil {
+		return fmt.Errorf("ModerationUseCase - SuspendUser - uc.getAdmin: %w", err)
+	}
+	user, err := uc.userRepo.GetUserByUsername(e.Username)
+	if err != nil {
+		return fmt.Errorf("ModerationUseCase - SuspendUser - uc.userRepo.GetUserByUsername: %w", err)
+	}
+
+	if err = uc.userRepo.SetUserSuspended(ctx, user.Id, e.Suspend); err != nil {
+		return fmt.Errorf("ModerationUseCase - SuspendUser - uc.userRepo.SetUserSuspended: %w", err)
+	}
+	// update cache auth middleware supaya access token user langsung ditolak / diterima lagi.
+	// Jika gagal, cache lama kadaluarsa setelah suspendedCacheTTL & session tetap dihapus di bawah
+	if err = uc.userRdsRepo.SetUserSuspended(user.Username, e.Suspend, suspendedCacheTTL); err != nil {
+		log.Println("ModerationUseCase - SuspendUser - uc.userRdsRepo.SetUserSuspended: ", err)
+	}
+	if !e.Suspend {
+		return nil
+	}
+
+	if err = uc.sessionRepo.DeleteSessionsByUsername(ctx, user.Username); err != nil {
+		return fmt.Errorf("ModerationUseCase - SuspendUser - uc.sessionRepo.DeleteSessionsByUsername: %w", err)
+	}
+
+	reason := "your account has been suspended"
+	if e.Reason != "" {
+		reason += ": " + e.Reason
+	}
+	msgWs := &entity.MessageWs{
+		Type: entity.MessageTypeForceDisconnect,
+		MsgForceDisconnect: entity.MessageForceDisconnect{
+			Reason:            reason,
+			RecipientUsername: user.Username,
+		},
+	}
+
+	// publish ke semua chat-server tempat user punya koneksi websocket
+	servers, err := uc.userRdsRepo.GetUserSessionServers(user.Id.String())
+	if err != nil {
+		return fmt.Errorf("ModerationUseCase - SuspendUser - uc.userRdsRepo.GetUserSessionServers: %w", err)
+	}
+	for _, server := range servers {
+		if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+			log.Println("ModerationUseCase - SuspendUser - uc.pubSub.PublishToChannel: ", err)
+		}
+	}
+	return nil
+}
+
+// getAdmin mendapatkan user yang login, error jika user bukan admin
+func (uc *ModerationUseCase) getAdmin(username string) (entity.GetUser, error) {
+	user, err := uc.userRepo.GetUserByUsername(username)
+	if err != nil {
+		return entity.GetUser{}, err
+	}
+	if !user.IsAdmin {
+		return entity.GetUser{}, NotAdminErr
+	}
+	return user, nil
+}
+
+// isSuspended cek apakah akun user sedang di suspend
+func isSuspended(user entity.GetUser) bool {
+	return user.SuspendedAt != nil
+}
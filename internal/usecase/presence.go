@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"time"
+)
+
+// privacySettings setting privacy user, false jika gagal diambil.
+// Jangan fallback ke setting default karena bisa membocorkan status online user yang membatasi privacynya
+func (c *ChatHub) privacySettings(userId uuid.UUID) (entity.PrivacySettings, bool) {
+	settings, err := c.profileRepo.GetPrivacySettings(context.Background(), userId)
+	if err != nil {
+		log.Println("privacySettings - c.profileRepo.GetPrivacySettings: ", err)
+		return entity.PrivacySettings{}, false
+	}
+	return settings, true
+}
+
+// removeSessionServer mengurangi jumlah koneksi user di chat-server ini setelah 1 koneksi ditutup,
+// lalu menyimpan last seen jika user sudah tidak punya koneksi di chat-server manapun.
+// Penambahan & pengurangan koneksi atomic di redis sehingga urutan dengan koneksi baru user tidak berpengaruh
+func (c *ChatHub) removeSessionServer(user *User) {
+	offline, err := c.usrRedis.RemoveUserSessionServer(user.UserId)
+	if err != nil {
+		log.Println("removeSessionServer - c.usrRedis.RemoveUserSessionServer: ", err)
+		return
+	}
+	if offline {
+		c.storeLastSeen(user.UserId)
+	}
+}
+
+// storeLastSeen menyimpan last seen user yang sudah tidak punya koneksi websocket di chat-server manapun
+func (c *ChatHub) storeLastSeen(userId string) {
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		log.Println("storeLastSeen - uuid.Parse: ", err)
+		return
+	}
+	if err = c.profileRepo.SetLastSeen(context.Background(), id, time.Now()); err != nil {
+		log.Println("storeLastSeen - c.profileRepo.SetLastSeen: ", err)
+	}
+}
+
+const (
+	// statusExpiryInterval interval pengecekan status user yang sudah kadaluarsa
+	statusExpiryInterval = 5 * time.Second
+	// idleCheckInterval interval pengecekan user yang tidak aktif
+	idleCheckInterval = 15 * time.Second
+)
+
+// userStatus status user (away / busy / dnd & teks status), status default jika gagal diambil
+func (c *ChatHub) userStatus(userId string) entity.UserStatus {
+	status, err := c.usrRedis.GetUserStatus(userId)
+	if err != nil {
+		log.Println("userStatus - c.usrRedis.GetUserStatus: ", err)
+	}
+	return status
+}
+
+// FanoutUserStatus mengirim status online & status user terbaru ke semua kontaknya
+func (c *ChatHub) FanoutUserStatus(username string) {
+	user, err := c.userPg.GetUserByUsername(username)
+	if err != nil {
+		log.Println("FanoutUserStatus - c.userPg.GetUserByUsername: ", err)
+		return
+	}
+	c.userOnlineStatusFanout(username, c.usrRedis.UserIsOnline(user.Id.String()))
+}
+
+// FanoutPrivacyChange fanout status online user setelah user mengubah setting privacy.
+// Kontak yang kehilangan izin melihat status online / last seen user menerima status offline tanpa last seen,
+// kontak yang masih boleh melihat menerima status online user seperti biasa
+func (c *ChatHub) FanoutPrivacyChange(username string, before entity.PrivacySettings) {
+	userDb, err := c.userPg.GetUserFriends(context.Background(), username)
+	if err != nil {
+		log.Println("FanoutPrivacyChange - c.userPg.GetUserFriends: ", err)
+		return
+	}
+	settings, ok := c.privacySettings(userDb.Id)
+	if !ok {
+		return
+	}
+	// kontak yang diblokir user sudah selalu melihat user offline
+	blocked, ok := c.blockedContacts(userDb.Id)
+	if !ok {
+		return
+	}
+	online := c.usrRedis.UserIsOnline(userDb.Id.String())
+	for _, uFriend := range userDb.Friends {
+		if blocked[uFriend.Id] {
+			continue
+		}
+		lostOnlineStatus := before.CanSeeOnlineStatus(uFriend.Id, true) && !settings.CanSeeOnlineStatus(uFriend.Id, true)
+		lostLastSeen := before.CanSeeLastSeen(uFriend.Id, true) && !settings.CanSeeLastSeen(uFriend.Id, true)
+		if !lostOnlineStatus && !(lostLastSeen && !online) {
+			continue
+		}
+		c.sendOnlineStatus(uFriend.Id.String(), &entity.MessageWs{
+			Type: entity.MessageTypeOnlineStatusFanOut,
+			MsgOnlineStatusFanout: entity.MessageOnlineStatusFanout{
+				FriendId:          userDb.Id.String(),
+				FriendUsername:    userDb.Username,
+				FriendEmail:       userDb.Email,
+				Online:            false,
+				UserToGetNotified: uFriend.Username,
+			},
+		})
+	}
+	c.userOnlineStatusFanout(username, online)
+}
+
+// RunStatusExpiry fanout status user yang sudah kadaluarsa (kembali ke available) ke semua kontaknya.
+// Dijalankan di setiap chat-server, 1 status kadaluarsa hanya di fanout oleh 1 chat-server
+func (c *ChatHub) RunStatusExpiry() {
+	ticker := time.NewTicker(statusExpiryInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		userIds, err := c.usrRedis.PopExpiredUserStatuses(time.Now())
+		if err != nil {
+			log.Println("RunStatusExpiry - c.usrRedis.PopExpiredUserStatuses: ", err)
+		}
+		for _, userId := range userIds {
+			id, err := uuid.Parse(userId)
+			if err != nil {
+				continue
+			}
+			user, err := c.userPg.GetUserById(id)
+			if err != nil {
+				log.Println("RunStatusExpiry - c.userPg.GetUserById: ", err)
+				continue
+			}
+			c.userOnlineStatusFanout(user.Username, c.usrRedis.UserIsOnline(userId))
+		}
+	}
+}
+
+// isUserActivity message websocket dari client yang dianggap aktivitas user (bukan ping / pong koneksi)
+func isUserActivity(msgType entity.MessageType) bool {
+	switch msgType {
+	case entity.MessageTypePrivateChat, entity.MessageTypePrivateChatBot, entity.MessageTypeGroupChat,
+		entity.MessageTypeGroupChatBot, entity.MessageTypeChannelPost, entity.MessageTypeTyping:
+		return true
+	}
+	return false
+}
+
+// MarkUserActive mencatat aktivitas terakhir user (kirim pesan, typing, membaca pesan).
+// Jika sebelumnya user otomatis away, status user yang kembali aktif di fanout ke semua kontaknya
+func (c *ChatHub) MarkUserActive(userId string, username string) {
+	if c.idleTimeout <= 0 {
+		return
+	}
+	wasIdle, err := c.usrRedis.TouchUserActivity(userId)
+	if err != nil {
+		log.Println("MarkUserActive - c.usrRedis.TouchUserActivity: ", err)
+		return
+	}
+	if wasIdle {
+		c.userOnlineStatusFanout(username, c.usrRedis.UserIsOnline(userId))
+	}
+}
+
+// RunIdleDetection menandai user yang tidak aktif selama idleTimeout menjadi away & fanout ke semua kontaknya.
+// Dijalankan di setiap chat-server, 1 user hanya di fanout oleh 1 chat-server
+func (c *ChatHub) RunIdleDetection() {
+	if c.idleTimeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(idleCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		userIds, err := c.usrRedis.PopIdleUsers(time.Now().Add(-c.idleTimeout))
+		if err != nil {
+			log.Println("RunIdleDetection - c.usrRedis.PopIdleUsers: ", err)
+		}
+		for _, userId := range userIds {
+			if !c.usrRedis.UserIsOnline(userId) {
+				// user sudah offline, tidak perlu di fanout away
+				c.usrRedis.RemoveUserActivity(userId)
+				continue
+			}
+			id, err := uuid.Parse(userId)
+			if err != nil {
+				continue
+			}
+			user, err := c.userPg.GetUserById(id)
+			if err != nil {
+				log.Println("RunIdleDetection - c.userPg.GetUserById: ", err)
+				continue
+			}
+			c.userOnlineStatusFanout(user.Username, true)
+		}
+	}
+}
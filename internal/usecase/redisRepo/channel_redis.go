@@ -0,0 +1,100 @@
+package redisRepo
+
+import (
+	"context"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/pkg/redispkg"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ChannelRedisRepo struct {
+	rds *redispkg.Redis
+}
+
+const (
+	keyChannelServers = "channelServers"
+)
+
+// channelServerTTL chat-server dihapus dari set chat-server channel jika tidak ada heartbeat selama ttl (chat-server crash)
+const channelServerTTL = time.Minute
+
+// addChannelServerScript menandai chat-server aktif di sorted set chat-server channel (score = waktu kadaluarsa).
+// KEYS[1] sorted set chat-server channel, ARGV[1] chat-server, ARGV[2] waktu kadaluarsa, ARGV[3] ttl (detik)
+var addChannelServerScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok == 'set' then redis.call('DEL', KEYS[1]) end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+func NewChannelRedisRepo(rds *redispkg.Redis) *ChannelRedisRepo {
+	return &ChannelRedisRepo{rds}
+}
+
+func (r *ChannelRedisRepo) getKeyChannelServers(channelId string) string {
+	return fmt.Sprintf("%s.%s", keyChannelServers, channelId)
+}
+
+// AddChannelServer menambahkan chat-server ini ke set chat-server yang punya subscriber channel yang online
+func (r *ChannelRedisRepo) AddChannelServer(channelId string) error {
+	keys := []string{r.getKeyChannelServers(channelId)}
+	err := addChannelServerScript.Run(context.Background(), r.rds.Client, keys, entity.ChatServerNameGlobal.ChatServerName,
+		time.Now().Add(channelServerTTL).Unix(), int(channelServerTTL/time.Second)).Err()
+	if err != nil {
+		return fmt.Errorf("ChannelRedisRepo - AddChannelServer - addChannelServerScript.Run: %w", err)
+	}
+	return nil
+}
+
+// RefreshChannelServers heartbeat chat-server ini di set chat-server semua channel yang punya subscriber online di chat-server ini
+func (r *ChannelRedisRepo) RefreshChannelServers(channelIds []string) error {
+	if len(channelIds) == 0 {
+		return nil
+	}
+	expireAt := time.Now().Add(channelServerTTL).Unix()
+	pipe := r.rds.Client.Pipeline()
+	for _, channelId := range channelIds {
+		addChannelServerScript.Eval(context.Background(), pipe, []string{r.getKeyChannelServers(channelId)},
+			entity.ChatServerNameGlobal.ChatServerName, expireAt, int(channelServerTTL/time.Second))
+	}
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		return fmt.Errorf("ChannelRedisRepo - RefreshChannelServers - pipe.Exec: %w", err)
+	}
+	return nil
+}
+
+// RemoveChannelServer menghapus chat-server ini dari set chat-server channel,
+// dipanggil ketika sudah tidak ada subscriber channel yang online di chat-server ini
+func (r *ChannelRedisRepo) RemoveChannelServer(channelId string) error {
+	key := r.getKeyChannelServers(channelId)
+	err := r.rds.Client.ZRem(context.Background(), key, entity.ChatServerNameGlobal.ChatServerName).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "WRONGTYPE") {
+		// key format lama (set)
+		err = r.rds.Client.SRem(context.Background(), key, entity.ChatServerNameGlobal.ChatServerName).Err()
+	}
+	if err != nil {
+		return fmt.Errorf("ChannelRedisRepo - RemoveChannelServer - r.rds.Client.ZRem: %w", err)
+	}
+	return nil
+}
+
+// GetChannelServers mendapatkan semua chat-server yang punya subscriber channel yang online & belum kadaluarsa
+func (r *ChannelRedisRepo) GetChannelServers(channelId string) ([]string, error) {
+	key := r.getKeyChannelServers(channelId)
+	servers, err := r.rds.Client.ZRangeByScore(context.Background(), key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil && strings.HasPrefix(err.Error(), "WRONGTYPE") {
+		// key format lama (set), diganti sorted set ketika subscriber pertama channel terhubung / heartbeat berikutnya
+		servers, err = r.rds.Client.SMembers(context.Background(), key).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ChannelRedisRepo - GetChannelServers - r.rds.Client.ZRangeByScore: %w", err)
+	}
+	return servers, nil
+}
@@ -0,0 +1,67 @@
+package redisRepo
+
+import (
+	"context"
+	"fmt"
+	"github.com/lintangbs/chat-be/pkg/redispkg"
+	"time"
+)
+
+const (
+	keyContactDiscoveryQuota = "contactDiscoveryQuota"
+)
+
+// ContactDiscoveryRedisRepo kuota pencarian user dari hash email per user & per ip
+type ContactDiscoveryRedisRepo struct {
+	rds *redispkg.Redis
+}
+
+func NewContactDiscoveryRedisRepo(rds *redispkg.Redis) *ContactDiscoveryRedisRepo {
+	return &ContactDiscoveryRedisRepo{rds}
+}
+
+func (r *ContactDiscoveryRedisRepo) constructKey(key string, subject string) string {
+	return fmt.Sprintf("%s.%s", key, subject)
+}
+
+// ConsumeQuota memakai n hash dari kuota subject (user / ip) di window sekarang (fixed window).
+// Jika kuota tidak cukup, kuota tidak dipakai & return false. Return sisa kuota & sisa waktu window
+func (r *ContactDiscoveryRedisRepo) ConsumeQuota(subject string, n int64, limit int64, window time.Duration) (bool, int64, time.Duration, error) {
+	key := r.constructKey(keyContactDiscoveryQuota, subject)
+
+	used, err := r.rds.Client.IncrBy(context.Background(), key, n).Result()
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("ContactDiscoveryRedisRepo - ConsumeQuota - r.rds.Client.IncrBy: %w", err)
+	}
+	if used == n {
+		// request pertama di window ini
+		if err = r.rds.Client.Expire(context.Background(), key, window).Err(); err != nil {
+			return false, 0, 0, fmt.Errorf("ContactDiscoveryRedisRepo - ConsumeQuota - r.rds.Client.Expire: %w", err)
+		}
+	}
+	retryAfter, err := r.rds.Client.TTL(context.Background(), key).Result()
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("ContactDiscoveryRedisRepo - ConsumeQuota - r.rds.Client.TTL: %w", err)
+	}
+	if retryAfter < 0 {
+		// key tanpa ttl (expire sebelumnya gagal), set ulang window
+		r.rds.Client.Expire(context.Background(), key, window)
+		retryAfter = window
+	}
+
+	if used > limit {
+		if err = r.ReleaseQuota(subject, n); err != nil {
+			return false, 0, 0, fmt.Errorf("ContactDiscoveryRedisRepo - ConsumeQuota: %w", err)
+		}
+		return false, limit - (used - n), retryAfter, nil
+	}
+	return true, limit - used, retryAfter, nil
+}
+
+// ReleaseQuota mengembalikan n hash ke kuota subject
+func (r *ContactDiscoveryRedisRepo) ReleaseQuota(subject string, n int64) error {
+	if err := r.rds.Client.DecrBy(context.Background(), r.constructKey(keyContactDiscoveryQuota, subject), n).Err(); err != nil {
+		return fmt.Errorf("ContactDiscoveryRedisRepo - ReleaseQuota - r.rds.Client.DecrBy: %w", err)
+	}
+	return nil
+}
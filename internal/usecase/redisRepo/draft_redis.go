@@ -0,0 +1,94 @@
+package redisRepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/pkg/redispkg"
+	"strings"
+	"time"
+)
+
+const (
+	keyUserDraft = "userDraft"
+)
+
+type DraftRedisRepo struct {
+	rds *redispkg.Redis
+}
+
+// draftValue value dari setiap field di hash draft user
+type draftValue struct {
+	Content   string    `json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewDraftRedisRepo(rds *redispkg.Redis) *DraftRedisRepo {
+	return &DraftRedisRepo{rds}
+}
+
+func (r *DraftRedisRepo) constructKey(userId uuid.UUID) string {
+	return fmt.Sprintf("%s.%s", keyUserDraft, userId.String())
+}
+
+// constructField field hash draft: <conversationType>.<conversationId>
+func (r *DraftRedisRepo) constructField(convType entity.ConversationType, convId uuid.UUID) string {
+	return fmt.Sprintf("%s.%s", convType, convId.String())
+}
+
+// SaveDraft menyimpan draft user di hash userDraft.<userId>
+func (r *DraftRedisRepo) SaveDraft(ctx context.Context, d entity.Draft) error {
+	val, err := json.Marshal(draftValue{Content: d.Content, UpdatedAt: d.UpdatedAt})
+	if err != nil {
+		return fmt.Errorf("DraftRedisRepo - SaveDraft - json.Marshal: %w", err)
+	}
+	key := r.constructKey(d.UserId)
+	if err = r.rds.Client.HSet(ctx, key, r.constructField(d.ConversationType, d.ConversationId), val).Err(); err != nil {
+		return fmt.Errorf("DraftRedisRepo - SaveDraft - r.rds.Client.HSet: %w", err)
+	}
+	return nil
+}
+
+// DeleteDraft menghapus draft user di sebuah percakapan, return true jika ada draft yang dihapus
+func (r *DraftRedisRepo) DeleteDraft(ctx context.Context, userId uuid.UUID, convType entity.ConversationType, convId uuid.UUID) (bool, error) {
+	key := r.constructKey(userId)
+	deleted, err := r.rds.Client.HDel(ctx, key, r.constructField(convType, convId)).Result()
+	if err != nil {
+		return false, fmt.Errorf("DraftRedisRepo - DeleteDraft - r.rds.Client.HDel: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// GetDrafts mendapatkan semua draft user, ConversationName belum diisi
+func (r *DraftRedisRepo) GetDrafts(ctx context.Context, userId uuid.UUID) ([]entity.Draft, error) {
+	res, err := r.rds.Client.HGetAll(ctx, r.constructKey(userId)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("DraftRedisRepo - GetDrafts - r.rds.Client.HGetAll: %w", err)
+	}
+
+	var drafts []entity.Draft
+	for field, val := range res {
+		parts := strings.SplitN(field, ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		convId, err := uuid.Parse(parts[1])
+		if err != nil {
+			continue
+		}
+		var v draftValue
+		if err = json.Unmarshal([]byte(val), &v); err != nil {
+			continue
+		}
+		drafts = append(drafts, entity.Draft{
+			UserId:           userId,
+			ConversationType: entity.ConversationType(parts[0]),
+			ConversationId:   convId,
+			Content:          v.Content,
+			UpdatedAt:        v.UpdatedAt,
+		})
+	}
+	return drafts, nil
+}
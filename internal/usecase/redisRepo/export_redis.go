@@ -0,0 +1,114 @@
+package redisRepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/pkg/redispkg"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var (
+	ExportJobNotFoundErr = errors.New("export job not found or already expired")
+)
+
+const (
+	keyExportJob     = "exportJob"
+	keyExportJobData = "exportJobData"
+
+	// exportJobTTL berapa lama hasil export disimpan di redis
+	exportJobTTL = 24 * time.Hour
+)
+
+type ExportRedisRepo struct {
+	rds *redispkg.Redis
+}
+
+func NewExportRedisRepo(rds *redispkg.Redis) *ExportRedisRepo {
+	return &ExportRedisRepo{rds}
+}
+
+func (r *ExportRedisRepo) constructKey(key string, jobId uuid.UUID) string {
+	return fmt.Sprintf("%s.%s", key, jobId.String())
+}
+
+// CreateExportJob menyimpan job export baru dengan status pending
+func (r *ExportRedisRepo) CreateExportJob(ctx context.Context, job entity.ExportJob) error {
+	key := r.constructKey(keyExportJob, job.Id)
+	err := r.rds.Client.HSet(ctx, key,
+		"username", job.Username,
+		"format", string(job.Format),
+		"status", string(job.Status),
+		"file_name", job.FileName,
+		"created_at", job.CreatedAt.Format(time.RFC3339),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("ExportRedisRepo - CreateExportJob - r.rds.Client.HSet: %w", err)
+	}
+	if err = r.rds.Client.Expire(ctx, key, exportJobTTL).Err(); err != nil {
+		return fmt.Errorf("ExportRedisRepo - CreateExportJob - r.rds.Client.Expire: %w", err)
+	}
+	return nil
+}
+
+// SetExportJobDone menyimpan hasil export & mengubah status job menjadi done
+func (r *ExportRedisRepo) SetExportJobDone(ctx context.Context, jobId uuid.UUID, data []byte) error {
+	dataKey := r.constructKey(keyExportJobData, jobId)
+	if err := r.rds.Client.Set(ctx, dataKey, data, exportJobTTL).Err(); err != nil {
+		return fmt.Errorf("ExportRedisRepo - SetExportJobDone - r.rds.Client.Set: %w", err)
+	}
+
+	key := r.constructKey(keyExportJob, jobId)
+	if err := r.rds.Client.HSet(ctx, key, "status", string(entity.ExportJobStatusDone)).Err(); err != nil {
+		return fmt.Errorf("ExportRedisRepo - SetExportJobDone - r.rds.Client.HSet: %w", err)
+	}
+	return nil
+}
+
+// SetExportJobFailed mengubah status job menjadi failed
+func (r *ExportRedisRepo) SetExportJobFailed(ctx context.Context, jobId uuid.UUID) error {
+	key := r.constructKey(keyExportJob, jobId)
+	if err := r.rds.Client.HSet(ctx, key, "status", string(entity.ExportJobStatusFailed)).Err(); err != nil {
+		return fmt.Errorf("ExportRedisRepo - SetExportJobFailed - r.rds.Client.HSet: %w", err)
+	}
+	return nil
+}
+
+// GetExportJob mendapatkan job export dari redis
+func (r *ExportRedisRepo) GetExportJob(ctx context.Context, jobId uuid.UUID) (entity.ExportJob, error) {
+	key := r.constructKey(keyExportJob, jobId)
+	res, err := r.rds.Client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return entity.ExportJob{}, fmt.Errorf("ExportRedisRepo - GetExportJob - r.rds.Client.HGetAll: %w", err)
+	}
+	if len(res) == 0 {
+		return entity.ExportJob{}, fmt.Errorf("ExportRedisRepo - GetExportJob - r.rds.Client.HGetAll: %w", ExportJobNotFoundErr)
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, res["created_at"])
+	job := entity.ExportJob{
+		Id:        jobId,
+		Username:  res["username"],
+		Format:    entity.ExportFormat(res["format"]),
+		Status:    entity.ExportJobStatus(res["status"]),
+		FileName:  res["file_name"],
+		CreatedAt: createdAt,
+	}
+	return job, nil
+}
+
+// GetExportJobData mendapatkan isi file hasil export dari redis
+func (r *ExportRedisRepo) GetExportJobData(ctx context.Context, jobId uuid.UUID) ([]byte, error) {
+	dataKey := r.constructKey(keyExportJobData, jobId)
+	data, err := r.rds.Client.Get(ctx, dataKey).Bytes()
+	if err == redis.Nil {
+		return nil, fmt.Errorf("ExportRedisRepo - GetExportJobData - r.rds.Client.Get: %w", ExportJobNotFoundErr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ExportRedisRepo - GetExportJobData - r.rds.Client.Get: %w", err)
+	}
+	return data, nil
+}
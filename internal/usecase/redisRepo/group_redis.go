@@ -0,0 +1,81 @@
+package redisRepo
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"github.com/lintangbs/chat-be/pkg/redispkg"
+	"time"
+)
+
+type GroupRedisRepo struct {
+	rds *redispkg.Redis
+}
+
+const (
+	keyGroupMembers = "groupMembers"
+	// groupMembersTTL cache member group tetap dihapus ketika member group berubah,
+	// ttl hanya jaga-jaga jika invalidasi gagal
+	groupMembersTTL = 10 * time.Minute
+)
+
+func NewGroupRedisRepo(rds *redispkg.Redis) *GroupRedisRepo {
+	return &GroupRedisRepo{rds}
+}
+
+func (r *GroupRedisRepo) getKeyGroupMembers(groupId string) string {
+	return fmt.Sprintf("%s.%s", keyGroupMembers, groupId)
+}
+
+// GetGroupRecipients mendapatkan cache member group (hash userId -> username), nil jika cache belum ada
+func (r *GroupRedisRepo) GetGroupRecipients(groupId string) ([]entity.GroupRecipient, error) {
+	key := r.getKeyGroupMembers(groupId)
+	members, err := r.rds.Client.HGetAll(context.Background(), key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("GroupRedisRepo - GetGroupRecipients - r.rds.Client.HGetAll: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	recipients := make([]entity.GroupRecipient, 0, len(members))
+	for userId, username := range members {
+		id, err := uuid.Parse(userId)
+		if err != nil {
+			continue
+		}
+		recipients = append(recipients, entity.GroupRecipient{UserId: id, Username: username})
+	}
+	return recipients, nil
+}
+
+// SetGroupRecipients menyimpan cache member group
+func (r *GroupRedisRepo) SetGroupRecipients(groupId string, recipients []entity.GroupRecipient) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	key := r.getKeyGroupMembers(groupId)
+	members := make(map[string]interface{}, len(recipients))
+	for _, recipient := range recipients {
+		members[recipient.UserId.String()] = recipient.Username
+	}
+
+	pipe := r.rds.Client.TxPipeline()
+	pipe.Del(context.Background(), key)
+	pipe.HSet(context.Background(), key, members)
+	pipe.Expire(context.Background(), key, groupMembersTTL)
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		return fmt.Errorf("GroupRedisRepo - SetGroupRecipients - pipe.Exec: %w", err)
+	}
+	return nil
+}
+
+// InvalidateGroupRecipients menghapus cache member group, dipanggil setiap member group berubah
+func (r *GroupRedisRepo) InvalidateGroupRecipients(groupId string) error {
+	key := r.getKeyGroupMembers(groupId)
+	if err := r.rds.Client.Del(context.Background(), key).Err(); err != nil {
+		return fmt.Errorf("GroupRedisRepo - InvalidateGroupRecipients - r.rds.Client.Del: %w", err)
+	}
+	return nil
+}
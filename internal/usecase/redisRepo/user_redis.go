@@ -2,9 +2,13 @@ package redisRepo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/lintangbs/chat-be/internal/entity"
 	"github.com/lintangbs/chat-be/pkg/redispkg"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,6 +19,19 @@ type UserRedisRepo struct {
 const (
 	keyUserStatus         = "userStatus"
 	keyUserServerLocation = "userServer"
+	// sorted set chat-server tempat user punya koneksi websocket dengan score waktu kadaluarsa (heartbeat pong)
+	keyUserSessionServers = "userSessionServers"
+	// hash jumlah koneksi websocket user per chat-server
+	keyUserSessionCount = "userSessionCount"
+	keyUserCustomStatus = "userCustomStatus"
+	// sorted set user id dengan score waktu kadaluarsa status user
+	keyUserStatusExpiry = "userStatusExpiry"
+	// sorted set user id dengan score waktu aktivitas terakhir user (kirim pesan, typing, membaca pesan)
+	keyUserLastActivity = "userLastActivity"
+	// set user id yang otomatis away karena tidak aktif
+	keyUserIdle = "userIdle"
+	// cache status suspend akun user, dipakai auth middleware
+	keyUserSuspended = "userSuspended"
 )
 
 func NewUserRedisrepo(rds *redispkg.Redis) *UserRedisRepo {
@@ -62,3 +79,307 @@ func (r *UserRedisRepo) GetUserServerLocation(userId string) (string, error) {
 	}
 	return res.Val(), nil
 }
+
+// sessionServerTTL chat-server dianggap tidak punya koneksi user jika tidak ada heartbeat selama ttl
+// (chat-server crash), heartbeat dikirim setiap pong dari client
+const sessionServerTTL = time.Minute
+
+// addSessionServerScript menambah jumlah koneksi user di chat-server & menandai chat-server aktif.
+// KEYS[1] sorted set chat-server, KEYS[2] hash jumlah koneksi, ARGV[1] chat-server, ARGV[2] waktu kadaluarsa, ARGV[3] ttl (detik)
+var addSessionServerScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok == 'set' then redis.call('DEL', KEYS[1]) end
+redis.call('HINCRBY', KEYS[2], ARGV[1], 1)
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+return 1
+`)
+
+// removeSessionServerScript mengurangi jumlah koneksi user di chat-server, chat-server dihapus jika koneksinya habis.
+// Jika user sudah tidak punya koneksi di chat-server manapun aktivitas user dihapus & return 1.
+// KEYS[3] sorted set aktivitas user, KEYS[4] set user idle, ARGV[1] chat-server, ARGV[2] waktu sekarang, ARGV[3] user id
+var removeSessionServerScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok == 'set' then redis.call('DEL', KEYS[1]) end
+local n = redis.call('HINCRBY', KEYS[2], ARGV[1], -1)
+if n <= 0 then
+	redis.call('HDEL', KEYS[2], ARGV[1])
+	redis.call('ZREM', KEYS[1], ARGV[1])
+end
+if redis.call('ZCOUNT', KEYS[1], ARGV[2], '+inf') > 0 then return 0 end
+redis.call('ZREM', KEYS[3], ARGV[3])
+redis.call('SREM', KEYS[4], ARGV[3])
+return 1
+`)
+
+// AddUserSessionServer menambah 1 koneksi websocket user di chat-server ini,
+// 1 user bisa terhubung dari beberapa device di chat-server yang berbeda
+func (r *UserRedisRepo) AddUserSessionServer(userId string) error {
+	keys := []string{r.constructKey(keyUserSessionServers, userId), r.constructKey(keyUserSessionCount, userId)}
+	err := addSessionServerScript.Run(context.Background(), r.rds.Client, keys, entity.ChatServerNameGlobal.ChatServerName,
+		time.Now().Add(sessionServerTTL).Unix(), int(sessionServerTTL/time.Second)).Err()
+	if err != nil {
+		return fmt.Errorf("UserRedisRepo - AddUserSessionServer - addSessionServerScript.Run: %w", err)
+	}
+	return nil
+}
+
+// RemoveUserSessionServer mengurangi 1 koneksi websocket user di chat-server ini, dipanggil setiap koneksi ditutup.
+// Return true jika user sudah tidak punya koneksi di chat-server manapun
+func (r *UserRedisRepo) RemoveUserSessionServer(userId string) (bool, error) {
+	keys := []string{r.constructKey(keyUserSessionServers, userId), r.constructKey(keyUserSessionCount, userId),
+		keyUserLastActivity, keyUserIdle}
+	offline, err := removeSessionServerScript.Run(context.Background(), r.rds.Client, keys, entity.ChatServerNameGlobal.ChatServerName,
+		time.Now().Unix(), userId).Int()
+	if err != nil {
+		return false, fmt.Errorf("UserRedisRepo - RemoveUserSessionServer - removeSessionServerScript.Run: %w", err)
+	}
+	return offline == 1, nil
+}
+
+// RefreshUserSessionServer heartbeat koneksi user di chat-server ini supaya chat-server tidak dianggap kadaluarsa
+func (r *UserRedisRepo) RefreshUserSessionServer(userId string) error {
+	key := r.constructKey(keyUserSessionServers, userId)
+	pipe := r.rds.Client.TxPipeline()
+	pipe.ZAddXX(context.Background(), key, redis.Z{
+		Score:  float64(time.Now().Add(sessionServerTTL).Unix()),
+		Member: entity.ChatServerNameGlobal.ChatServerName,
+	})
+	pipe.Expire(context.Background(), key, sessionServerTTL)
+	pipe.Expire(context.Background(), r.constructKey(keyUserSessionCount, userId), sessionServerTTL)
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		return fmt.Errorf("UserRedisRepo - RefreshUserSessionServer - pipe.Exec: %w", err)
+	}
+	return nil
+}
+
+// GetUserSessionServers mendapatkan semua chat-server tempat user punya koneksi websocket yang belum kadaluarsa
+func (r *UserRedisRepo) GetUserSessionServers(userId string) ([]string, error) {
+	key := r.constructKey(keyUserSessionServers, userId)
+	servers, err := r.rds.Client.ZRangeByScore(context.Background(), key, &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil && strings.HasPrefix(err.Error(), "WRONGTYPE") {
+		// key format lama (set), diganti sorted set ketika user connect / disconnect berikutnya
+		servers, err = r.rds.Client.SMembers(context.Background(), key).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("UserRedisRepo - GetUserSessionServers - r.rds.Client.ZRangeByScore: %w", err)
+	}
+	return servers, nil
+}
+
+// GetUsersPresence mendapatkan status online & lokasi chat-server banyak user dalam 1 round-trip ke redis
+// (MGET status online + HMGET lokasi chat-server di dalam 1 pipeline), urutan hasil sama dengan userIds
+func (r *UserRedisRepo) GetUsersPresence(userIds []string) ([]entity.UserPresence, error) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+	statusKeys := make([]string, len(userIds))
+	for i, userId := range userIds {
+		statusKeys[i] = r.getKeyUserStatus(userId)
+	}
+
+	customStatusKeys := make([]string, len(userIds))
+	for i, userId := range userIds {
+		customStatusKeys[i] = r.constructKey(keyUserCustomStatus, userId)
+	}
+
+	pipe := r.rds.Client.Pipeline()
+	statusCmd := pipe.MGet(context.Background(), statusKeys...)
+	customStatusCmd := pipe.MGet(context.Background(), customStatusKeys...)
+	idleCmds := make([]*redis.BoolCmd, len(userIds))
+	for i, userId := range userIds {
+		idleCmds[i] = pipe.SIsMember(context.Background(), keyUserIdle, userId)
+	}
+	locationCmds := make([]*redis.SliceCmd, len(userIds))
+	for i, userId := range userIds {
+		locationCmds[i] = pipe.HMGet(context.Background(), r.constructKey(keyUserServerLocation, userId), userId)
+	}
+	if _, err := pipe.Exec(context.Background()); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("UserRedisRepo - GetUsersPresence - pipe.Exec: %w", err)
+	}
+
+	statuses := statusCmd.Val()
+	customStatuses := customStatusCmd.Val()
+	presences := make([]entity.UserPresence, len(userIds))
+	for i := range userIds {
+		presences[i].Online = i < len(statuses) && statuses[i] != nil
+		presences[i].Status = entity.DefaultUserStatus()
+		if i < len(customStatuses) && customStatuses[i] != nil {
+			data, _ := customStatuses[i].(string)
+			presences[i].Status = decodeUserStatus(data)
+		}
+		presences[i].Status = presences[i].Status.WithIdle(idleCmds[i].Val())
+		if location := locationCmds[i].Val(); len(location) > 0 && location[0] != nil {
+			presences[i].ServerLocation, _ = location[0].(string)
+		}
+	}
+	return presences, nil
+}
+
+// SetUserStatus menyimpan status user (away / busy / dnd & teks status). Status dengan ExpiresAt otomatis
+// dihapus redis (TTL) & dicatat di sorted set kadaluarsa agar perubahannya bisa di fanout ke kontak user
+func (r *UserRedisRepo) SetUserStatus(userId string, status entity.UserStatus) error {
+	if status.IsDefault() {
+		return r.ClearUserStatus(userId)
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("UserRedisRepo - SetUserStatus - json.Marshal: %w", err)
+	}
+	var ttl time.Duration
+	if status.ExpiresAt != nil {
+		ttl = time.Until(*status.ExpiresAt)
+		if ttl <= 0 {
+			return r.ClearUserStatus(userId)
+		}
+	}
+
+	pipe := r.rds.Client.TxPipeline()
+	pipe.Set(context.Background(), r.constructKey(keyUserCustomStatus, userId), data, ttl)
+	if status.ExpiresAt != nil {
+		pipe.ZAdd(context.Background(), keyUserStatusExpiry, redis.Z{Score: float64(status.ExpiresAt.Unix()), Member: userId})
+	} else {
+		pipe.ZRem(context.Background(), keyUserStatusExpiry, userId)
+	}
+	if _, err = pipe.Exec(context.Background()); err != nil {
+		return fmt.Errorf("UserRedisRepo - SetUserStatus - pipe.Exec: %w", err)
+	}
+	return nil
+}
+
+// GetUserStatus mendapatkan status user, status default jika user belum memilih status / statusnya kadaluarsa.
+// User yang tidak aktif otomatis away
+func (r *UserRedisRepo) GetUserStatus(userId string) (entity.UserStatus, error) {
+	pipe := r.rds.Client.Pipeline()
+	statusCmd := pipe.Get(context.Background(), r.constructKey(keyUserCustomStatus, userId))
+	idleCmd := pipe.SIsMember(context.Background(), keyUserIdle, userId)
+	if _, err := pipe.Exec(context.Background()); err != nil && err != redis.Nil {
+		return entity.DefaultUserStatus(), fmt.Errorf("UserRedisRepo - GetUserStatus - pipe.Exec: %w", err)
+	}
+	status := entity.DefaultUserStatus()
+	if data, err := statusCmd.Result(); err == nil {
+		status = decodeUserStatus(data)
+	}
+	return status.WithIdle(idleCmd.Val()), nil
+}
+
+// ClearUserStatus menghapus status user (kembali ke available)
+func (r *UserRedisRepo) ClearUserStatus(userId string) error {
+	pipe := r.rds.Client.TxPipeline()
+	pipe.Del(context.Background(), r.constructKey(keyUserCustomStatus, userId))
+	pipe.ZRem(context.Background(), keyUserStatusExpiry, userId)
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		return fmt.Errorf("UserRedisRepo - ClearUserStatus - pipe.Exec: %w", err)
+	}
+	return nil
+}
+
+// PopExpiredUserStatuses mengambil & menghapus user id yang statusnya sudah kadaluarsa dari sorted set kadaluarsa.
+// ZREM memastikan 1 user hanya diproses 1 chat-server walaupun semua chat-server memanggil fungsi ini
+func (r *UserRedisRepo) PopExpiredUserStatuses(now time.Time) ([]string, error) {
+	userIds, err := r.rds.Client.ZRangeByScore(context.Background(), keyUserStatusExpiry, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("%d", now.Unix()),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("UserRedisRepo - PopExpiredUserStatuses - r.rds.Client.ZRangeByScore: %w", err)
+	}
+
+	var expired []string
+	for _, userId := range userIds {
+		removed, err := r.rds.Client.ZRem(context.Background(), keyUserStatusExpiry, userId).Result()
+		if err != nil {
+			return expired, fmt.Errorf("UserRedisRepo - PopExpiredUserStatuses - r.rds.Client.ZRem: %w", err)
+		}
+		if removed > 0 {
+			expired = append(expired, userId)
+		}
+	}
+	return expired, nil
+}
+
+// TouchUserActivity mencatat aktivitas terakhir user (bukan ping / pong koneksi websocket),
+// return true jika sebelumnya user otomatis away karena tidak aktif
+func (r *UserRedisRepo) TouchUserActivity(userId string) (bool, error) {
+	pipe := r.rds.Client.TxPipeline()
+	pipe.ZAdd(context.Background(), keyUserLastActivity, redis.Z{Score: float64(time.Now().Unix()), Member: userId})
+	idleCmd := pipe.SRem(context.Background(), keyUserIdle, userId)
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		return false, fmt.Errorf("UserRedisRepo - TouchUserActivity - pipe.Exec: %w", err)
+	}
+	return idleCmd.Val() > 0, nil
+}
+
+// PopIdleUsers mengambil user id yang aktivitas terakhirnya sebelum before lalu menandai user tersebut away.
+// ZREM memastikan 1 user hanya diproses 1 chat-server walaupun semua chat-server memanggil fungsi ini
+func (r *UserRedisRepo) PopIdleUsers(before time.Time) ([]string, error) {
+	userIds, err := r.rds.Client.ZRangeByScore(context.Background(), keyUserLastActivity, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: fmt.Sprintf("%d", before.Unix()),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("UserRedisRepo - PopIdleUsers - r.rds.Client.ZRangeByScore: %w", err)
+	}
+
+	var idle []string
+	for _, userId := range userIds {
+		removed, err := r.rds.Client.ZRem(context.Background(), keyUserLastActivity, userId).Result()
+		if err != nil {
+			return idle, fmt.Errorf("UserRedisRepo - PopIdleUsers - r.rds.Client.ZRem: %w", err)
+		}
+		if removed == 0 {
+			continue
+		}
+		if err = r.rds.Client.SAdd(context.Background(), keyUserIdle, userId).Err(); err != nil {
+			return idle, fmt.Errorf("UserRedisRepo - PopIdleUsers - r.rds.Client.SAdd: %w", err)
+		}
+		idle = append(idle, userId)
+	}
+	return idle, nil
+}
+
+// RemoveUserActivity menghapus aktivitas & status away otomatis user, dipanggil ketika koneksi terakhir user ditutup
+func (r *UserRedisRepo) RemoveUserActivity(userId string) error {
+	pipe := r.rds.Client.TxPipeline()
+	pipe.ZRem(context.Background(), keyUserLastActivity, userId)
+	pipe.SRem(context.Background(), keyUserIdle, userId)
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		return fmt.Errorf("UserRedisRepo - RemoveUserActivity - pipe.Exec: %w", err)
+	}
+	return nil
+}
+
+// GetUserSuspended mendapatkan cache status suspend akun user, found false jika belum ada di cache
+func (r *UserRedisRepo) GetUserSuspended(username string) (suspended bool, found bool, err error) {
+	val, err := r.rds.Client.Get(context.Background(), keyUserSuspended+"."+username).Result()
+	if err == redis.Nil {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("UserRedisRepo - GetUserSuspended - r.rds.Client.Get: %w", err)
+	}
+	return val == "1", true, nil
+}
+
+// SetUserSuspended simpan cache status suspend akun user
+func (r *UserRedisRepo) SetUserSuspended(username string, suspended bool, ttl time.Duration) error {
+	val := "0"
+	if suspended {
+		val = "1"
+	}
+	if err := r.rds.Client.Set(context.Background(), keyUserSuspended+"."+username, val, ttl).Err(); err != nil {
+		return fmt.Errorf("UserRedisRepo - SetUserSuspended - r.rds.Client.Set: %w", err)
+	}
+	return nil
+}
+
+// decodeUserStatus decode status user dari redis, status default jika data rusak / sudah kadaluarsa
+func decodeUserStatus(data string) entity.UserStatus {
+	var status entity.UserStatus
+	if err := json.Unmarshal([]byte(data), &status); err != nil || status.Expired(time.Now()) {
+		return entity.DefaultUserStatus()
+	}
+	return status
+}
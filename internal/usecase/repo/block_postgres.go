@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type BlockRepo struct {
+	db *gorm.DB
+}
+
+type UserBlock struct {
+	BlockerId uuid.UUID
+	BlockedId uuid.UUID
+	CreatedAt time.Time
+}
+
+// blockedUserRow user yang diblokir beserta usernamenya
+type blockedUserRow struct {
+	UserId    uuid.UUID
+	Username  string
+	CreatedAt time.Time
+}
+
+func NewBlockRepo(db *gorm.DB) *BlockRepo {
+	return &BlockRepo{db}
+}
+
+// Block memblokir user, tidak error jika user sudah diblokir sebelumnya
+func (r *BlockRepo) Block(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) error {
+	block := UserBlock{
+		BlockerId: blockerId,
+		BlockedId: blockedId,
+		CreatedAt: time.Now(),
+	}
+	if res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&block); res.Error != nil {
+		return fmt.Errorf("BlockRepo - Block - r.db.Create: %w", res.Error)
+	}
+	return nil
+}
+
+// Unblock membuka blokir user
+func (r *BlockRepo) Unblock(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) error {
+	if res := r.db.Where("blocker_id = ? AND blocked_id = ?", blockerId, blockedId).Delete(&UserBlock{}); res.Error != nil {
+		return fmt.Errorf("BlockRepo - Unblock - r.db.Delete: %w", res.Error)
+	}
+	return nil
+}
+
+// GetBlockedUsers mendapatkan semua user yang diblokir user
+func (r *BlockRepo) GetBlockedUsers(ctx context.Context, blockerId uuid.UUID) ([]entity.BlockedUser, error) {
+	var rows []blockedUserRow
+	res := r.db.Table("user_blocks").
+		Select("user_blocks.blocked_id AS user_id, users.username, user_blocks.created_at").
+		Joins("JOIN users ON users.id = user_blocks.blocked_id").
+		Where("user_blocks.blocker_id = ?", blockerId).
+		Order("user_blocks.created_at DESC").Scan(&rows)
+	if res.Error != nil {
+		return nil, fmt.Errorf("BlockRepo - GetBlockedUsers - r.db.Scan: %w", res.Error)
+	}
+
+	blocked := []entity.BlockedUser{}
+	for _, row := range rows {
+		blocked = append(blocked, entity.BlockedUser{
+			UserId:    row.UserId,
+			Username:  row.Username,
+			BlockedAt: row.CreatedAt,
+		})
+	}
+	return blocked, nil
+}
+
+// IsBlocked apakah blockerId memblokir blockedId
+func (r *BlockRepo) IsBlocked(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (bool, error) {
+	var count int64
+	if res := r.db.Model(&UserBlock{}).Where("blocker_id = ? AND blocked_id = ?", blockerId, blockedId).Count(&count); res.Error != nil {
+		return false, fmt.Errorf("BlockRepo - IsBlocked - r.db.Count: %w", res.Error)
+	}
+	return count > 0, nil
+}
+
+// GetBlockerIds mendapatkan id semua user yang memblokir blockedId
+func (r *BlockRepo) GetBlockerIds(ctx context.Context, blockedId uuid.UUID) ([]uuid.UUID, error) {
+	var blockerIds []uuid.UUID
+	if res := r.db.Model(&UserBlock{}).Where("blocked_id = ?", blockedId).Pluck("blocker_id", &blockerIds); res.Error != nil {
+		return nil, fmt.Errorf("BlockRepo - GetBlockerIds - r.db.Pluck: %w", res.Error)
+	}
+	return blockerIds, nil
+}
@@ -0,0 +1,256 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	NotChannelMemberErr         = errors.New("you are not subscribed to this channel")
+	ChannelAlreadySubscribedErr = errors.New("you are already subscribed to this channel")
+)
+
+type ChannelRepo struct {
+	db *gorm.DB
+}
+
+type Channel struct {
+	gorm.Model
+	Id              uuid.UUID
+	Name            string
+	Description     string
+	OwnerId         uuid.UUID
+	SubscriberCount int64
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type ChannelMember struct {
+	gorm.Model
+	Id        uuid.UUID
+	ChannelId uuid.UUID
+	UserId    uuid.UUID
+	Role      string
+}
+
+type ChannelMessage struct {
+	ChannelId uuid.UUID `gorm:"primaryKey"`
+	MessageId uint64    `gorm:"primaryKey;autoIncrement:false"`
+	UserId    uuid.UUID
+	Content   string `gorm:"type:text"`
+	Format    string
+	Entities  messageEntities `gorm:"type:jsonb"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func NewChannelRepo(db *gorm.DB) *ChannelRepo {
+	return &ChannelRepo{db}
+}
+
+// CreateChannel membuat channel baru, pembuat channel menjadi owner
+func (r *ChannelRepo) CreateChannel(ctx context.Context, e entity.Channel) (entity.Channel, error) {
+	ch := Channel{
+		Id:              uuid.New(),
+		Name:            e.Name,
+		Description:     e.Description,
+		OwnerId:         e.OwnerId,
+		SubscriberCount: 1,
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Create(&ch); res.Error != nil {
+			return res.Error
+		}
+		owner := ChannelMember{Id: uuid.New(), ChannelId: ch.Id, UserId: e.OwnerId, Role: string(entity.ChannelRoleOwner)}
+		return tx.Create(&owner).Error
+	})
+	if err != nil {
+		return entity.Channel{}, fmt.Errorf("ChannelRepo - CreateChannel - r.db.Transaction: %w", err)
+	}
+
+	res := ch.toEntity()
+	res.Role = entity.ChannelRoleOwner
+	return res, nil
+}
+
+// GetChannelById mendapatkan channel by id
+func (r *ChannelRepo) GetChannelById(ctx context.Context, channelId uuid.UUID) (entity.Channel, error) {
+	var ch Channel
+	if res := r.db.Where(&Channel{Id: channelId}).First(&ch); res.Error != nil {
+		return entity.Channel{}, fmt.Errorf("ChannelRepo - GetChannelById - r.db.Where(&Channel{Id: channelId}).First: %w", res.Error)
+	}
+	return ch.toEntity(), nil
+}
+
+// GetMemberRole mendapatkan role user di channel, NotChannelMemberErr jika user tidak subscribe channel
+func (r *ChannelRepo) GetMemberRole(ctx context.Context, channelId uuid.UUID, userId uuid.UUID) (entity.ChannelRole, error) {
+	var member ChannelMember
+	res := r.db.Where(&ChannelMember{ChannelId: channelId, UserId: userId}).First(&member)
+	if res.Error == gorm.ErrRecordNotFound {
+		return "", fmt.Errorf("ChannelRepo - GetMemberRole - r.db.Where(&ChannelMember{}).First: %w", NotChannelMemberErr)
+	}
+	if res.Error != nil {
+		return "", fmt.Errorf("ChannelRepo - GetMemberRole - r.db.Where(&ChannelMember{}).First: %w", res.Error)
+	}
+	return entity.ChannelRole(member.Role), nil
+}
+
+// Subscribe menambahkan user sebagai subscriber channel
+func (r *ChannelRepo) Subscribe(ctx context.Context, channelId uuid.UUID, userId uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if res := tx.Model(&ChannelMember{}).Where(&ChannelMember{ChannelId: channelId, UserId: userId}).Count(&count); res.Error != nil {
+			return res.Error
+		}
+		if count > 0 {
+			return ChannelAlreadySubscribedErr
+		}
+		member := ChannelMember{Id: uuid.New(), ChannelId: channelId, UserId: userId, Role: string(entity.ChannelRoleSubscriber)}
+		if res := tx.Create(&member); res.Error != nil {
+			return res.Error
+		}
+		return tx.Model(&Channel{}).Where("id = ?", channelId).Update("subscriber_count", gorm.Expr("subscriber_count + 1")).Error
+	})
+	if err != nil {
+		return fmt.Errorf("ChannelRepo - Subscribe - r.db.Transaction: %w", err)
+	}
+	return nil
+}
+
+// Unsubscribe menghapus user dari subscriber channel
+func (r *ChannelRepo) Unsubscribe(ctx context.Context, channelId uuid.UUID, userId uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Where("channel_id = ? AND user_id = ?", channelId, userId).Delete(&ChannelMember{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return NotChannelMemberErr
+		}
+		return tx.Model(&Channel{}).Where("id = ?", channelId).Update("subscriber_count", gorm.Expr("subscriber_count - 1")).Error
+	})
+	if err != nil {
+		return fmt.Errorf("ChannelRepo - Unsubscribe - r.db.Transaction: %w", err)
+	}
+	return nil
+}
+
+// SetMemberRole mengubah role subscriber channel
+func (r *ChannelRepo) SetMemberRole(ctx context.Context, channelId uuid.UUID, userId uuid.UUID, role entity.ChannelRole) error {
+	res := r.db.Model(&ChannelMember{}).Where("channel_id = ? AND user_id = ?", channelId, userId).Update("role", string(role))
+	if res.Error != nil {
+		return fmt.Errorf("ChannelRepo - SetMemberRole - r.db.Update: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("ChannelRepo - SetMemberRole - r.db.Update: %w", NotChannelMemberErr)
+	}
+	return nil
+}
+
+// GetUserChannels mendapatkan semua channel yang di subscribe user beserta role user di channel tsb
+func (r *ChannelRepo) GetUserChannels(ctx context.Context, userId uuid.UUID) ([]entity.Channel, error) {
+	type userChannel struct {
+		Id              uuid.UUID
+		Name            string
+		Description     string
+		OwnerId         uuid.UUID
+		SubscriberCount int64
+		Role            string
+		CreatedAt       time.Time
+		UpdatedAt       time.Time
+	}
+	var rows []userChannel
+	if res := r.db.Table("channels").
+		Select("channels.id, channels.name, channels.description, channels.owner_id, channels.subscriber_count, channel_members.role, channels.created_at, channels.updated_at").
+		Joins("JOIN channel_members on channel_members.channel_id=channels.id AND channel_members.deleted_at IS NULL").
+		Where("channel_members.user_id = ? AND channels.deleted_at IS NULL", userId).
+		Order("channels.name").Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("ChannelRepo - GetUserChannels - r.db.Scan: %w", res.Error)
+	}
+
+	channels := []entity.Channel{}
+	for _, row := range rows {
+		channels = append(channels, entity.Channel{
+			Id:              row.Id,
+			Name:            row.Name,
+			Description:     row.Description,
+			OwnerId:         row.OwnerId,
+			SubscriberCount: row.SubscriberCount,
+			Role:            entity.ChannelRole(row.Role),
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+		})
+	}
+	return channels, nil
+}
+
+// GetSubscribedChannelIds mendapatkan id semua channel yang di subscribe user
+func (r *ChannelRepo) GetSubscribedChannelIds(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if res := r.db.Model(&ChannelMember{}).Where("user_id = ?", userId).Pluck("channel_id", &ids); res.Error != nil {
+		return nil, fmt.Errorf("ChannelRepo - GetSubscribedChannelIds - r.db.Pluck: %w", res.Error)
+	}
+	return ids, nil
+}
+
+// InsertMessage menyimpan pesan channel
+func (r *ChannelRepo) InsertMessage(ctx context.Context, e entity.ChannelMessage) (entity.ChannelMessage, error) {
+	msg := ChannelMessage{
+		ChannelId: e.ChannelId,
+		MessageId: e.MessageId,
+		UserId:    e.UserId,
+		Content:   e.Content,
+		Format:    string(messageFormat(string(e.Format))),
+		Entities:  e.Entities,
+	}
+	if res := r.db.Create(&msg); res.Error != nil {
+		return entity.ChannelMessage{}, fmt.Errorf("ChannelRepo - InsertMessage - r.db.Create(&msg): %w", res.Error)
+	}
+	return msg.toEntity(), nil
+}
+
+// GetMessagesAfter mendapatkan maksimal limit pesan channel dengan message_id > afterId,
+// diurutkan dari yang paling lama
+func (r *ChannelRepo) GetMessagesAfter(ctx context.Context, channelId uuid.UUID, afterId uint64, limit int) (entity.ChannelMessages, error) {
+	var msgs []ChannelMessage
+	if res := r.db.Where("channel_id = ? AND message_id > ?", channelId, afterId).
+		Order("message_id").Limit(limit).Find(&msgs); res.Error != nil {
+		return entity.ChannelMessages{}, fmt.Errorf("ChannelRepo - GetMessagesAfter - r.db.Find: %w", res.Error)
+	}
+
+	res := entity.ChannelMessages{Messages: []entity.ChannelMessage{}}
+	for _, msg := range msgs {
+		res.Messages = append(res.Messages, msg.toEntity())
+	}
+	return res, nil
+}
+
+func (ch Channel) toEntity() entity.Channel {
+	return entity.Channel{
+		Id:              ch.Id,
+		Name:            ch.Name,
+		Description:     ch.Description,
+		OwnerId:         ch.OwnerId,
+		SubscriberCount: ch.SubscriberCount,
+		CreatedAt:       ch.CreatedAt,
+		UpdatedAt:       ch.UpdatedAt,
+	}
+}
+
+func (m ChannelMessage) toEntity() entity.ChannelMessage {
+	return entity.ChannelMessage{
+		ChannelId: m.ChannelId,
+		MessageId: m.MessageId,
+		UserId:    m.UserId,
+		Content:   m.Content,
+		Format:    messageFormat(m.Format),
+		Entities:  m.Entities,
+		CreatedAt: m.CreatedAt,
+	}
+}
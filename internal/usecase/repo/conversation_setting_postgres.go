@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type ConversationSettingRepo struct {
+	db *gorm.DB
+}
+
+type ConversationSetting struct {
+	gorm.Model
+	Id               uuid.UUID
+	UserId           uuid.UUID
+	ConversationType string
+	ConversationId   uuid.UUID
+	Muted            bool
+	MutedUntil       *time.Time
+	Archived         bool
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// conversationSettingRow setting percakapan beserta username friend / nama group
+type conversationSettingRow struct {
+	UserId           uuid.UUID
+	ConversationType string
+	ConversationId   uuid.UUID
+	ConversationName string
+	Muted            bool
+	MutedUntil       *time.Time
+	Archived         bool
+	UpdatedAt        time.Time
+}
+
+func NewConversationSettingRepo(db *gorm.DB) *ConversationSettingRepo {
+	return &ConversationSettingRepo{db}
+}
+
+// conversationSettingConflict upsert setting percakapan by (user_id, conversation_type, conversation_id)
+var conversationSettingConflict = []clause.Column{{Name: "user_id"}, {Name: "conversation_type"}, {Name: "conversation_id"}}
+
+// SetMuted mute / unmute percakapan, mutedUntil nil berarti mute tanpa batas waktu
+func (r *ConversationSettingRepo) SetMuted(ctx context.Context, userId uuid.UUID, convType entity.ConversationType, convId uuid.UUID,
+	muted bool, mutedUntil *time.Time) error {
+	setting := ConversationSetting{
+		Id:               uuid.New(),
+		UserId:           userId,
+		ConversationType: string(convType),
+		ConversationId:   convId,
+		Muted:            muted,
+		MutedUntil:       mutedUntil,
+	}
+	res := r.db.Clauses(clause.OnConflict{
+		Columns:   conversationSettingConflict,
+		DoUpdates: clause.AssignmentColumns([]string{"muted", "muted_until", "updated_at"}),
+	}).Create(&setting)
+	if res.Error != nil {
+		return fmt.Errorf("ConversationSettingRepo - SetMuted - r.db.Create: %w", res.Error)
+	}
+	return nil
+}
+
+// SetArchived archive / unarchive percakapan
+func (r *ConversationSettingRepo) SetArchived(ctx context.Context, userId uuid.UUID, convType entity.ConversationType, convId uuid.UUID,
+	archived bool) error {
+	setting := ConversationSetting{
+		Id:               uuid.New(),
+		UserId:           userId,
+		ConversationType: string(convType),
+		ConversationId:   convId,
+		Archived:         archived,
+	}
+	res := r.db.Clauses(clause.OnConflict{
+		Columns:   conversationSettingConflict,
+		DoUpdates: clause.AssignmentColumns([]string{"archived", "updated_at"}),
+	}).Create(&setting)
+	if res.Error != nil {
+		return fmt.Errorf("ConversationSettingRepo - SetArchived - r.db.Create: %w", res.Error)
+	}
+	return nil
+}
+
+// GetSettings mendapatkan semua setting percakapan user yang di mute / archive
+func (r *ConversationSettingRepo) GetSettings(ctx context.Context, userId uuid.UUID) ([]entity.ConversationSetting, error) {
+	var rows []conversationSettingRow
+	res := r.db.Table("conversation_settings").
+		Select(`conversation_settings.user_id, conversation_settings.conversation_type, conversation_settings.conversation_id,
+			COALESCE(users.username, groups.name) AS conversation_name, conversation_settings.muted, conversation_settings.muted_until,
+			conversation_settings.archived, conversation_settings.updated_at`).
+		Joins("LEFT JOIN users on users.id=conversation_settings.conversation_id AND conversation_settings.conversation_type = ?", entity.ConversationTypePrivate).
+		Joins("LEFT JOIN groups on groups.id=conversation_settings.conversation_id AND conversation_settings.conversation_type = ? AND groups.deleted_at IS NULL", entity.ConversationTypeGroup).
+		Where("conversation_settings.user_id = ? AND conversation_settings.deleted_at IS NULL", userId).
+		Where("conversation_settings.muted = true OR conversation_settings.archived = true").
+		Order("conversation_settings.updated_at DESC").
+		Scan(&rows)
+	if res.Error != nil {
+		return nil, fmt.Errorf("ConversationSettingRepo - GetSettings - r.db.Scan: %w", res.Error)
+	}
+
+	settings := make([]entity.ConversationSetting, 0, len(rows))
+	for _, row := range rows {
+		settings = append(settings, entity.ConversationSetting{
+			UserId:           row.UserId,
+			ConversationType: entity.ConversationType(row.ConversationType),
+			ConversationId:   row.ConversationId,
+			ConversationName: row.ConversationName,
+			Muted:            row.Muted,
+			MutedUntil:       row.MutedUntil,
+			Archived:         row.Archived,
+			UpdatedAt:        row.UpdatedAt,
+		})
+	}
+	return settings, nil
+}
+
+// GetMutedUsers mendapatkan id user yang sedang mute percakapan (mute tanpa batas waktu / muted_until belum lewat)
+func (r *ConversationSettingRepo) GetMutedUsers(ctx context.Context, convType entity.ConversationType, convId uuid.UUID) ([]uuid.UUID, error) {
+	var userIds []uuid.UUID
+	res := r.db.Model(&ConversationSetting{}).
+		Where("conversation_type = ? AND conversation_id = ? AND muted = true AND (muted_until IS NULL OR muted_until > ?)", convType, convId, time.Now()).
+		Pluck("user_id", &userIds)
+	if res.Error != nil {
+		return nil, fmt.Errorf("ConversationSettingRepo - GetMutedUsers - r.db.Pluck: %w", res.Error)
+	}
+	return userIds, nil
+}
+
+// IsMuted cek apakah user sedang mute percakapan
+func (r *ConversationSettingRepo) IsMuted(ctx context.Context, userId uuid.UUID, convType entity.ConversationType, convId uuid.UUID) (bool, error) {
+	var count int64
+	res := r.db.Model(&ConversationSetting{}).
+		Where("user_id = ? AND conversation_type = ? AND conversation_id = ? AND muted = true AND (muted_until IS NULL OR muted_until > ?)",
+			userId, convType, convId, time.Now()).
+		Count(&count)
+	if res.Error != nil {
+		return false, fmt.Errorf("ConversationSettingRepo - IsMuted - r.db.Count: %w", res.Error)
+	}
+	return count > 0, nil
+}
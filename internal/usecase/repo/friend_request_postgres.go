@@ -0,0 +1,171 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	FriendRequestPendingErr        = errors.New("you already have a pending friend request to this user")
+	FriendRequestAlreadyDecidedErr = errors.New("friend request has already been accepted, declined or cancelled")
+)
+
+type FriendRequestRepo struct {
+	db *gorm.DB
+}
+
+type FriendRequest struct {
+	gorm.Model
+	Id          uuid.UUID
+	SenderId    uuid.UUID
+	RecipientId uuid.UUID
+	Status      string
+	DecidedAt   *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// friendRequestRow friend request beserta username sender & recipient
+type friendRequestRow struct {
+	Id                uuid.UUID
+	SenderId          uuid.UUID
+	SenderUsername    string
+	RecipientId       uuid.UUID
+	RecipientUsername string
+	Status            string
+	DecidedAt         *time.Time
+	CreatedAt         time.Time
+}
+
+func NewFriendRequestRepo(db *gorm.DB) *FriendRequestRepo {
+	return &FriendRequestRepo{db}
+}
+
+// CreateFriendRequest membuat friend request pending, FriendRequestPendingErr jika sender masih punya request pending ke recipient
+func (r *FriendRequestRepo) CreateFriendRequest(ctx context.Context, senderId uuid.UUID, recipientId uuid.UUID) (entity.FriendRequest, error) {
+	var count int64
+	if res := r.db.Model(&FriendRequest{}).Where("sender_id = ? AND recipient_id = ? AND status = ?", senderId, recipientId, entity.FriendRequestPending).
+		Count(&count); res.Error != nil {
+		return entity.FriendRequest{}, fmt.Errorf("FriendRequestRepo - CreateFriendRequest - r.db.Count: %w", res.Error)
+	}
+	if count > 0 {
+		return entity.FriendRequest{}, fmt.Errorf("FriendRequestRepo - CreateFriendRequest - r.db.Count: %w", FriendRequestPendingErr)
+	}
+
+	req := FriendRequest{
+		Id:          uuid.New(),
+		SenderId:    senderId,
+		RecipientId: recipientId,
+		Status:      string(entity.FriendRequestPending),
+	}
+	if res := r.db.Create(&req); res.Error != nil {
+		return entity.FriendRequest{}, fmt.Errorf("FriendRequestRepo - CreateFriendRequest - r.db.Create: %w", res.Error)
+	}
+	return r.GetFriendRequest(ctx, req.Id)
+}
+
+// GetFriendRequest mendapatkan friend request by id
+func (r *FriendRequestRepo) GetFriendRequest(ctx context.Context, requestId uuid.UUID) (entity.FriendRequest, error) {
+	var rows []friendRequestRow
+	if res := r.friendRequestQuery().Where("friend_requests.id = ?", requestId).Scan(&rows); res.Error != nil {
+		return entity.FriendRequest{}, fmt.Errorf("FriendRequestRepo - GetFriendRequest - r.db.Scan: %w", res.Error)
+	}
+	if len(rows) == 0 {
+		return entity.FriendRequest{}, fmt.Errorf("FriendRequestRepo - GetFriendRequest - r.db.Scan: %w", gorm.ErrRecordNotFound)
+	}
+	return rows[0].toEntity(), nil
+}
+
+// GetPendingFriendRequest mendapatkan friend request pending dari sender ke recipient
+func (r *FriendRequestRepo) GetPendingFriendRequest(ctx context.Context, senderId uuid.UUID, recipientId uuid.UUID) (entity.FriendRequest, error) {
+	var rows []friendRequestRow
+	if res := r.friendRequestQuery().Where("friend_requests.sender_id = ? AND friend_requests.recipient_id = ? AND friend_requests.status = ?",
+		senderId, recipientId, entity.FriendRequestPending).Scan(&rows); res.Error != nil {
+		return entity.FriendRequest{}, fmt.Errorf("FriendRequestRepo - GetPendingFriendRequest - r.db.Scan: %w", res.Error)
+	}
+	if len(rows) == 0 {
+		return entity.FriendRequest{}, fmt.Errorf("FriendRequestRepo - GetPendingFriendRequest - r.db.Scan: %w", gorm.ErrRecordNotFound)
+	}
+	return rows[0].toEntity(), nil
+}
+
+// GetPendingFriendRequests mendapatkan semua friend request pending yang dikirim ke / oleh user
+func (r *FriendRequestRepo) GetPendingFriendRequests(ctx context.Context, userId uuid.UUID) ([]entity.FriendRequest, error) {
+	var rows []friendRequestRow
+	if res := r.friendRequestQuery().Where("(friend_requests.sender_id = ? OR friend_requests.recipient_id = ?) AND friend_requests.status = ?",
+		userId, userId, entity.FriendRequestPending).Order("friend_requests.created_at").Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("FriendRequestRepo - GetPendingFriendRequests - r.db.Scan: %w", res.Error)
+	}
+
+	res := []entity.FriendRequest{}
+	for _, row := range rows {
+		res = append(res, row.toEntity())
+	}
+	return res, nil
+}
+
+// SetFriendRequestStatus decline / cancel friend request yang masih pending
+func (r *FriendRequestRepo) SetFriendRequestStatus(ctx context.Context, requestId uuid.UUID, status entity.FriendRequestStatus) error {
+	if err := setFriendRequestStatus(r.db, requestId, status); err != nil {
+		return fmt.Errorf("FriendRequestRepo - SetFriendRequestStatus - setFriendRequestStatus: %w", err)
+	}
+	return nil
+}
+
+// AcceptFriendRequest accept friend request yang masih pending lalu membuat kontak sender & recipient dua arah dalam 1 transaction.
+// Kontak yang sebelumnya dihapus (deleted_at terisi) diaktifkan kembali
+func (r *FriendRequestRepo) AcceptFriendRequest(ctx context.Context, req entity.FriendRequest) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := setFriendRequestStatus(tx, req.Id, entity.FriendRequestAccepted); err != nil {
+			return err
+		}
+		res := tx.Exec("INSERT INTO contacts (user_id, friend_id) VALUES (?, ?), (?, ?) "+
+			"ON CONFLICT (user_id, friend_id) DO UPDATE SET deleted_at = NULL, updated_at = now()",
+			req.SenderId, req.RecipientId, req.RecipientId, req.SenderId)
+		return res.Error
+	})
+	if err != nil {
+		return fmt.Errorf("FriendRequestRepo - AcceptFriendRequest - r.db.Transaction: %w", err)
+	}
+	return nil
+}
+
+func setFriendRequestStatus(db *gorm.DB, requestId uuid.UUID, status entity.FriendRequestStatus) error {
+	res := db.Model(&FriendRequest{}).Where("id = ? AND status = ?", requestId, entity.FriendRequestPending).
+		Updates(map[string]interface{}{"status": string(status), "decided_at": time.Now(), "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return FriendRequestAlreadyDecidedErr
+	}
+	return nil
+}
+
+func (r *FriendRequestRepo) friendRequestQuery() *gorm.DB {
+	return r.db.Table("friend_requests").
+		Select("friend_requests.id, friend_requests.sender_id, sender.username AS sender_username, " +
+			"friend_requests.recipient_id, recipient.username AS recipient_username, " +
+			"friend_requests.status, friend_requests.decided_at, friend_requests.created_at").
+		Joins("JOIN users sender ON sender.id = friend_requests.sender_id").
+		Joins("JOIN users recipient ON recipient.id = friend_requests.recipient_id").
+		Where("friend_requests.deleted_at IS NULL")
+}
+
+func (row friendRequestRow) toEntity() entity.FriendRequest {
+	return entity.FriendRequest{
+		Id:                row.Id,
+		SenderId:          row.SenderId,
+		SenderUsername:    row.SenderUsername,
+		RecipientId:       row.RecipientId,
+		RecipientUsername: row.RecipientUsername,
+		Status:            entity.FriendRequestStatus(row.Status),
+		DecidedAt:         row.DecidedAt,
+		CreatedAt:         row.CreatedAt,
+	}
+}
@@ -17,7 +17,11 @@ type GroupChat struct {
 	Id        uuid.UUID
 	MessageId uint64
 	UserId    uuid.UUID
+	Kind      string
 	Content   string
+	Format    string
+	Entities  messageEntities `gorm:"type:jsonb"`
+	PinnedAt  *time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -31,7 +35,10 @@ func (r *GroupChatRepo) InsertNewChat(gcMessage entity.GroupChatMessage) (entity
 	msg := GroupChat{Id: gcMessage.GroupId,
 		MessageId: gcMessage.MessageId,
 		UserId:    gcMessage.UserId,
+		Kind:      string(groupChatKind(gcMessage.Kind)),
 		Content:   gcMessage.Content,
+		Format:    string(messageFormat(string(gcMessage.Format))),
+		Entities:  gcMessage.Entities,
 	}
 
 	if result := r.db.Create(&msg); result.Error != nil {
@@ -42,7 +49,10 @@ func (r *GroupChatRepo) InsertNewChat(gcMessage entity.GroupChatMessage) (entity
 		GroupId:   msg.Id,
 		MessageId: msg.MessageId,
 		UserId:    msg.UserId,
+		Kind:      entity.GroupChatKind(msg.Kind),
 		Content:   msg.Content,
+		Format:    messageFormat(msg.Format),
+		Entities:  msg.Entities,
 		CreatedAt: msg.CreatedAt,
 		UpdatedAt: msg.UpdatedAt,
 	}
@@ -63,7 +73,11 @@ func (r *GroupChatRepo) GetMessagesByGroupId(groupId uuid.UUID) (entity.GroupCha
 			GroupId:   gChat.Id,
 			MessageId: gChat.MessageId,
 			UserId:    gChat.UserId,
+			Kind:      groupChatKind(entity.GroupChatKind(gChat.Kind)),
 			Content:   gChat.Content,
+			Format:    messageFormat(gChat.Format),
+			Entities:  gChat.Entities,
+			PinnedAt:  gChat.PinnedAt,
 			CreatedAt: gChat.CreatedAt,
 			UpdatedAt: gChat.UpdatedAt,
 		})
@@ -75,3 +89,98 @@ func (r *GroupChatRepo) GetMessagesByGroupId(groupId uuid.UUID) (entity.GroupCha
 
 	return res, nil
 }
+
+// CountMessagesByGroupId menghitung jumlah pesan di dalam group
+func (r *GroupChatRepo) CountMessagesByGroupId(groupId uuid.UUID) (int64, error) {
+	var count int64
+	if res := r.db.Model(&GroupChat{}).Where(&GroupChat{Id: groupId}).Count(&count); res.Error != nil {
+		return 0, fmt.Errorf("GroupChatRepo - CountMessagesByGroupId - r.db.Count: %w", res.Error)
+	}
+	return count, nil
+}
+
+// GetMessagesByGroupIdAfter mendapatkan maksimal limit pesan group dengan message_id > afterId,
+// diurutkan dari yang paling lama
+func (r *GroupChatRepo) GetMessagesByGroupIdAfter(groupId uuid.UUID, afterId uint64, limit int) (entity.GroupChatMessages, error) {
+	var groupChat []GroupChat
+	if res := r.db.Where(&GroupChat{Id: groupId}).Where("message_id > ?", afterId).
+		Order("message_id").Limit(limit).Find(&groupChat); res.Error != nil {
+		return entity.GroupChatMessages{}, fmt.Errorf("GroupChatRepo - GetMessagesByGroupIdAfter -  r.db.Where(&Group{Id: groupId}).Find: %w", res.Error)
+	}
+
+	var msgs []entity.GroupChatMessage
+	for _, gChat := range groupChat {
+		msgs = append(msgs, entity.GroupChatMessage{
+			GroupId:   gChat.Id,
+			MessageId: gChat.MessageId,
+			UserId:    gChat.UserId,
+			Kind:      groupChatKind(entity.GroupChatKind(gChat.Kind)),
+			Content:   gChat.Content,
+			Format:    messageFormat(gChat.Format),
+			Entities:  gChat.Entities,
+			PinnedAt:  gChat.PinnedAt,
+			CreatedAt: gChat.CreatedAt,
+			UpdatedAt: gChat.UpdatedAt,
+		})
+	}
+
+	return entity.GroupChatMessages{Messages: msgs}, nil
+}
+
+// GetMessageById mendapatkan 1 pesan group chat
+func (r *GroupChatRepo) GetMessageById(groupId uuid.UUID, messageId uint64) (entity.GroupChatMessage, error) {
+	var gChat GroupChat
+	if res := r.db.Where(&GroupChat{Id: groupId, MessageId: messageId}).First(&gChat); res.Error != nil {
+		return entity.GroupChatMessage{}, fmt.Errorf("GroupChatRepo - GetMessageById - r.db.Where(&GroupChat{Id: groupId, MessageId: messageId}).First: %w", res.Error)
+	}
+
+	return entity.GroupChatMessage{
+		GroupId:   gChat.Id,
+		MessageId: gChat.MessageId,
+		UserId:    gChat.UserId,
+		Kind:      groupChatKind(entity.GroupChatKind(gChat.Kind)),
+		Content:   gChat.Content,
+		Format:    messageFormat(gChat.Format),
+		Entities:  gChat.Entities,
+		PinnedAt:  gChat.PinnedAt,
+		CreatedAt: gChat.CreatedAt,
+		UpdatedAt: gChat.UpdatedAt,
+	}, nil
+}
+
+// DeleteMessage soft delete pesan group chat (mengisi deleted_at)
+func (r *GroupChatRepo) DeleteMessage(groupId uuid.UUID, messageId uint64) error {
+	res := r.db.Where("id = ? AND message_id = ?", groupId, messageId).Delete(&GroupChat{})
+	if res.Error != nil {
+		return fmt.Errorf("GroupChatRepo - DeleteMessage - r.db.Delete: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("GroupChatRepo - DeleteMessage - r.db.Delete: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// SetPinned pin / unpin pesan group chat
+func (r *GroupChatRepo) SetPinned(groupId uuid.UUID, messageId uint64, pinned bool) error {
+	var pinnedAt *time.Time
+	if pinned {
+		now := time.Now()
+		pinnedAt = &now
+	}
+	res := r.db.Model(&GroupChat{}).Where("id = ? AND message_id = ?", groupId, messageId).Update("pinned_at", pinnedAt)
+	if res.Error != nil {
+		return fmt.Errorf("GroupChatRepo - SetPinned - r.db.Update: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("GroupChatRepo - SetPinned - r.db.Update: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// groupChatKind pesan tanpa kind (pesan lama) adalah pesan biasa
+func groupChatKind(kind entity.GroupChatKind) entity.GroupChatKind {
+	if kind == "" {
+		return entity.GroupChatKindMessage
+	}
+	return kind
+}
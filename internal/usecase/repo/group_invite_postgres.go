@@ -0,0 +1,178 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	InviteExpiredErr   = errors.New("invite link has expired")
+	InviteRevokedErr   = errors.New("invite link has been revoked")
+	InviteExhaustedErr = errors.New("invite link has reached its maximum number of uses")
+)
+
+type GroupInviteRepo struct {
+	db *gorm.DB
+}
+
+type GroupInvite struct {
+	gorm.Model
+	Id               uuid.UUID
+	GroupId          uuid.UUID
+	Token            string
+	CreatedBy        uuid.UUID
+	ExpiresAt        *time.Time
+	MaxUses          int
+	Uses             int
+	ApprovalRequired bool
+	RevokedAt        *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func NewGroupInviteRepo(db *gorm.DB) *GroupInviteRepo {
+	return &GroupInviteRepo{db}
+}
+
+// CreateInvite menyimpan invite link baru
+func (r *GroupInviteRepo) CreateInvite(ctx context.Context, e entity.GroupInvite) (entity.GroupInvite, error) {
+	invite := GroupInvite{
+		Id:               uuid.New(),
+		GroupId:          e.GroupId,
+		Token:            e.Token,
+		CreatedBy:        e.CreatedBy,
+		ExpiresAt:        e.ExpiresAt,
+		MaxUses:          e.MaxUses,
+		ApprovalRequired: e.ApprovalRequired,
+	}
+	if res := r.db.Create(&invite); res.Error != nil {
+		return entity.GroupInvite{}, fmt.Errorf("GroupInviteRepo - CreateInvite - r.db.Create: %w", res.Error)
+	}
+	return invite.toEntity(), nil
+}
+
+// GetInviteByToken mendapatkan invite link yang masih bisa dipakai by token
+func (r *GroupInviteRepo) GetInviteByToken(ctx context.Context, token string) (entity.GroupInvite, error) {
+	var invite GroupInvite
+	if res := r.db.Where(&GroupInvite{Token: token}).First(&invite); res.Error != nil {
+		return entity.GroupInvite{}, fmt.Errorf("GroupInviteRepo - GetInviteByToken - r.db.Where(&GroupInvite{Token: token}).First: %w", res.Error)
+	}
+	if err := invite.check(); err != nil {
+		return entity.GroupInvite{}, fmt.Errorf("GroupInviteRepo - GetInviteByToken - invite.check: %w", err)
+	}
+	return invite.toEntity(), nil
+}
+
+// GetActiveInvites mendapatkan semua invite link group yang belum di revoke, belum expired & belum habis
+func (r *GroupInviteRepo) GetActiveInvites(ctx context.Context, groupId uuid.UUID) ([]entity.GroupInvite, error) {
+	var invites []GroupInvite
+	if res := r.db.Where("group_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?) AND (max_uses = 0 OR uses < max_uses)", groupId, time.Now()).
+		Order("created_at DESC").Find(&invites); res.Error != nil {
+		return nil, fmt.Errorf("GroupInviteRepo - GetActiveInvites - r.db.Find: %w", res.Error)
+	}
+
+	res := []entity.GroupInvite{}
+	for _, invite := range invites {
+		res = append(res, invite.toEntity())
+	}
+	return res, nil
+}
+
+// RevokeInvite revoke invite link group, token tidak bisa dipakai lagi
+func (r *GroupInviteRepo) RevokeInvite(ctx context.Context, groupId uuid.UUID, inviteId uuid.UUID) error {
+	res := r.db.Model(&GroupInvite{}).Where("id = ? AND group_id = ? AND revoked_at IS NULL", inviteId, groupId).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "updated_at": time.Now()})
+	if res.Error != nil {
+		return fmt.Errorf("GroupInviteRepo - RevokeInvite - r.db.Updates: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("GroupInviteRepo - RevokeInvite - r.db.Updates: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// RedeemInvite menambahkan user ke group lewat invite link.
+// row invite di lock agar jumlah pemakaian tidak melebihi max_uses ketika banyak user redeem bersamaan
+func (r *GroupInviteRepo) RedeemInvite(ctx context.Context, token string, userId uuid.UUID, maxMembers int) (entity.Group, error) {
+	var group Group
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var invite GroupInvite
+		// error sentinel dikembalikan tanpa wrap agar bisa dibandingkan di controller
+		if res := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&GroupInvite{Token: token}).First(&invite); res.Error != nil {
+			return res.Error
+		}
+		if err := invite.check(); err != nil {
+			return err
+		}
+		if res := tx.Where(&Group{Id: invite.GroupId}).First(&group); res.Error != nil {
+			return fmt.Errorf("tx.Where(&Group{Id: invite.GroupId}).First: %w", res.Error)
+		}
+
+		var count int64
+		if res := tx.Model(&UsersGroup{}).Where(&UsersGroup{GroupId: invite.GroupId, UserId: userId}).Count(&count); res.Error != nil {
+			return fmt.Errorf("tx.Model(&UsersGroup{}).Count: %w", res.Error)
+		}
+		if count > 0 {
+			return UserAlreadyMembersErr
+		}
+		if err := checkMemberLimit(tx, invite.GroupId, 1, maxMembers); err != nil {
+			return err
+		}
+
+		userG := UsersGroup{Id: uuid.New(), UserId: userId, GroupId: invite.GroupId, Role: string(entity.GroupRoleMember)}
+		if res := tx.Create(&userG); res.Error != nil {
+			return fmt.Errorf("tx.Create(&userG): %w", res.Error)
+		}
+		if res := tx.Model(&GroupInvite{}).Where("id = ?", invite.Id).
+			Updates(map[string]interface{}{"uses": gorm.Expr("uses + 1"), "updated_at": time.Now()}); res.Error != nil {
+			return fmt.Errorf("tx.Updates(uses): %w", res.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupInviteRepo - RedeemInvite - r.db.Transaction: %w", err)
+	}
+
+	return entity.Group{
+		Id:        group.Id,
+		Name:      group.Name,
+		Role:      entity.GroupRoleMember,
+		CreatedAt: group.CreatedAt,
+		UpdatedAt: group.UpdatedAt,
+	}, nil
+}
+
+// check cek apakah invite link masih bisa dipakai
+func (i GroupInvite) check() error {
+	if i.RevokedAt != nil {
+		return InviteRevokedErr
+	}
+	if i.ExpiresAt != nil && !i.ExpiresAt.After(time.Now()) {
+		return InviteExpiredErr
+	}
+	if i.MaxUses > 0 && i.Uses >= i.MaxUses {
+		return InviteExhaustedErr
+	}
+	return nil
+}
+
+func (i GroupInvite) toEntity() entity.GroupInvite {
+	return entity.GroupInvite{
+		Id:               i.Id,
+		GroupId:          i.GroupId,
+		Token:            i.Token,
+		CreatedBy:        i.CreatedBy,
+		ExpiresAt:        i.ExpiresAt,
+		MaxUses:          i.MaxUses,
+		Uses:             i.Uses,
+		ApprovalRequired: i.ApprovalRequired,
+		RevokedAt:        i.RevokedAt,
+		CreatedAt:        i.CreatedAt,
+	}
+}
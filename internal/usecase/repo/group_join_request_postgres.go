@@ -0,0 +1,199 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	JoinRequestPendingErr        = errors.New("you already have a pending join request for this group")
+	JoinRequestAlreadyDecidedErr = errors.New("join request has already been approved or denied")
+)
+
+type GroupJoinRequestRepo struct {
+	db *gorm.DB
+}
+
+type GroupJoinRequest struct {
+	gorm.Model
+	Id        uuid.UUID
+	GroupId   uuid.UUID
+	UserId    uuid.UUID
+	InviteId  *uuid.UUID
+	Status    string
+	DecidedBy *uuid.UUID
+	DecidedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// groupJoinRequestRow join request beserta nama group & username yang request
+type groupJoinRequestRow struct {
+	Id        uuid.UUID
+	GroupId   uuid.UUID
+	GroupName string
+	UserId    uuid.UUID
+	Username  string
+	InviteId  *uuid.UUID
+	Status    string
+	DecidedBy *uuid.UUID
+	DecidedAt *time.Time
+	CreatedAt time.Time
+}
+
+func NewGroupJoinRequestRepo(db *gorm.DB) *GroupJoinRequestRepo {
+	return &GroupJoinRequestRepo{db}
+}
+
+// CreateJoinRequest membuat join request pending, JoinRequestPendingErr jika user masih punya request pending di group
+func (r *GroupJoinRequestRepo) CreateJoinRequest(ctx context.Context, e entity.GroupJoinRequest) (entity.GroupJoinRequest, error) {
+	var count int64
+	if res := r.db.Model(&GroupJoinRequest{}).Where("group_id = ? AND user_id = ? AND status = ?", e.GroupId, e.UserId, entity.GroupJoinRequestPending).
+		Count(&count); res.Error != nil {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupJoinRequestRepo - CreateJoinRequest - r.db.Count: %w", res.Error)
+	}
+	if count > 0 {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupJoinRequestRepo - CreateJoinRequest - r.db.Count: %w", JoinRequestPendingErr)
+	}
+
+	req := GroupJoinRequest{
+		Id:      uuid.New(),
+		GroupId: e.GroupId,
+		UserId:  e.UserId,
+		Status:  string(entity.GroupJoinRequestPending),
+	}
+	if e.InviteId != uuid.Nil {
+		req.InviteId = &e.InviteId
+	}
+	if res := r.db.Create(&req); res.Error != nil {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupJoinRequestRepo - CreateJoinRequest - r.db.Create: %w", res.Error)
+	}
+	return r.GetJoinRequest(ctx, e.GroupId, req.Id)
+}
+
+// GetJoinRequest mendapatkan join request di group by id
+func (r *GroupJoinRequestRepo) GetJoinRequest(ctx context.Context, groupId uuid.UUID, requestId uuid.UUID) (entity.GroupJoinRequest, error) {
+	var rows []groupJoinRequestRow
+	if res := r.joinRequestQuery().Where("group_join_requests.id = ? AND group_join_requests.group_id = ?", requestId, groupId).
+		Scan(&rows); res.Error != nil {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupJoinRequestRepo - GetJoinRequest - r.db.Scan: %w", res.Error)
+	}
+	if len(rows) == 0 {
+		return entity.GroupJoinRequest{}, fmt.Errorf("GroupJoinRequestRepo - GetJoinRequest - r.db.Scan: %w", gorm.ErrRecordNotFound)
+	}
+	return rows[0].toEntity(), nil
+}
+
+// GetPendingJoinRequests mendapatkan semua join request group yang belum di approve / deny
+func (r *GroupJoinRequestRepo) GetPendingJoinRequests(ctx context.Context, groupId uuid.UUID) ([]entity.GroupJoinRequest, error) {
+	var rows []groupJoinRequestRow
+	if res := r.joinRequestQuery().Where("group_join_requests.group_id = ? AND group_join_requests.status = ?", groupId, entity.GroupJoinRequestPending).
+		Order("group_join_requests.created_at").Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("GroupJoinRequestRepo - GetPendingJoinRequests - r.db.Scan: %w", res.Error)
+	}
+
+	res := []entity.GroupJoinRequest{}
+	for _, row := range rows {
+		res = append(res, row.toEntity())
+	}
+	return res, nil
+}
+
+// SetJoinRequestStatus approve / deny join request yang masih pending
+func (r *GroupJoinRequestRepo) SetJoinRequestStatus(ctx context.Context, requestId uuid.UUID, status entity.GroupJoinRequestStatus, decidedBy uuid.UUID) error {
+	res := r.db.Model(&GroupJoinRequest{}).Where("id = ? AND status = ?", requestId, entity.GroupJoinRequestPending).
+		Updates(map[string]interface{}{"status": string(status), "decided_by": decidedBy, "decided_at": time.Now(), "updated_at": time.Now()})
+	if res.Error != nil {
+		return fmt.Errorf("GroupJoinRequestRepo - SetJoinRequestStatus - r.db.Updates: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("GroupJoinRequestRepo - SetJoinRequestStatus - r.db.Updates: %w", JoinRequestAlreadyDecidedErr)
+	}
+	return nil
+}
+
+// ApproveJoinRequest approve join request pending & menambahkan user sebagai member group dalam 1 transaksi.
+// Status diubah lebih dulu (pending -> approved) sehingga admin yang approve bersamaan hanya 1 yang berhasil.
+// Jika request dari invite link, invite di lock & dicek ulang (revoked, expired, max_uses) lalu jumlah pemakaiannya ditambah
+func (r *GroupJoinRequestRepo) ApproveJoinRequest(ctx context.Context, joinReq entity.GroupJoinRequest, decidedBy uuid.UUID, maxMembers int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// error sentinel dikembalikan tanpa wrap agar bisa dibandingkan di controller
+		res := tx.Model(&GroupJoinRequest{}).Where("id = ? AND status = ?", joinReq.Id, entity.GroupJoinRequestPending).
+			Updates(map[string]interface{}{"status": string(entity.GroupJoinRequestApproved), "decided_by": decidedBy, "decided_at": time.Now(), "updated_at": time.Now()})
+		if res.Error != nil {
+			return fmt.Errorf("tx.Updates(status): %w", res.Error)
+		}
+		if res.RowsAffected == 0 {
+			return JoinRequestAlreadyDecidedErr
+		}
+
+		if joinReq.InviteId != uuid.Nil {
+			var invite GroupInvite
+			if res = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&GroupInvite{Id: joinReq.InviteId}).First(&invite); res.Error != nil {
+				return fmt.Errorf("tx.Where(&GroupInvite{Id: joinReq.InviteId}).First: %w", res.Error)
+			}
+			if err := invite.check(); err != nil {
+				return err
+			}
+			if res = tx.Model(&GroupInvite{}).Where("id = ?", invite.Id).
+				Updates(map[string]interface{}{"uses": gorm.Expr("uses + 1"), "updated_at": time.Now()}); res.Error != nil {
+				return fmt.Errorf("tx.Updates(uses): %w", res.Error)
+			}
+		}
+
+		var count int64
+		if res = tx.Model(&UsersGroup{}).Where(&UsersGroup{GroupId: joinReq.GroupId, UserId: joinReq.UserId}).Count(&count); res.Error != nil {
+			return fmt.Errorf("tx.Model(&UsersGroup{}).Count: %w", res.Error)
+		}
+		if count > 0 {
+			return UserAlreadyMembersErr
+		}
+		if err := checkMemberLimit(tx, joinReq.GroupId, 1, maxMembers); err != nil {
+			return err
+		}
+		userG := UsersGroup{Id: uuid.New(), UserId: joinReq.UserId, GroupId: joinReq.GroupId, Role: string(entity.GroupRoleMember)}
+		if res = tx.Create(&userG); res.Error != nil {
+			return fmt.Errorf("tx.Create(&userG): %w", res.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("GroupJoinRequestRepo - ApproveJoinRequest - r.db.Transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *GroupJoinRequestRepo) joinRequestQuery() *gorm.DB {
+	return r.db.Table("group_join_requests").
+		Select("group_join_requests.id, group_join_requests.group_id, groups.name AS group_name, group_join_requests.user_id, users.username, " +
+			"group_join_requests.invite_id, group_join_requests.status, group_join_requests.decided_by, group_join_requests.decided_at, group_join_requests.created_at").
+		Joins("JOIN groups ON groups.id = group_join_requests.group_id").
+		Joins("JOIN users ON users.id = group_join_requests.user_id").
+		Where("group_join_requests.deleted_at IS NULL")
+}
+
+func (row groupJoinRequestRow) toEntity() entity.GroupJoinRequest {
+	req := entity.GroupJoinRequest{
+		Id:        row.Id,
+		GroupId:   row.GroupId,
+		GroupName: row.GroupName,
+		UserId:    row.UserId,
+		Username:  row.Username,
+		Status:    entity.GroupJoinRequestStatus(row.Status),
+		DecidedAt: row.DecidedAt,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.InviteId != nil {
+		req.InviteId = *row.InviteId
+	}
+	if row.DecidedBy != nil {
+		req.DecidedBy = *row.DecidedBy
+	}
+	return req
+}
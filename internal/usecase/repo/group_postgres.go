@@ -7,13 +7,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/lintangbs/chat-be/internal/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 var (
 	UserNotMemberErr      = errors.New("users tidak termasuk dalam group")
-	GroupAlreadyExistsErr = errors.New("group with same username already exists")
+	AmbiguousGroupNameErr = errors.New("you are a member of more than one group with this name, use group_id")
 	UserAlreadyMembersErr = errors.New("users already in group ")
+	GroupMemberLimitErr   = errors.New("the group has reached the maximum number of members")
 )
 
 type GroupRepo struct {
@@ -22,11 +24,13 @@ type GroupRepo struct {
 
 type Group struct {
 	gorm.Model
-	Id        uuid.UUID
-	Name      string
-	Members   []UsersGroup
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id          uuid.UUID
+	Name        string
+	Description string
+	AvatarUrl   string
+	Members     []UsersGroup
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 type UsersGroup struct {
@@ -34,6 +38,15 @@ type UsersGroup struct {
 	Id      uuid.UUID
 	UserId  uuid.UUID
 	GroupId uuid.UUID
+	Role    string
+}
+
+// groupMemberRow member group beserta username
+type groupMemberRow struct {
+	UserId    uuid.UUID
+	Username  string
+	Role      string
+	CreatedAt time.Time
 }
 
 // Reename table
@@ -56,14 +69,7 @@ func (r *GroupRepo) CreateGroup(ctx context.Context, e entity.CreateGroupRequest
 
 	var ug []UsersGroup
 
-	var grp []Group
-
-	// group dg username ada di db
-	r.db.Where(&Group{Name: e.Name}).Find(&grp)
-	if len(grp) != 0 {
-		return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup -  r.db.Create: %w", GroupAlreadyExistsErr)
-	}
-
+	// nama group tidak harus unik, group diidentifikasi dengan id
 	//Create Group
 	g := &Group{Id: groupId, Name: e.Name}
 	if result := r.db.Create(&g); result.Error != nil {
@@ -72,7 +78,8 @@ func (r *GroupRepo) CreateGroup(ctx context.Context, e entity.CreateGroupRequest
 
 	// Add group Members
 
-	userG := UsersGroup{Id: uuid.New(), UserId: e.UserId, GroupId: g.Id}
+	// pembuat group menjadi owner
+	userG := UsersGroup{Id: uuid.New(), UserId: e.UserId, GroupId: g.Id, Role: string(entity.GroupRoleOwner)}
 
 	if result := r.db.Create(&userG); result.Error != nil {
 		return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup -  r.db.Create: %w", result.Error)
@@ -81,7 +88,7 @@ func (r *GroupRepo) CreateGroup(ctx context.Context, e entity.CreateGroupRequest
 	ug = append(ug, userG)
 
 	for _, memberId := range e.Members {
-		userG = UsersGroup{Id: uuid.New(), UserId: memberId, GroupId: g.Id}
+		userG = UsersGroup{Id: uuid.New(), UserId: memberId, GroupId: g.Id, Role: string(entity.GroupRoleMember)}
 		//create new group and insert user to group
 		if result := r.db.Create(&userG); result.Error != nil {
 			return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup -  r.db.Create: %w", result.Error)
@@ -90,7 +97,7 @@ func (r *GroupRepo) CreateGroup(ctx context.Context, e entity.CreateGroupRequest
 	}
 	g.Members = ug
 	if res := r.db.Save(&g); res.Error != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup -r.db.Save(&g)", res.Error)
+		return entity.Group{}, fmt.Errorf("GroupRepo - CreateGroup -r.db.Save(&g): %w", res.Error)
 
 	}
 
@@ -106,44 +113,53 @@ func (r *GroupRepo) CreateGroup(ctx context.Context, e entity.CreateGroupRequest
 
 func (r *GroupRepo) AddNewGroupMember(ctx context.Context, e entity.AddNewGroupMemberReq) (entity.Group, error) {
 	var group Group
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// error sentinel dikembalikan tanpa wrap agar bisa dibandingkan di controller
+		if err := checkMemberLimit(tx, e.GroupId, len(e.Members), e.MaxMembers); err != nil {
+			return err
+		}
+		if res := tx.Where(&Group{Id: e.GroupId}).Preload("Members").First(&group); res.Error != nil {
+			return fmt.Errorf("tx.Where(&Group{Id: e.GroupId}).First(&group): %w", res.Error)
+		}
 
-	if res := r.db.Where(&Group{Name: e.Name}).Joins("LEFT JOIN users_group on users_group.group_id=groups.id").Preload("Members").First(&group); res.Error != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", res.Error)
-	}
-
-	uMap := make(map[string]bool)
+		uMap := make(map[string]bool)
 
-	isMember := false
-	// cek apakah user yg login termasuk member dari groupnya
-	for _, member := range group.Members {
-		if member.UserId == e.UserId {
-			isMember = true
+		isMember := false
+		// cek apakah user yg login termasuk member dari groupnya
+		for _, member := range group.Members {
+			if member.UserId == e.UserId {
+				isMember = true
+			}
+			uMap[member.UserId.String()] = true
 		}
-		uMap[member.UserId.String()] = true
-	}
 
-	if isMember == false {
-		return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", UserNotMemberErr)
-	}
+		if isMember == false {
+			return UserNotMemberErr
+		}
 
-	for _, memToRegisterId := range e.Members {
-		memToRegisteridStr := memToRegisterId.String()
-		if _, prs := uMap[memToRegisteridStr]; prs == true {
-			return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", UserAlreadyMembersErr)
+		for _, memToRegisterId := range e.Members {
+			memToRegisteridStr := memToRegisterId.String()
+			if _, prs := uMap[memToRegisteridStr]; prs == true {
+				return UserAlreadyMembersErr
+			}
 		}
-	}
 
-	for _, memberId := range e.Members {
-		group.Members = append(group.Members, UsersGroup{Id: uuid.New(), UserId: memberId})
-	}
+		for _, memberId := range e.Members {
+			group.Members = append(group.Members, UsersGroup{Id: uuid.New(), UserId: memberId, Role: string(entity.GroupRoleMember)})
+		}
 
-	if res := r.db.Save(&group); res.Error != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember - r.db.Save(&group): %w", res.Error)
+		if res := tx.Save(&group); res.Error != nil {
+			return fmt.Errorf("tx.Save(&group): %w", res.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember - r.db.Transaction: %w", err)
 	}
 
 	res := entity.Group{
 		Id:        group.Id,
-		Name:      e.Name,
+		Name:      group.Name,
 		CreatedAt: group.CreatedAt,
 		UpdatedAt: group.UpdatedAt,
 	}
@@ -153,8 +169,8 @@ func (r *GroupRepo) AddNewGroupMember(ctx context.Context, e entity.AddNewGroupM
 
 func (r *GroupRepo) RemoveMember(ctx context.Context, e entity.RemoveGroupMemberReq) (entity.Group, error) {
 	var group Group
-	if res := r.db.Where(&Group{Name: e.Name}).Joins("LEFT JOIN users_group on users_group.group_id=groups.id").Preload("Members").First(&group); res.Error != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", res.Error)
+	if res := r.db.Where(&Group{Id: e.GroupId}).Joins("LEFT JOIN users_group on users_group.group_id=groups.id").Preload("Members").First(&group); res.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - RemoveMember -  r.db.Where(&Group{Id: e.GroupId}).First(&group): %w", res.Error)
 	}
 
 	isMember := false
@@ -168,77 +184,290 @@ func (r *GroupRepo) RemoveMember(ctx context.Context, e entity.RemoveGroupMember
 		return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", UserNotMemberErr)
 	}
 
-	if result := r.db.Exec("DELETE FROM users_group WHERE user_id=" + "'" + e.Member.String() + "'" + " AND group_id='" + group.Id.String() + "';"); result.RowsAffected == 0 {
-		return entity.Group{}, fmt.Errorf("GroupRepo - RemoveMember - r.db.Save(&group): rows already deleted")
+	result := r.db.Unscoped().Where("user_id = ? AND group_id = ?", e.Member, group.Id).Delete(&UsersGroup{})
+	if result.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - RemoveMember - r.db.Delete(&UsersGroup{}): %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entity.Group{}, fmt.Errorf("GroupRepo - RemoveMember - r.db.Delete(&UsersGroup{}): %w", UserNotMemberErr)
 	}
 
 	res := entity.Group{
 		Id:        group.Id,
-		Name:      e.Name,
+		Name:      group.Name,
 		CreatedAt: group.CreatedAt,
 		UpdatedAt: group.UpdatedAt,
 	}
 	return res, nil
 }
 
-func (r *GroupRepo) GetGroupMembers(groupId uuid.UUID, userId uuid.UUID) (entity.Group, error) {
-	var group Group
-	if res := r.db.Where(&Group{Id: groupId}).Joins("LEFT JOIN users_group on users_group.group_id=groups.id").Preload("Members").First(&group); res.Error != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - AddNewGroupMember -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", res.Error)
+// GetGroupMembers mendapatkan member group per halaman (urut user_id), afterId = user_id terakhir di halaman sebelumnya
+func (r *GroupRepo) GetGroupMembers(groupId uuid.UUID, afterId uuid.UUID, limit int) ([]entity.GroupMemberDetail, error) {
+	var rows []groupMemberRow
+	query := r.db.Table("users_group").
+		Select("users_group.user_id, users.username, users_group.role, users_group.created_at").
+		Joins("JOIN users on users.id=users_group.user_id").
+		Where("users_group.group_id = ? AND users_group.deleted_at IS NULL", groupId)
+	if afterId != uuid.Nil {
+		query = query.Where("users_group.user_id > ?", afterId)
+	}
+	if res := query.Order("users_group.user_id").Limit(limit).Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("GroupRepo - GetGroupMembers - r.db.Scan: %w", res.Error)
 	}
 
-	// cek apakah user yg login termasuk member dari groupnya
-	uMap := make(map[string]bool)
-	isMember := false
-	for _, member := range group.Members {
-		if member.UserId == userId {
-			isMember = true
-		}
-		uMap[member.UserId.String()] = true
+	members := make([]entity.GroupMemberDetail, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, entity.GroupMemberDetail{
+			UserId:   row.UserId,
+			Username: row.Username,
+			Role:     entity.GroupRole(row.Role),
+			JoinedAt: row.CreatedAt,
+		})
 	}
+	return members, nil
+}
 
-	if isMember == false {
-		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupMembers -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", UserNotMemberErr)
+// CountGroupMembers jumlah member group
+func (r *GroupRepo) CountGroupMembers(groupId uuid.UUID) (int64, error) {
+	var count int64
+	if res := r.db.Model(&UsersGroup{}).Where("group_id = ?", groupId).Count(&count); res.Error != nil {
+		return 0, fmt.Errorf("GroupRepo - CountGroupMembers - r.db.Count: %w", res.Error)
 	}
+	return count, nil
+}
 
-	var members []uuid.UUID
-	for _, member := range group.Members {
-		members = append(members, member.UserId)
+// checkMemberLimit lock row group (SELECT ... FOR UPDATE) lalu cek apakah group masih bisa ditambah newMembers member.
+// Harus dipanggil di transaksi yang sama dengan insert member supaya penambahan member bersamaan tidak melewati batas
+func checkMemberLimit(tx *gorm.DB, groupId uuid.UUID, newMembers int, maxMembers int) error {
+	var group Group
+	if res := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where(&Group{Id: groupId}).First(&group); res.Error != nil {
+		return fmt.Errorf("tx.Where(&Group{Id: groupId}).First(&group): %w", res.Error)
+	}
+	var count int64
+	if res := tx.Model(&UsersGroup{}).Where("group_id = ?", groupId).Count(&count); res.Error != nil {
+		return fmt.Errorf("tx.Model(&UsersGroup{}).Count: %w", res.Error)
+	}
+	if !(entity.GroupLimits{MaxMembers: maxMembers}).CanAdd(count, newMembers) {
+		return GroupMemberLimitErr
+	}
+	return nil
+}
+
+// GetGroupById mendapatkan group by id, user harus member group
+func (r *GroupRepo) GetGroupById(groupId uuid.UUID, userId uuid.UUID) (entity.Group, error) {
+	var group Group
+	if res := r.db.Where(&Group{Id: groupId}).First(&group); res.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupById -  r.db.Where(&Group{Id: groupId}).First(&group): %w", res.Error)
+	}
+
+	// cek apakah user yg login termasuk member dari groupnya, member tidak di preload karena group bisa sangat besar
+	var count int64
+	if res := r.db.Model(&UsersGroup{}).Where("group_id = ? AND user_id = ?", groupId, userId).Count(&count); res.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupById - r.db.Count: %w", res.Error)
+	}
+	if count == 0 {
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupById -  r.db.Where(&Group{Id: groupId}).First(&group): %w", UserNotMemberErr)
 	}
 
 	groupRes := entity.Group{
-		Id:      group.Id,
-		Name:    group.Name,
-		Members: members,
+		Id:          group.Id,
+		Name:        group.Name,
+		Description: group.Description,
+		AvatarUrl:   group.AvatarUrl,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
 	}
+	return groupRes, nil
+}
 
+// GetGroupByName mendapatkan group by name di antara group yang diikuti user.
+// Deprecated: nama group tidak unik, hanya untuk client lama yang belum mengirim group id
+func (r *GroupRepo) GetGroupByName(groupName string, userId uuid.UUID) (entity.Group, error) {
+	var groups []Group
+	if res := r.db.Joins("JOIN users_group on users_group.group_id=groups.id AND users_group.deleted_at IS NULL").
+		Where("groups.name = ? AND users_group.user_id = ?", groupName, userId).Find(&groups); res.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByName -  r.db.Where(&Group{Name: groupName}).Find(&groups): %w", res.Error)
+	}
+
+	if len(groups) == 0 {
+		// bedakan group tidak ada dg user bukan member group
+		var count int64
+		r.db.Model(&Group{}).Where(&Group{Name: groupName}).Count(&count)
+		if count == 0 {
+			return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByName -  r.db.Where(&Group{Name: groupName}).Find(&groups): %w", gorm.ErrRecordNotFound)
+		}
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByName -  r.db.Where(&Group{Name: groupName}).Find(&groups): %w", UserNotMemberErr)
+	}
+	if len(groups) > 1 {
+		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByName -  r.db.Where(&Group{Name: groupName}).Find(&groups): %w", AmbiguousGroupNameErr)
+	}
+
+	groupRes := entity.Group{
+		Id:          groups[0].Id,
+		Name:        groups[0].Name,
+		Description: groups[0].Description,
+		AvatarUrl:   groups[0].AvatarUrl,
+		CreatedAt:   groups[0].CreatedAt,
+		UpdatedAt:   groups[0].UpdatedAt,
+	}
 	return groupRes, nil
+}
 
+// GetMemberRole mendapatkan role user di group, UserNotMemberErr jika user bukan member group
+func (r *GroupRepo) GetMemberRole(groupId uuid.UUID, userId uuid.UUID) (entity.GroupRole, error) {
+	var ug UsersGroup
+	res := r.db.Where(&UsersGroup{GroupId: groupId, UserId: userId}).First(&ug)
+	if res.Error == gorm.ErrRecordNotFound {
+		return "", fmt.Errorf("GroupRepo - GetMemberRole - r.db.Where(&UsersGroup{}).First: %w", UserNotMemberErr)
+	}
+	if res.Error != nil {
+		return "", fmt.Errorf("GroupRepo - GetMemberRole - r.db.Where(&UsersGroup{}).First: %w", res.Error)
+	}
+	if ug.Role == "" {
+		return entity.GroupRoleMember, nil
+	}
+	return entity.GroupRole(ug.Role), nil
 }
 
-func (r *GroupRepo) GetGroupByName(groupName string, userId uuid.UUID) (entity.Group, error) {
+// SetMemberRole mengubah role member group
+func (r *GroupRepo) SetMemberRole(groupId uuid.UUID, userId uuid.UUID, role entity.GroupRole) error {
+	res := r.db.Model(&UsersGroup{}).Where("group_id = ? AND user_id = ?", groupId, userId).Update("role", string(role))
+	if res.Error != nil {
+		return fmt.Errorf("GroupRepo - SetMemberRole - r.db.Update: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("GroupRepo - SetMemberRole - r.db.Update: %w", UserNotMemberErr)
+	}
+	return nil
+}
+
+// RenameGroup mengganti nama group
+func (r *GroupRepo) RenameGroup(groupId uuid.UUID, newName string) (entity.Group, error) {
 	var group Group
-	if res := r.db.Where(&Group{Name: groupName}).Joins("LEFT JOIN users_group on users_group.group_id=groups.id").Preload("Members").First(&group); res.Error != nil {
-		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByName -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", res.Error)
+	if res := r.db.Where(&Group{Id: groupId}).First(&group); res.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - RenameGroup - r.db.Where(&Group{Id: groupId}).First: %w", res.Error)
+	}
+	if res := r.db.Model(&Group{}).Where("id = ?", groupId).Updates(map[string]interface{}{"name": newName, "updated_at": time.Now()}); res.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - RenameGroup - r.db.Updates: %w", res.Error)
 	}
 
-	// cek apakah user yg login termasuk member dari groupnya
-	uMap := make(map[string]bool)
-	isMember := false
-	for _, member := range group.Members {
-		if member.UserId == userId {
-			isMember = true
+	return entity.Group{
+		Id:        group.Id,
+		Name:      newName,
+		CreatedAt: group.CreatedAt,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// GetUserGroups mendapatkan semua group yang diikuti user beserta role user di group tsb
+func (r *GroupRepo) GetUserGroups(userId uuid.UUID) ([]entity.Group, error) {
+	type userGroup struct {
+		Id          uuid.UUID
+		Name        string
+		Description string
+		AvatarUrl   string
+		Role        string
+		CreatedAt   time.Time
+		UpdatedAt   time.Time
+	}
+	var rows []userGroup
+	if res := r.db.Table("groups").
+		Select("groups.id, groups.name, groups.description, groups.avatar_url, users_group.role, groups.created_at, groups.updated_at").
+		Joins("JOIN users_group on users_group.group_id=groups.id AND users_group.deleted_at IS NULL").
+		Where("users_group.user_id = ? AND groups.deleted_at IS NULL", userId).
+		Order("groups.name").Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("GroupRepo - GetUserGroups - r.db.Scan: %w", res.Error)
+	}
+
+	var groups []entity.Group
+	for _, row := range rows {
+		groups = append(groups, entity.Group{
+			Id:          row.Id,
+			Name:        row.Name,
+			Description: row.Description,
+			AvatarUrl:   row.AvatarUrl,
+			Role:        entity.GroupRole(row.Role),
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		})
+	}
+	return groups, nil
+}
+
+// GetGroupAdmins mendapatkan id owner & admin group
+func (r *GroupRepo) GetGroupAdmins(groupId uuid.UUID) ([]uuid.UUID, error) {
+	var admins []uuid.UUID
+	if res := r.db.Model(&UsersGroup{}).Where("group_id = ? AND role IN ?", groupId, []string{string(entity.GroupRoleOwner), string(entity.GroupRoleAdmin)}).
+		Pluck("user_id", &admins); res.Error != nil {
+		return nil, fmt.Errorf("GroupRepo - GetGroupAdmins - r.db.Pluck: %w", res.Error)
+	}
+	return admins, nil
+}
+
+// UpdateGroup mengubah nama, deskripsi & avatar group
+func (r *GroupRepo) UpdateGroup(group entity.Group) (entity.Group, error) {
+	group.UpdatedAt = time.Now()
+	if res := r.db.Model(&Group{}).Where("id = ?", group.Id).Updates(map[string]interface{}{
+		"name":        group.Name,
+		"description": group.Description,
+		"avatar_url":  group.AvatarUrl,
+		"updated_at":  group.UpdatedAt,
+	}); res.Error != nil {
+		return entity.Group{}, fmt.Errorf("GroupRepo - UpdateGroup - r.db.Updates: %w", res.Error)
+	}
+	return group, nil
+}
+
+// LeaveGroup menghapus user dari member group
+func (r *GroupRepo) LeaveGroup(groupId uuid.UUID, userId uuid.UUID) error {
+	res := r.db.Unscoped().Where("group_id = ? AND user_id = ?", groupId, userId).Delete(&UsersGroup{})
+	if res.Error != nil {
+		return fmt.Errorf("GroupRepo - LeaveGroup - r.db.Delete: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("GroupRepo - LeaveGroup - r.db.Delete: %w", UserNotMemberErr)
+	}
+	return nil
+}
+
+// TransferOwnership member newOwner menjadi owner group, owner lama menjadi admin
+func (r *GroupRepo) TransferOwnership(groupId uuid.UUID, oldOwner uuid.UUID, newOwner uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&UsersGroup{}).Where("group_id = ? AND user_id = ?", groupId, newOwner).Update("role", string(entity.GroupRoleOwner))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return UserNotMemberErr
 		}
-		uMap[member.UserId.String()] = true
+		return tx.Model(&UsersGroup{}).Where("group_id = ? AND user_id = ?", groupId, oldOwner).Update("role", string(entity.GroupRoleAdmin)).Error
+	})
+	if err != nil {
+		return fmt.Errorf("GroupRepo - TransferOwnership - r.db.Transaction: %w", err)
 	}
+	return nil
+}
 
-	if isMember == false {
-		return entity.Group{}, fmt.Errorf("GroupRepo - GetGroupByName -  r.db.Where(&Group{Name: e.Name}).First(&group): %w", UserNotMemberErr)
+// DeleteGroup soft delete group (mengisi groups.deleted_at), group tidak bisa diakses lagi oleh member
+func (r *GroupRepo) DeleteGroup(groupId uuid.UUID) error {
+	res := r.db.Model(&Group{}).Where("id = ?", groupId).Update("deleted_at", time.Now())
+	if res.Error != nil {
+		return fmt.Errorf("GroupRepo - DeleteGroup - r.db.Update: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("GroupRepo - DeleteGroup - r.db.Update: %w", gorm.ErrRecordNotFound)
 	}
+	return nil
+}
 
-	groupRes := entity.Group{
-		Id:   group.Id,
-		Name: group.Name,
+// GetGroupRecipients mendapatkan id & username semua member group untuk fanout pesan group
+func (r *GroupRepo) GetGroupRecipients(groupId uuid.UUID) ([]entity.GroupRecipient, error) {
+	var recipients []entity.GroupRecipient
+	if res := r.db.Table("users_group").Select("users_group.user_id, users.username").
+		Joins("JOIN users on users.id=users_group.user_id").
+		Where("users_group.group_id = ? AND users_group.deleted_at IS NULL", groupId).
+		Scan(&recipients); res.Error != nil {
+		return nil, fmt.Errorf("GroupRepo - GetGroupRecipients - r.db.Scan: %w", res.Error)
 	}
-	return groupRes, nil
+	return recipients, nil
 }
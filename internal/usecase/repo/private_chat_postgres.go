@@ -1,6 +1,8 @@
 package repo
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/lintangbs/chat-be/internal/entity"
@@ -18,10 +20,47 @@ type PrivateChat struct {
 	MessageFrom uuid.UUID
 	MessageTo   uuid.UUID
 	Content     string `gorm:"type:text"`
+	Format      string
+	Entities    messageEntities `gorm:"type:jsonb"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
 
+// messageEntities kolom jsonb entities di table private_chats, group_chats & channel_messages
+type messageEntities []entity.MessageEntity
+
+func (m messageEntities) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (m *messageEntities) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	}
+	return fmt.Errorf("messageEntities - Scan: unsupported type %T", src)
+}
+
+// messageFormat format pesan lama (sebelum ada kolom format) dianggap plain
+func messageFormat(format string) entity.MessageFormat {
+	if format == "" {
+		return entity.MessageFormatPlain
+	}
+	return entity.MessageFormat(format)
+}
+
 func NewPrivateChatRepo(db *gorm.DB) *PrivateChatRepo {
 	return &PrivateChatRepo{db}
 }
@@ -29,7 +68,14 @@ func NewPrivateChatRepo(db *gorm.DB) *PrivateChatRepo {
 // InsertPrivateChat insert private chat to private chat table
 func (r *PrivateChatRepo) InsertPrivateChat(e entity.InsertPrivateChatRequest) (entity.PrivateChatMessage, error) {
 
-	msg := PrivateChat{Id: e.MessageId, MessageFrom: e.MessageFrom, MessageTo: e.MessageTo, Content: e.Content}
+	msg := PrivateChat{
+		Id:          e.MessageId,
+		MessageFrom: e.MessageFrom,
+		MessageTo:   e.MessageTo,
+		Content:     e.Content,
+		Format:      string(messageFormat(string(e.Format))),
+		Entities:    e.Entities,
+	}
 	if result := r.db.Create(&msg); result.Error != nil {
 		return entity.PrivateChatMessage{}, fmt.Errorf("PrivateChatRepo -  InsertPrivateChat - r.db.Create: %w", result.Error)
 	}
@@ -39,6 +85,8 @@ func (r *PrivateChatRepo) InsertPrivateChat(e entity.InsertPrivateChatRequest) (
 		MessageFrom: e.MessageFrom,
 		MessageTo:   e.MessageTo,
 		Content:     e.Content,
+		Format:      messageFormat(msg.Format),
+		Entities:    msg.Entities,
 		CreatedAt:   msg.CreatedAt,
 		UpdatedAt:   msg.UpdatedAt,
 	}
@@ -66,6 +114,8 @@ func (r *PrivateChatRepo) GetPrivateChatByUser(e entity.GetPrivateChatQueryByUse
 			MessageFrom: msg.MessageFrom,
 			MessageTo:   msg.MessageTo,
 			Content:     msg.Content,
+			Format:      messageFormat(msg.Format),
+			Entities:    msg.Entities,
 			CreatedAt:   msg.CreatedAt,
 			UpdatedAt:   msg.UpdatedAt,
 			DeletedAt:   msg.UpdatedAt,
@@ -111,6 +161,8 @@ func (r *PrivateChatRepo) GetPrivateChatBySenderAndReceiver(e entity.GetPCQueryB
 			MessageFrom: msg.MessageFrom,
 			MessageTo:   msg.MessageTo,
 			Content:     msg.Content,
+			Format:      messageFormat(msg.Format),
+			Entities:    msg.Entities,
 			CreatedAt:   msg.CreatedAt,
 			UpdatedAt:   msg.UpdatedAt,
 			DeletedAt:   msg.UpdatedAt,
@@ -120,3 +172,70 @@ func (r *PrivateChatRepo) GetPrivateChatBySenderAndReceiver(e entity.GetPCQueryB
 	pcs.Messages = arrPcs
 	return pcs, nil
 }
+
+// CountPrivateChatBySenderAndReceiver menghitung jumlah pesan antara sender & receiver
+func (r *PrivateChatRepo) CountPrivateChatBySenderAndReceiver(e entity.GetPCQueryBySdrAndRcvrRequest) (int64, error) {
+	var count int64
+	if result := r.db.Model(&PrivateChat{}).Where(&PrivateChat{MessageFrom: e.SenderId, MessageTo: e.ReceiverId}).Or(&PrivateChat{MessageFrom: e.ReceiverId, MessageTo: e.SenderId}).Count(&count); result.Error != nil {
+		return 0, fmt.Errorf("PrivateChatRepo - CountPrivateChatBySenderAndReceiver - r.db.Count: %w", result.Error)
+	}
+	return count, nil
+}
+
+// GetPrivateChatBySenderAndReceiverAfter mendapatkan maksimal limit pesan antara sender & receiver
+// dengan id > afterId, diurutkan dari yang paling lama (sonyflake id terurut berdasarkan waktu)
+func (r *PrivateChatRepo) GetPrivateChatBySenderAndReceiverAfter(e entity.GetPCQueryBySdrAndRcvrRequest, afterId uint64, limit int) (entity.PrivateChats, error) {
+	var msgs []PrivateChat
+
+	if result := r.db.Where("((message_from = ? AND message_to = ?) OR (message_from = ? AND message_to = ?)) AND id > ?",
+		e.SenderId, e.ReceiverId, e.ReceiverId, e.SenderId, afterId).
+		Order("id").Limit(limit).Find(&msgs); result.Error != nil {
+		return entity.PrivateChats{}, fmt.Errorf("PrivateChatRepo - GetPrivateChatBySenderAndReceiverAfter -  r.db.Where: %w", result.Error)
+	}
+
+	var pcs entity.PrivateChats
+	for _, msg := range msgs {
+		pcs.Messages = append(pcs.Messages, entity.PrivateChatMessage{
+			MessageId:   msg.Id,
+			MessageFrom: msg.MessageFrom,
+			MessageTo:   msg.MessageTo,
+			Content:     msg.Content,
+			Format:      messageFormat(msg.Format),
+			Entities:    msg.Entities,
+			CreatedAt:   msg.CreatedAt,
+			UpdatedAt:   msg.UpdatedAt,
+		})
+	}
+	return pcs, nil
+}
+
+// GetPrivateChatById mendapatkan 1 pesan private chat berdasarkan id
+func (r *PrivateChatRepo) GetPrivateChatById(messageId uint64) (entity.PrivateChatMessage, error) {
+	var msg PrivateChat
+	if result := r.db.Where("id = ?", messageId).First(&msg); result.Error != nil {
+		return entity.PrivateChatMessage{}, fmt.Errorf("PrivateChatRepo - GetPrivateChatById - r.db.Where(\"id = ?\", messageId).First: %w", result.Error)
+	}
+
+	return entity.PrivateChatMessage{
+		MessageId:   msg.Id,
+		MessageFrom: msg.MessageFrom,
+		MessageTo:   msg.MessageTo,
+		Content:     msg.Content,
+		Format:      messageFormat(msg.Format),
+		Entities:    msg.Entities,
+		CreatedAt:   msg.CreatedAt,
+		UpdatedAt:   msg.UpdatedAt,
+	}, nil
+}
+
+// DeletePrivateChat soft delete pesan private chat (mengisi deleted_at)
+func (r *PrivateChatRepo) DeletePrivateChat(messageId uint64) error {
+	result := r.db.Where("id = ?", messageId).Delete(&PrivateChat{})
+	if result.Error != nil {
+		return fmt.Errorf("PrivateChatRepo - DeletePrivateChat - r.db.Delete: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("PrivateChatRepo - DeletePrivateChat - r.db.Delete: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ReportAlreadyClosedErr = errors.New("report already resolved or dismissed")
+)
+
+type ReportRepo struct {
+	db *gorm.DB
+}
+
+type Report struct {
+	gorm.Model
+	ID               uuid.UUID
+	ReporterId       *uuid.UUID // null jika report dibuat oleh content filter
+	ReportedUserId   uuid.UUID
+	ConversationType *string
+	MessageId        *uint64
+	GroupId          *uuid.UUID
+	Reason           string
+	Status           string
+	ResolvedBy       *uuid.UUID
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func NewReportRepo(db *gorm.DB) *ReportRepo {
+	return &ReportRepo{db}
+}
+
+// CreateReport insert report baru ke table reports
+func (r *ReportRepo) CreateReport(ctx context.Context, e entity.Report) (entity.Report, error) {
+	report := Report{
+		ID:             uuid.New(),
+		ReportedUserId: e.ReportedUserId,
+		Reason:         e.Reason,
+		Status:         string(entity.ReportStatusOpen),
+	}
+	if e.ReporterId != uuid.Nil {
+		report.ReporterId = &e.ReporterId
+	}
+	if e.ConversationType != "" {
+		convType := string(e.ConversationType)
+		report.ConversationType = &convType
+		report.MessageId = &e.MessageId
+	}
+	if e.GroupId != uuid.Nil {
+		report.GroupId = &e.GroupId
+	}
+
+	if res := r.db.Create(&report); res.Error != nil {
+		return entity.Report{}, fmt.Errorf("ReportRepo - CreateReport - r.db.Create: %w", res.Error)
+	}
+	return report.toEntity(), nil
+}
+
+// GetReports mendapatkan semua report dengan status tertentu, diurutkan dari yang paling lama
+func (r *ReportRepo) GetReports(ctx context.Context, status entity.ReportStatus) ([]entity.Report, error) {
+	var reports []Report
+	if res := r.db.Where(&Report{Status: string(status)}).Order("created_at").Find(&reports); res.Error != nil {
+		return nil, fmt.Errorf("ReportRepo - GetReports - r.db.Where(&Report{Status: status}).Find: %w", res.Error)
+	}
+
+	var res []entity.Report
+	for _, report := range reports {
+		res = append(res, report.toEntity())
+	}
+	return res, nil
+}
+
+// ResolveReport mengubah status report yang masih open menjadi resolved / dismissed
+func (r *ReportRepo) ResolveReport(ctx context.Context, reportId uuid.UUID, status entity.ReportStatus, adminId uuid.UUID) (entity.Report, error) {
+	var report Report
+	if res := r.db.Where(&Report{ID: reportId}).First(&report); res.Error != nil {
+		return entity.Report{}, fmt.Errorf("ReportRepo - ResolveReport - r.db.Where(&Report{ID: reportId}).First: %w", res.Error)
+	}
+	if report.Status != string(entity.ReportStatusOpen) {
+		return entity.Report{}, fmt.Errorf("ReportRepo - ResolveReport: %w", ReportAlreadyClosedErr)
+	}
+
+	report.Status = string(status)
+	report.ResolvedBy = &adminId
+	if res := r.db.Model(&Report{}).Where("id = ?", reportId).
+		Updates(map[string]interface{}{"status": report.Status, "resolved_by": adminId, "updated_at": time.Now()}); res.Error != nil {
+		return entity.Report{}, fmt.Errorf("ReportRepo - ResolveReport - r.db.Updates: %w", res.Error)
+	}
+	return report.toEntity(), nil
+}
+
+func (report Report) toEntity() entity.Report {
+	res := entity.Report{
+		Id:             report.ID,
+		ReportedUserId: report.ReportedUserId,
+		Reason:         report.Reason,
+		Status:         entity.ReportStatus(report.Status),
+		CreatedAt:      report.CreatedAt,
+		UpdatedAt:      report.UpdatedAt,
+	}
+	if report.ReporterId != nil {
+		res.ReporterId = *report.ReporterId
+	}
+	if report.ConversationType != nil {
+		res.ConversationType = entity.ConversationType(*report.ConversationType)
+	}
+	if report.MessageId != nil {
+		res.MessageId = *report.MessageId
+	}
+	if report.GroupId != nil {
+		res.GroupId = *report.GroupId
+	}
+	if report.ResolvedBy != nil {
+		res.ResolvedBy = *report.ResolvedBy
+	}
+	return res
+}
@@ -77,3 +77,12 @@ func (r *SessionRepo) DeleteSession(ctx context.Context, uuid uuid.UUID) error {
 	}
 	return nil
 }
+
+// DeleteSessionsByUsername menghapus semua session / refresh token milik user
+func (r *SessionRepo) DeleteSessionsByUsername(ctx context.Context, username string) error {
+	result := r.db.Where(&Session{Username: username}).Delete(&Session{})
+	if err := result.Error; err != nil {
+		return fmt.Errorf("SessionRepo - DeleteSessionsByUsername - r.db.Where(&Session{Username: username}).Delete: %w", err)
+	}
+	return nil
+}
@@ -25,6 +25,8 @@ type User struct {
 	Username       string
 	HashedPassword string
 	Email          string
+	IsAdmin        bool
+	SuspendedAt    *time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Friends        []*User `gorm:"many2many:contacts"`
@@ -33,9 +35,20 @@ type User struct {
 type contact struct {
 	UserID    uuid.UUID
 	FriendId  uuid.UUID
+	Nickname  string
+	Note      string
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	DeletedAt time.Time
+	DeletedAt *time.Time
+}
+
+// contactRow kontak user beserta nickname & catatan pribadi user
+type contactRow struct {
+	Id       uuid.UUID
+	Username string
+	Email    string
+	Nickname string
+	Note     string
 }
 
 func NewUserRepo(db *gorm.DB) *UserRepo {
@@ -47,7 +60,8 @@ func (r *UserRepo) CreateUser(ctx context.Context, e entity.CreateUserRequest) (
 	// e.password sudah dihash
 	var userDb User
 
-	result := r.db.Where(&User{Username: e.Username}).Or(&User{Email: e.Email}).First(&userDb)
+	// username unik case insensitive (unique index lower(username))
+	result := r.db.Where("lower(username) = lower(?)", e.Username).Or(&User{Email: e.Email}).First(&userDb)
 
 	if result.RowsAffected > 0 {
 		//bad request
@@ -83,84 +97,99 @@ func (r *UserRepo) GetUser(ctx context.Context, email string) (entity.GetUser, e
 		Username:       userDb.Username,
 		Email:          userDb.Email,
 		HashedPassword: userDb.HashedPassword,
+		IsAdmin:        userDb.IsAdmin,
+		SuspendedAt:    userDb.SuspendedAt,
 	}
 
 	return user, nil
 }
 
-// AddFriend menambahkan daftar kontak yg dimiliki user
-func (r *UserRepo) AddFriend(ctx context.Context, myUsername string, fUsername string) (entity.UserResponse, error) {
+// GetUserFriends mendpatkan kontak yang dimiliki user, kontak yang sudah dihapus (deleted_at terisi) tidak diikutkan
+func (r *UserRepo) GetUserFriends(ctx context.Context, username string) (entity.UserResponse, error) {
 	var user User
-	var friend User
-	queryResF := r.db.Where(&User{Username: fUsername}).First(&friend)
-	if err := queryResF.Error; err != nil {
-		return entity.UserResponse{}, fmt.Errorf("UserRepo -  AddFriend -  r.db.Where(&User{Username: fUsername}).First(&friend):  %w", err)
-	}
-	queryRes := r.db.Where(&User{Username: myUsername}).First(&user)
+	queryRes := r.db.Where(&User{Username: username}).First(&user)
 	if err := queryRes.Error; err != nil {
-		return entity.UserResponse{}, fmt.Errorf("UserRepo -  AddFriend -  r.db.Where(&User{Username: myUsername}).First(&user):  %w", err)
+		return entity.UserResponse{}, fmt.Errorf("UserRepo - GetUserFriends -  r.db.Where(&User{Username: username}).First(&user): %w", err)
 	}
 
-	var result []contact
-	queryRes = r.db.Raw("SELECT * FROM contacts WHERE user_id=? AND friend_id=? ", user.ID, friend.ID).Scan(&result)
+	var rows []contactRow
+	queryRes = r.contactQuery(user.ID).Order("users.username").Scan(&rows)
 	if err := queryRes.Error; err != nil {
-		return entity.UserResponse{}, fmt.Errorf("UserRepo -  AddFriend - :  r.db.Raw(\"SELECT * FROM contacts WHERE user_id=? AND friend_id=? \", user.ID, friend.ID).Scan(&result): %w", err)
-	}
-	if len(result) > 0 {
-		return entity.UserResponse{}, fmt.Errorf("UserRepo -  AddFriend - :  r.db.Raw(\"SELECT * FROM contacts WHERE user_id=? AND friend_id=? \", user.ID, friend.ID).Scan(&result): %w", AlreadyYourInYourContactErr)
+		return entity.UserResponse{}, fmt.Errorf("UserRepo - GetUserFriends - r.contactQuery: %w", err)
 	}
 
-	user.Friends = append(user.Friends, &friend)
-	friend.Friends = append(friend.Friends, &user)
-	r.db.Save(&user)
-	r.db.Save(&friend)
-
 	var friendRes []entity.UserResponse
-	for _, uFriend := range user.Friends {
-
-		frnd := entity.UserResponse{
-			Id:       uFriend.ID,
-			Username: uFriend.Username,
-			Email:    uFriend.Email,
-		}
-		friendRes = append(friendRes, frnd)
+	for _, row := range rows {
+		friendRes = append(friendRes, row.toEntity())
 	}
 
-	resp := entity.UserResponse{
+	res := entity.UserResponse{
 		Id:       user.ID,
 		Username: user.Username,
 		Email:    user.Email,
 		Friends:  friendRes,
 	}
-	return resp, nil
+	return res, nil
 }
 
-// GetUserFriends mendpatkan kontak yang dimiliki user
-func (r *UserRepo) GetUserFriends(ctx context.Context, username string) (entity.UserResponse, error) {
-	var user User
-	queryRes := r.db.Where(&User{Username: username}).Preload("Friends").First(&user)
+// RemoveContact menghapus kontak user & friend di kedua arah dengan mengisi deleted_at,
+// nickname & catatan pribadi ikut dihapus. Untuk menjadi kontak lagi harus lewat friend request
+func (r *UserRepo) RemoveContact(ctx context.Context, userId uuid.UUID, friendId uuid.UUID) error {
+	queryRes := r.db.Exec("UPDATE contacts SET deleted_at = now(), updated_at = now(), nickname = '', note = '' "+
+		"WHERE ((user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)) AND deleted_at IS NULL",
+		userId, friendId, friendId, userId)
 	if err := queryRes.Error; err != nil {
-		return entity.UserResponse{}, fmt.Errorf("UserRepo - GetUserFriends -  r.db.Where(&User{Username: username}).Preload(\"Friends\").First(&user): %w", err)
+		return fmt.Errorf("UserRepo - RemoveContact - r.db.Exec: %w", err)
+	}
+	if queryRes.RowsAffected == 0 {
+		return fmt.Errorf("UserRepo - RemoveContact - r.db.Exec: %w", ErrNotFoundContactErr)
 	}
+	return nil
+}
 
-	var friendRes []entity.UserResponse
-	for _, uFriend := range user.Friends {
+// UpdateContact mengubah nickname / catatan pribadi user untuk kontaknya, nil berarti tidak diubah
+func (r *UserRepo) UpdateContact(ctx context.Context, userId uuid.UUID, friendId uuid.UUID, nickname *string, note *string) (entity.UserResponse, error) {
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if nickname != nil {
+		updates["nickname"] = *nickname
+	}
+	if note != nil {
+		updates["note"] = *note
+	}
+	queryRes := r.db.Table("contacts").Where("user_id = ? AND friend_id = ? AND deleted_at IS NULL", userId, friendId).Updates(updates)
+	if err := queryRes.Error; err != nil {
+		return entity.UserResponse{}, fmt.Errorf("UserRepo - UpdateContact - r.db.Updates: %w", err)
+	}
+	if queryRes.RowsAffected == 0 {
+		return entity.UserResponse{}, fmt.Errorf("UserRepo - UpdateContact - r.db.Updates: %w", ErrNotFoundContactErr)
+	}
 
-		frnd := entity.UserResponse{
-			Id:       uFriend.ID,
-			Username: uFriend.Username,
-			Email:    uFriend.Email,
-		}
-		friendRes = append(friendRes, frnd)
+	var rows []contactRow
+	if queryRes = r.contactQuery(userId).Where("contacts.friend_id = ?", friendId).Scan(&rows); queryRes.Error != nil {
+		return entity.UserResponse{}, fmt.Errorf("UserRepo - UpdateContact - r.contactQuery: %w", queryRes.Error)
 	}
+	if len(rows) == 0 {
+		return entity.UserResponse{}, fmt.Errorf("UserRepo - UpdateContact - r.contactQuery: %w", ErrNotFoundContactErr)
+	}
+	return rows[0].toEntity(), nil
+}
 
-	res := entity.UserResponse{
-		Id:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Friends:  friendRes,
+// contactQuery kontak aktif milik user beserta nickname & catatan pribadi user
+func (r *UserRepo) contactQuery(userId uuid.UUID) *gorm.DB {
+	return r.db.Table("contacts").
+		Select("users.id, users.username, users.email, contacts.nickname, contacts.note").
+		Joins("JOIN users ON users.id = contacts.friend_id").
+		Where("contacts.user_id = ? AND contacts.deleted_at IS NULL", userId)
+}
+
+func (row contactRow) toEntity() entity.UserResponse {
+	return entity.UserResponse{
+		Id:       row.Id,
+		Username: row.Username,
+		Email:    row.Email,
+		Nickname: row.Nickname,
+		Note:     row.Note,
 	}
-	return res, nil
 }
 
 // GetUserFriend untuk mengecek apakah friendUsername teman dari user
@@ -176,12 +205,12 @@ func (r *UserRepo) GetUserFriend(ctx context.Context, myUsername string, friendU
 		return err
 	}
 	var result []contact
-	queryRes = r.db.Raw("SELECT * FROM contacts WHERE user_id=? AND friend_id=? ", user.ID, friend.ID).Scan(&result)
+	queryRes = r.db.Raw("SELECT * FROM contacts WHERE user_id=? AND friend_id=? AND deleted_at IS NULL", user.ID, friend.ID).Scan(&result)
 	if err := queryRes.Error; err != nil {
 		return err
 	}
 	if len(result) == 0 {
-		return fmt.Errorf("userRepo - GetUserFriend - r.db.Raw(\"SELECT * FROM contacts WHERE user_id=? AND friend_id=? \", user.ID, friend.ID): %w", ErrNotFoundContactErr)
+		return fmt.Errorf("userRepo - GetUserFriend - r.db.Raw(\"SELECT * FROM contacts WHERE user_id=? AND friend_id=? AND deleted_at IS NULL\", user.ID, friend.ID): %w", ErrNotFoundContactErr)
 
 	}
 
@@ -202,6 +231,8 @@ func (r *UserRepo) GetUserByUsername(username string) (entity.GetUser, error) {
 		Username:       userDb.Username,
 		Email:          userDb.Email,
 		HashedPassword: userDb.HashedPassword,
+		IsAdmin:        userDb.IsAdmin,
+		SuspendedAt:    userDb.SuspendedAt,
 	}
 
 	return user, nil
@@ -219,7 +250,42 @@ func (r *UserRepo) GetUserById(userId uuid.UUID) (entity.GetUser, error) {
 		Username:       userDb.Username,
 		Email:          userDb.Email,
 		HashedPassword: userDb.HashedPassword,
+		IsAdmin:        userDb.IsAdmin,
+		SuspendedAt:    userDb.SuspendedAt,
 	}
 
 	return user, nil
 }
+
+// SetUserSuspended suspend / unsuspend akun user
+func (r *UserRepo) SetUserSuspended(ctx context.Context, userId uuid.UUID, suspended bool) error {
+	var suspendedAt *time.Time
+	if suspended {
+		now := time.Now()
+		suspendedAt = &now
+	}
+	if res := r.db.Model(&User{}).Where("id = ?", userId).Update("suspended_at", suspendedAt); res.Error != nil {
+		return fmt.Errorf("UserRepo - SetUserSuspended - r.db.Update: %w", res.Error)
+	}
+	return nil
+}
+
+// GetUsersWithStaleEmailHash mendapatkan user yang email_hash belum dihitung dengan key sekarang (user baru / salt atau secret diganti)
+func (r *UserRepo) GetUsersWithStaleEmailHash(ctx context.Context, key string, limit int) ([]entity.UserEmail, error) {
+	var users []entity.UserEmail
+	if res := r.db.Table("users").Select("users.id AS user_id, users.email").
+		Where("users.deleted_at IS NULL AND users.email_hash_key IS DISTINCT FROM ?", key).
+		Limit(limit).Scan(&users); res.Error != nil {
+		return nil, fmt.Errorf("UserRepo - GetUsersWithStaleEmailHash - r.db.Scan: %w", res.Error)
+	}
+	return users, nil
+}
+
+// SetEmailHash menyimpan hmac hash email user beserta key yang dipakai
+func (r *UserRepo) SetEmailHash(ctx context.Context, userId uuid.UUID, hash string, key string) error {
+	if res := r.db.Model(&User{}).Where("id = ?", userId).
+		UpdateColumns(map[string]interface{}{"email_hash": hash, "email_hash_key": key}); res.Error != nil {
+		return fmt.Errorf("UserRepo - SetEmailHash - r.db.UpdateColumns: %w", res.Error)
+	}
+	return nil
+}
@@ -0,0 +1,462 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+	"time"
+)
+
+type UserProfileRepo struct {
+	db *gorm.DB
+}
+
+type UserPrivacySetting struct {
+	UserId       uuid.UUID
+	Discoverable string
+	OnlineStatus string
+	LastSeen     string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// UserPrivacyException pengecualian setting privacy user untuk target
+type UserPrivacyException struct {
+	UserId    uuid.UUID
+	Setting   string
+	TargetId  uuid.UUID
+	Allow     bool
+	CreatedAt time.Time
+}
+
+// privacyExceptionRow pengecualian setting privacy beserta username target
+type privacyExceptionRow struct {
+	UserId   uuid.UUID
+	Setting  string
+	TargetId uuid.UUID
+	Username string
+	Allow    bool
+}
+
+// lastSeenRow last seen user
+type lastSeenRow struct {
+	Id         uuid.UUID
+	LastSeenAt *time.Time
+}
+
+// UserAvatar avatar yang diupload user
+type UserAvatar struct {
+	UserId      uuid.UUID
+	ContentType string
+	Data        []byte
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// userProfileRow profil publik user
+type userProfileRow struct {
+	Id          uuid.UUID
+	Username    string
+	DisplayName string
+	AvatarUrl   string
+	Bio         string
+	Location    string
+	Website     string
+	LastSeenAt  *time.Time
+}
+
+// emailHashRow user yang hash emailnya cocok dengan hash dari client
+type emailHashRow struct {
+	Id          uuid.UUID
+	Username    string
+	DisplayName string
+	AvatarUrl   string
+	Hash        string
+}
+
+// userSearchRow hasil search user
+type userSearchRow struct {
+	Id          uuid.UUID
+	Username    string
+	DisplayName string
+	AvatarUrl   string
+}
+
+func NewUserProfileRepo(db *gorm.DB) *UserProfileRepo {
+	return &UserProfileRepo{db}
+}
+
+// likeEscaper escape wildcard LIKE di query search
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchUsers search user berdasarkan prefix & trigram similarity username / display name.
+// User yang tidak discoverable oleh viewer, user yang memblokir viewer & user yang di suspend tidak diikutkan.
+// Hasil prefix match diurutkan lebih dulu, lalu berdasarkan similarity
+func (r *UserProfileRepo) SearchUsers(ctx context.Context, viewerId uuid.UUID, query string, limit int) ([]entity.UserSearchResult, error) {
+	query = strings.ToLower(query)
+	prefix := likeEscaper.Replace(query) + "%"
+
+	var rows []userSearchRow
+	res := r.db.Raw(`SELECT users.id, users.username, users.display_name, users.avatar_url
+		FROM users
+		LEFT JOIN user_privacy_settings ups ON ups.user_id = users.id
+		WHERE users.deleted_at IS NULL AND users.suspended_at IS NULL AND users.id <> @viewer
+			AND (lower(users.username) LIKE @prefix OR lower(users.display_name) LIKE @prefix
+				OR lower(users.username) % @query OR lower(users.display_name) % @query)
+			AND (COALESCE(ups.discoverable, 'everyone') = 'everyone'
+				OR (ups.discoverable = 'contacts' AND EXISTS (
+					SELECT 1 FROM contacts WHERE contacts.user_id = users.id AND contacts.friend_id = @viewer AND contacts.deleted_at IS NULL)))
+			AND NOT EXISTS (SELECT 1 FROM user_blocks WHERE user_blocks.blocker_id = users.id AND user_blocks.blocked_id = @viewer)
+		ORDER BY (lower(users.username) LIKE @prefix OR lower(users.display_name) LIKE @prefix) DESC,
+			GREATEST(similarity(lower(users.username), @query), similarity(lower(users.display_name), @query)) DESC,
+			users.username
+		LIMIT @limit`,
+		map[string]interface{}{"viewer": viewerId, "prefix": prefix, "query": query, "limit": limit}).Scan(&rows)
+	if res.Error != nil {
+		return nil, fmt.Errorf("UserProfileRepo - SearchUsers - r.db.Raw: %w", res.Error)
+	}
+
+	users := []entity.UserSearchResult{}
+	for _, row := range rows {
+		users = append(users, entity.UserSearchResult{
+			UserId:      row.Id,
+			Username:    row.Username,
+			DisplayName: row.DisplayName,
+			AvatarUrl:   row.AvatarUrl,
+		})
+	}
+	return users, nil
+}
+
+// FindUsersByEmailHashes mencari user yang users.email_hash (hmac hash email, lihat internal/util/emailhash) ada di hashes.
+// Filter sama dengan SearchUsers: user yang tidak discoverable oleh viewer, memblokir viewer / di suspend tidak diikutkan
+func (r *UserProfileRepo) FindUsersByEmailHashes(ctx context.Context, viewerId uuid.UUID, hashes []string) ([]entity.ContactDiscoveryMatch, error) {
+	matches := []entity.ContactDiscoveryMatch{}
+	if len(hashes) == 0 {
+		return matches, nil
+	}
+
+	var rows []emailHashRow
+	if res := r.db.Table("users").
+		Select("users.id, users.username, users.display_name, users.avatar_url, users.email_hash AS hash").
+		Joins("LEFT JOIN user_privacy_settings ups ON ups.user_id = users.id").
+		Where("users.email_hash IN ? AND users.deleted_at IS NULL AND users.suspended_at IS NULL AND users.id <> ?", hashes, viewerId).
+		Where(`COALESCE(ups.discoverable, 'everyone') = 'everyone' OR (ups.discoverable = 'contacts' AND EXISTS (
+			SELECT 1 FROM contacts WHERE contacts.user_id = users.id AND contacts.friend_id = ? AND contacts.deleted_at IS NULL))`, viewerId).
+		Where("NOT EXISTS (SELECT 1 FROM user_blocks WHERE user_blocks.blocker_id = users.id AND user_blocks.blocked_id = ?)", viewerId).
+		Order("users.username").Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("UserProfileRepo - FindUsersByEmailHashes - r.db.Scan: %w", res.Error)
+	}
+
+	for _, row := range rows {
+		matches = append(matches, entity.ContactDiscoveryMatch{
+			Hash:        row.Hash,
+			UserId:      row.Id,
+			Username:    row.Username,
+			DisplayName: row.DisplayName,
+			AvatarUrl:   row.AvatarUrl,
+		})
+	}
+	return matches, nil
+}
+
+// GetMutualGroups mendapatkan group yang diikuti userId & otherId (join users_group dengan dirinya sendiri)
+func (r *UserProfileRepo) GetMutualGroups(ctx context.Context, userId uuid.UUID, otherId uuid.UUID) ([]entity.MutualGroup, error) {
+	type mutualGroupRow struct {
+		Id        uuid.UUID
+		Name      string
+		AvatarUrl string
+	}
+	var rows []mutualGroupRow
+	if res := r.db.Table("users_group ug1").
+		Select("groups.id, groups.name, groups.avatar_url").
+		Joins("JOIN users_group ug2 ON ug2.group_id = ug1.group_id AND ug2.user_id = ? AND ug2.deleted_at IS NULL", otherId).
+		Joins("JOIN groups ON groups.id = ug1.group_id AND groups.deleted_at IS NULL").
+		Where("ug1.user_id = ? AND ug1.deleted_at IS NULL", userId).
+		Order("groups.name").Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("UserProfileRepo - GetMutualGroups - r.db.Scan: %w", res.Error)
+	}
+
+	groups := []entity.MutualGroup{}
+	for _, row := range rows {
+		groups = append(groups, entity.MutualGroup{
+			Id:        row.Id,
+			Name:      row.Name,
+			AvatarUrl: row.AvatarUrl,
+		})
+	}
+	return groups, nil
+}
+
+// GetMutualContacts mendapatkan kontak userId yang juga kontak otherId (join contacts dengan dirinya sendiri).
+// Kontak yang memblokir userId / di suspend tidak diikutkan
+func (r *UserProfileRepo) GetMutualContacts(ctx context.Context, userId uuid.UUID, otherId uuid.UUID) ([]entity.UserSearchResult, error) {
+	var rows []userSearchRow
+	if res := r.db.Table("contacts c1").
+		Select("users.id, users.username, users.display_name, users.avatar_url").
+		Joins("JOIN contacts c2 ON c2.friend_id = c1.friend_id AND c2.user_id = ? AND c2.deleted_at IS NULL", otherId).
+		Joins("JOIN users ON users.id = c1.friend_id AND users.deleted_at IS NULL AND users.suspended_at IS NULL").
+		Joins("LEFT JOIN user_blocks ON user_blocks.blocker_id = users.id AND user_blocks.blocked_id = ?", userId).
+		Where("c1.user_id = ? AND c1.deleted_at IS NULL AND user_blocks.blocker_id IS NULL", userId).
+		Order("users.username").Scan(&rows); res.Error != nil {
+		return nil, fmt.Errorf("UserProfileRepo - GetMutualContacts - r.db.Scan: %w", res.Error)
+	}
+
+	contacts := []entity.UserSearchResult{}
+	for _, row := range rows {
+		contacts = append(contacts, entity.UserSearchResult{
+			UserId:      row.Id,
+			Username:    row.Username,
+			DisplayName: row.DisplayName,
+			AvatarUrl:   row.AvatarUrl,
+		})
+	}
+	return contacts, nil
+}
+
+// GetProfile mendapatkan profil user by username, setting privacy belum diterapkan
+func (r *UserProfileRepo) GetProfile(ctx context.Context, username string) (entity.PublicProfile, error) {
+	var rows []userProfileRow
+	res := r.db.Table("users").
+		Select("users.id, users.username, users.display_name, users.avatar_url, users.bio, users.location, users.website, users.last_seen_at").
+		Where("users.username = ? AND users.deleted_at IS NULL", username).Scan(&rows)
+	if res.Error != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileRepo - GetProfile - r.db.Scan: %w", res.Error)
+	}
+	if len(rows) == 0 {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileRepo - GetProfile - r.db.Scan: %w", gorm.ErrRecordNotFound)
+	}
+	row := rows[0]
+	return entity.PublicProfile{
+		UserId:      row.Id,
+		Username:    row.Username,
+		DisplayName: row.DisplayName,
+		AvatarUrl:   row.AvatarUrl,
+		Bio:         row.Bio,
+		Location:    row.Location,
+		Website:     row.Website,
+		LastSeenAt:  row.LastSeenAt,
+	}, nil
+}
+
+// UpdateProfile mengubah field profil user, field nil tidak diubah
+func (r *UserProfileRepo) UpdateProfile(ctx context.Context, userId uuid.UUID, e entity.UpdateProfileReqUc) error {
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if e.DisplayName != nil {
+		updates["display_name"] = *e.DisplayName
+	}
+	if e.Bio != nil {
+		updates["bio"] = *e.Bio
+	}
+	if e.Location != nil {
+		updates["location"] = *e.Location
+	}
+	if e.Website != nil {
+		updates["website"] = *e.Website
+	}
+	res := r.db.Table("users").Where("id = ? AND deleted_at IS NULL", userId).Updates(updates)
+	if res.Error != nil {
+		return fmt.Errorf("UserProfileRepo - UpdateProfile - r.db.Updates: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("UserProfileRepo - UpdateProfile - r.db.Updates: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// SetAvatar menyimpan avatar user (upsert by user_id) & mengubah avatar_url user dalam 1 transaction
+func (r *UserProfileRepo) SetAvatar(ctx context.Context, userId uuid.UUID, avatar entity.Avatar, avatarUrl string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		userAvatar := UserAvatar{
+			UserId:      userId,
+			ContentType: avatar.ContentType,
+			Data:        avatar.Data,
+			CreatedAt:   avatar.UpdatedAt,
+			UpdatedAt:   avatar.UpdatedAt,
+		}
+		res := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"content_type", "data", "updated_at"}),
+		}).Create(&userAvatar)
+		if res.Error != nil {
+			return res.Error
+		}
+		return tx.Table("users").Where("id = ?", userId).
+			Updates(map[string]interface{}{"avatar_url": avatarUrl, "updated_at": time.Now()}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("UserProfileRepo - SetAvatar - r.db.Transaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveAvatar menghapus avatar user & mengosongkan avatar_url user
+func (r *UserProfileRepo) RemoveAvatar(ctx context.Context, userId uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&UserAvatar{}).Error; err != nil {
+			return err
+		}
+		return tx.Table("users").Where("id = ?", userId).
+			Updates(map[string]interface{}{"avatar_url": "", "updated_at": time.Now()}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("UserProfileRepo - RemoveAvatar - r.db.Transaction: %w", err)
+	}
+	return nil
+}
+
+// GetAvatar mendapatkan avatar yang diupload user by username
+func (r *UserProfileRepo) GetAvatar(ctx context.Context, username string) (entity.Avatar, error) {
+	var avatars []UserAvatar
+	res := r.db.Table("user_avatars").
+		Select("user_avatars.*").
+		Joins("JOIN users ON users.id = user_avatars.user_id").
+		Where("users.username = ? AND users.deleted_at IS NULL", username).
+		Limit(1).Scan(&avatars)
+	if res.Error != nil {
+		return entity.Avatar{}, fmt.Errorf("UserProfileRepo - GetAvatar - r.db.Scan: %w", res.Error)
+	}
+	if len(avatars) == 0 {
+		return entity.Avatar{}, fmt.Errorf("UserProfileRepo - GetAvatar - r.db.Scan: %w", gorm.ErrRecordNotFound)
+	}
+	return entity.Avatar{
+		ContentType: avatars[0].ContentType,
+		Data:        avatars[0].Data,
+		UpdatedAt:   avatars[0].UpdatedAt,
+	}, nil
+}
+
+// GetPrivacySettings mendapatkan setting privacy user, setting default jika user belum pernah mengubahnya
+func (r *UserProfileRepo) GetPrivacySettings(ctx context.Context, userId uuid.UUID) (entity.PrivacySettings, error) {
+	settings, err := r.GetPrivacySettingsByUserIds(ctx, []uuid.UUID{userId})
+	if err != nil {
+		return entity.PrivacySettings{}, fmt.Errorf("UserProfileRepo - GetPrivacySettings - r.GetPrivacySettingsByUserIds: %w", err)
+	}
+	return settings[userId], nil
+}
+
+// GetPrivacySettingsByUserIds mendapatkan setting privacy & pengecualiannya untuk banyak user dalam 2 query,
+// user yang belum pernah mengubah settingnya mendapat setting default
+func (r *UserProfileRepo) GetPrivacySettingsByUserIds(ctx context.Context, userIds []uuid.UUID) (map[uuid.UUID]entity.PrivacySettings, error) {
+	res := make(map[uuid.UUID]entity.PrivacySettings, len(userIds))
+	if len(userIds) == 0 {
+		return res, nil
+	}
+	for _, userId := range userIds {
+		res[userId] = entity.DefaultPrivacySettings()
+	}
+
+	var settings []UserPrivacySetting
+	if err := r.db.Where("user_id IN ?", userIds).Find(&settings).Error; err != nil {
+		return nil, fmt.Errorf("UserProfileRepo - GetPrivacySettingsByUserIds - r.db.Find: %w", err)
+	}
+	for _, setting := range settings {
+		res[setting.UserId] = entity.PrivacySettings{
+			Discoverable: entity.PrivacyVisibility(setting.Discoverable),
+			OnlineStatus: entity.PrivacyVisibility(setting.OnlineStatus),
+			LastSeen:     entity.PrivacyVisibility(setting.LastSeen),
+			UpdatedAt:    setting.UpdatedAt,
+		}
+	}
+
+	var exceptions []privacyExceptionRow
+	err := r.db.Table("user_privacy_exceptions upe").
+		Select("upe.user_id, upe.setting, upe.target_id, users.username, upe.allow").
+		Joins("JOIN users ON users.id = upe.target_id").
+		Where("upe.user_id IN ?", userIds).
+		Order("users.username").Scan(&exceptions).Error
+	if err != nil {
+		return nil, fmt.Errorf("UserProfileRepo - GetPrivacySettingsByUserIds - r.db.Scan: %w", err)
+	}
+	for _, row := range exceptions {
+		setting := res[row.UserId]
+		exception := entity.PrivacyException{UserId: row.TargetId, Username: row.Username, Allow: row.Allow}
+		switch entity.PrivacySetting(row.Setting) {
+		case entity.PrivacySettingOnlineStatus:
+			setting.OnlineStatusExceptions = append(setting.OnlineStatusExceptions, exception)
+		case entity.PrivacySettingLastSeen:
+			setting.LastSeenExceptions = append(setting.LastSeenExceptions, exception)
+		}
+		res[row.UserId] = setting
+	}
+	return res, nil
+}
+
+// SetPrivacySettings menyimpan setting privacy user (upsert by user_id), pengecualian tidak diubah
+func (r *UserProfileRepo) SetPrivacySettings(ctx context.Context, userId uuid.UUID, settings entity.PrivacySettings) error {
+	setting := UserPrivacySetting{
+		UserId:       userId,
+		Discoverable: string(settings.Discoverable),
+		OnlineStatus: string(settings.OnlineStatus),
+		LastSeen:     string(settings.LastSeen),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	res := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"discoverable", "online_status", "last_seen", "updated_at"}),
+	}).Create(&setting)
+	if res.Error != nil {
+		return fmt.Errorf("UserProfileRepo - SetPrivacySettings - r.db.Create: %w", res.Error)
+	}
+	return nil
+}
+
+// SetPrivacyExceptions mengganti semua pengecualian 1 setting privacy user dalam 1 transaction
+func (r *UserProfileRepo) SetPrivacyExceptions(ctx context.Context, userId uuid.UUID, setting entity.PrivacySetting,
+	exceptions []entity.PrivacyException) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND setting = ?", userId, string(setting)).Delete(&UserPrivacyException{}).Error; err != nil {
+			return err
+		}
+		if len(exceptions) == 0 {
+			return nil
+		}
+		rows := make([]UserPrivacyException, len(exceptions))
+		for i, exception := range exceptions {
+			rows[i] = UserPrivacyException{
+				UserId:    userId,
+				Setting:   string(setting),
+				TargetId:  exception.UserId,
+				Allow:     exception.Allow,
+				CreatedAt: time.Now(),
+			}
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return fmt.Errorf("UserProfileRepo - SetPrivacyExceptions - r.db.Transaction: %w", err)
+	}
+	return nil
+}
+
+// SetLastSeen menyimpan waktu terakhir user online (koneksi websocket terakhir user ditutup)
+func (r *UserProfileRepo) SetLastSeen(ctx context.Context, userId uuid.UUID, lastSeenAt time.Time) error {
+	res := r.db.Table("users").Where("id = ?", userId).Update("last_seen_at", lastSeenAt)
+	if res.Error != nil {
+		return fmt.Errorf("UserProfileRepo - SetLastSeen - r.db.Update: %w", res.Error)
+	}
+	return nil
+}
+
+// GetLastSeen mendapatkan last seen banyak user, user yang belum pernah offline tidak ada di map
+func (r *UserProfileRepo) GetLastSeen(ctx context.Context, userIds []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	res := make(map[uuid.UUID]time.Time, len(userIds))
+	if len(userIds) == 0 {
+		return res, nil
+	}
+	var rows []lastSeenRow
+	err := r.db.Table("users").Select("id, last_seen_at").
+		Where("id IN ? AND last_seen_at IS NOT NULL", userIds).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("UserProfileRepo - GetLastSeen - r.db.Scan: %w", err)
+	}
+	for _, row := range rows {
+		res[row.Id] = *row.LastSeenAt
+	}
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+)
+
+// sendTyping meneruskan typing indicator ke friend (semua device) atau ke member group yang online.
+// Typing indicator di group yang masuk mode large group tidak di fanout
+func (u *User) sendTyping(msgWs *entity.MessageWs) {
+	typing := &msgWs.MsgTyping
+	typing.SenderUsername = u.Name
+	typing.RecipientUsernames = nil
+
+	switch {
+	case typing.FriendUsername != "":
+		if err := u.Chat.userPg.GetUserFriend(context.Background(), u.Name, typing.FriendUsername); err != nil {
+			return
+		}
+		friend, err := u.Chat.userPg.GetUserByUsername(typing.FriendUsername)
+		if err != nil {
+			return
+		}
+		// typing dari user yang diblokir friend tidak dikirim, termasuk jika status blokir tidak bisa dicek
+		userId, _ := uuid.Parse(u.UserId)
+		if blocked, err := u.Chat.isBlocked(friend.Id, userId); err != nil || blocked {
+			return
+		}
+		typing.RecipientUsername = friend.Username
+		u.Chat.sendToUserSessions(friend.Id.String(), msgWs)
+	case typing.GroupId != uuid.Nil:
+		userId, _ := uuid.Parse(u.UserId)
+		if _, err := u.Chat.gpRepo.GetGroupById(typing.GroupId, userId); err != nil {
+			return
+		}
+		members, err := groupRecipients(u.Chat.gpRepo, u.Chat.gRedis, typing.GroupId)
+		if err != nil {
+			log.Println("sendTyping - groupRecipients: ", err)
+			return
+		}
+		if u.Chat.groupLimits.IsLargeGroup(int64(len(members))) {
+			return
+		}
+		typing.RecipientUsername = ""
+		for server, recipients := range u.Chat.recipientsByServer(members, u.Name) {
+			msgServer := *msgWs
+			for _, recipient := range recipients {
+				msgServer.MsgTyping.RecipientUsernames = append(msgServer.MsgTyping.RecipientUsernames, recipient.Username)
+			}
+			u.Chat.sendToServer(server, &msgServer)
+		}
+	}
+}
+
+// deliverTyping mengirim typing indicator ke recipient yang terhubung ke chat-server ini
+func (c *ChatHub) deliverTyping(message *entity.MessageWs) {
+	rcpTyping := make(map[string]bool)
+	if message.MsgTyping.RecipientUsername != "" {
+		rcpTyping[message.MsgTyping.RecipientUsername] = true
+	}
+	for _, username := range message.MsgTyping.RecipientUsernames {
+		rcpTyping[username] = true
+	}
+
+	for _, user := range c.us {
+		if !rcpTyping[user.Name] {
+			continue
+		}
+		msgUser := *message
+		msgUser.MsgTyping.RecipientUsername = user.Name
+		msgUser.MsgTyping.RecipientUsernames = nil
+		select {
+		case user.inbox <- &msgUser:
+		}
+	}
+}
@@ -0,0 +1,506 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lintangbs/chat-be/internal/entity"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	SearchQueryTooShortErr     = errors.New("search query must be at least 2 characters")
+	InvalidPrivacySettingErr   = errors.New("privacy setting must be everyone, contacts or nobody")
+	InvalidDisplayNameErr      = errors.New("display name can not be longer than 64 characters")
+	InvalidBioErr              = errors.New("bio can not be longer than 500 characters")
+	InvalidLocationErr         = errors.New("location can not be longer than 64 characters")
+	InvalidWebsiteErr          = errors.New("website must be an http or https url of at most 200 characters")
+	AvatarTooLargeErr          = errors.New("avatar file is too large")
+	InvalidAvatarTypeErr       = errors.New("avatar must be a jpeg, png, gif or webp image")
+	InvalidUserStatusErr       = errors.New("status presence must be available, away, busy or dnd")
+	UserStatusTextTooLongErr   = errors.New("status text can not be longer than 100 characters")
+	InvalidStatusExpiryErr     = errors.New("status expiry must be between 0 and 30 days")
+	InvalidPrivacyExceptionErr = errors.New("privacy exceptions must be other users, listed once and at most 100 per setting")
+	MutualWithYourselfErr      = errors.New("can not get mutual groups and contacts with yourself")
+)
+
+const (
+	minSearchQueryLength = 2
+	maxSearchQueryLength = 64
+	defaultSearchLimit   = 20
+	maxSearchLimit       = 50
+	maxDisplayNameLength = 64
+	maxBioLength         = 500
+	maxLocationLength    = 64
+	maxWebsiteLength     = 200
+	maxPrivacyExceptions = 100
+	maxStatusTextLength  = 100
+)
+
+// MaxStatusExpiry batas waktu kadaluarsa status user
+const MaxStatusExpiry = 30 * 24 * time.Hour
+
+// avatarContentTypes content type avatar yang bisa diupload (hasil http.DetectContentType)
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// UserProfileUseCase search user, profil publik, ubah profil & avatar, status user, setting privacy user
+type UserProfileUseCase struct {
+	profileRepo UserProfileRepo
+	uRepo       UserRepo
+	blockRepo   BlockRepo
+	pubSub      PubSubRedis
+	usrRedis    UserRedisRepo
+	fanout      PresenceFanout
+	limits      entity.ProfileLimits
+}
+
+func NewUserProfileUseCase(profileRepo UserProfileRepo, uRepo UserRepo, blockRepo BlockRepo, pubSub PubSubRedis,
+	usrRedis UserRedisRepo, fanout PresenceFanout, limits entity.ProfileLimits) *UserProfileUseCase {
+	return &UserProfileUseCase{
+		profileRepo: profileRepo,
+		uRepo:       uRepo,
+		blockRepo:   blockRepo,
+		pubSub:      pubSub,
+		usrRedis:    usrRedis,
+		fanout:      fanout,
+		limits:      limits,
+	}
+}
+
+// SearchUsers search user berdasarkan prefix & kemiripan username / display name,
+// hanya user yang discoverable oleh user login yang dikembalikan
+func (uc *UserProfileUseCase) SearchUsers(ctx context.Context, e entity.UserSearchReqUc) ([]entity.UserSearchResult, error) {
+	query := strings.TrimSpace(e.Query)
+	if utf8.RuneCountInString(query) < minSearchQueryLength {
+		return nil, fmt.Errorf("UserProfileUseCase - SearchUsers: %w", SearchQueryTooShortErr)
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		query = string([]rune(query)[:maxSearchQueryLength])
+	}
+	limit := e.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return nil, fmt.Errorf("UserProfileUseCase - SearchUsers - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	users, err := uc.profileRepo.SearchUsers(ctx, userLogin.Id, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("UserProfileUseCase - SearchUsers - uc.profileRepo.SearchUsers: %w", err)
+	}
+	return users, nil
+}
+
+// GetProfile mendapatkan profil publik user, last seen disembunyikan sesuai setting privacy pemilik profil.
+// Last seen juga disembunyikan dari user yang diblokir pemilik profil
+func (uc *UserProfileUseCase) GetProfile(ctx context.Context, e entity.PublicProfileReqUc) (entity.PublicProfile, error) {
+	viewer, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - GetProfile - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	profile, err := uc.profileRepo.GetProfile(ctx, e.ProfileUsername)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - GetProfile - uc.profileRepo.GetProfile: %w", err)
+	}
+	if profile.UserId == viewer.Id {
+		return profile, nil
+	}
+
+	// viewer adalah kontak pemilik profil
+	profile.IsContact = uc.uRepo.GetUserFriend(ctx, profile.Username, viewer.Username) == nil
+
+	settings, err := uc.profileRepo.GetPrivacySettings(ctx, profile.UserId)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - GetProfile - uc.profileRepo.GetPrivacySettings: %w", err)
+	}
+	blocked, err := uc.blockRepo.IsBlocked(ctx, profile.UserId, viewer.Id)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - GetProfile - uc.blockRepo.IsBlocked: %w", err)
+	}
+	if blocked || !settings.CanSeeLastSeen(viewer.Id, profile.IsContact) {
+		profile.LastSeenAt = nil
+	}
+	return profile, nil
+}
+
+// GetMutual mendapatkan group & kontak yang sama antara user login & user lain.
+// Jika user lain memblokir user login, hasilnya kosong
+func (uc *UserProfileUseCase) GetMutual(ctx context.Context, e entity.PublicProfileReqUc) (entity.Mutual, error) {
+	viewer, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.Mutual{}, fmt.Errorf("UserProfileUseCase - GetMutual - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	profile, err := uc.profileRepo.GetProfile(ctx, e.ProfileUsername)
+	if err != nil {
+		return entity.Mutual{}, fmt.Errorf("UserProfileUseCase - GetMutual - uc.profileRepo.GetProfile: %w", err)
+	}
+	if profile.UserId == viewer.Id {
+		return entity.Mutual{}, fmt.Errorf("UserProfileUseCase - GetMutual: %w", MutualWithYourselfErr)
+	}
+
+	mutual := entity.Mutual{Groups: []entity.MutualGroup{}, Contacts: []entity.UserSearchResult{}}
+	blocked, err := uc.blockRepo.IsBlocked(ctx, profile.UserId, viewer.Id)
+	if err != nil {
+		return entity.Mutual{}, fmt.Errorf("UserProfileUseCase - GetMutual - uc.blockRepo.IsBlocked: %w", err)
+	}
+	if blocked {
+		return mutual, nil
+	}
+
+	mutual.Groups, err = uc.profileRepo.GetMutualGroups(ctx, viewer.Id, profile.UserId)
+	if err != nil {
+		return entity.Mutual{}, fmt.Errorf("UserProfileUseCase - GetMutual - uc.profileRepo.GetMutualGroups: %w", err)
+	}
+	mutual.Contacts, err = uc.profileRepo.GetMutualContacts(ctx, viewer.Id, profile.UserId)
+	if err != nil {
+		return entity.Mutual{}, fmt.Errorf("UserProfileUseCase - GetMutual - uc.profileRepo.GetMutualContacts: %w", err)
+	}
+	return mutual, nil
+}
+
+// GetPrivacySettings mendapatkan setting privacy user login
+func (uc *UserProfileUseCase) GetPrivacySettings(ctx context.Context, username string) (entity.PrivacySettings, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - GetPrivacySettings - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	settings, err := uc.profileRepo.GetPrivacySettings(ctx, userLogin.Id)
+	if err != nil {
+		return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - GetPrivacySettings - uc.profileRepo.GetPrivacySettings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdatePrivacySettings mengubah setting privacy user login, setting yang kosong tidak diubah.
+// Pengecualian yang dikirim mengganti semua pengecualian setting tersebut
+// Kontak yang kehilangan izin melihat status online / last seen user langsung menerima status offline
+func (uc *UserProfileUseCase) UpdatePrivacySettings(ctx context.Context, e entity.PrivacySettingsReqUc) (entity.PrivacySettings, error) {
+	for _, v := range []entity.PrivacyVisibility{e.Discoverable, e.OnlineStatus, e.LastSeen} {
+		if v != "" && !v.Valid() {
+			return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings: %w", InvalidPrivacySettingErr)
+		}
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	settings, err := uc.profileRepo.GetPrivacySettings(ctx, userLogin.Id)
+	if err != nil {
+		return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings - uc.profileRepo.GetPrivacySettings: %w", err)
+	}
+	// setting sebelum diubah, untuk mencari kontak yang kehilangan izin melihat status online user
+	before := settings
+	if e.Discoverable != "" {
+		settings.Discoverable = e.Discoverable
+	}
+	if e.OnlineStatus != "" {
+		settings.OnlineStatus = e.OnlineStatus
+	}
+	if e.LastSeen != "" {
+		settings.LastSeen = e.LastSeen
+	}
+	settings.UpdatedAt = time.Now()
+
+	// validasi semua pengecualian dulu sebelum menyimpan apapun
+	var onlineStatusExceptions, lastSeenExceptions []entity.PrivacyException
+	if e.OnlineStatusExceptions != nil {
+		if onlineStatusExceptions, err = uc.privacyExceptions(userLogin, *e.OnlineStatusExceptions); err != nil {
+			return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings - uc.privacyExceptions: %w", err)
+		}
+	}
+	if e.LastSeenExceptions != nil {
+		if lastSeenExceptions, err = uc.privacyExceptions(userLogin, *e.LastSeenExceptions); err != nil {
+			return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings - uc.privacyExceptions: %w", err)
+		}
+	}
+
+	if err = uc.profileRepo.SetPrivacySettings(ctx, userLogin.Id, settings); err != nil {
+		return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings - uc.profileRepo.SetPrivacySettings: %w", err)
+	}
+	if e.OnlineStatusExceptions != nil {
+		err = uc.profileRepo.SetPrivacyExceptions(ctx, userLogin.Id, entity.PrivacySettingOnlineStatus, onlineStatusExceptions)
+		if err != nil {
+			return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings - uc.profileRepo.SetPrivacyExceptions: %w", err)
+		}
+		settings.OnlineStatusExceptions = onlineStatusExceptions
+	}
+	if e.LastSeenExceptions != nil {
+		err = uc.profileRepo.SetPrivacyExceptions(ctx, userLogin.Id, entity.PrivacySettingLastSeen, lastSeenExceptions)
+		if err != nil {
+			return entity.PrivacySettings{}, fmt.Errorf("UserProfileUseCase - UpdatePrivacySettings - uc.profileRepo.SetPrivacyExceptions: %w", err)
+		}
+		settings.LastSeenExceptions = lastSeenExceptions
+	}
+
+	uc.fanout.FanoutPrivacyChange(userLogin.Username, before)
+	return settings, nil
+}
+
+// privacyExceptions mengubah username pengecualian menjadi user, user tidak boleh mengecualikan dirinya sendiri
+// dan 1 user hanya boleh ada sekali di always_share / never_share
+func (uc *UserProfileUseCase) privacyExceptions(userLogin entity.GetUser, e entity.PrivacyExceptionsReqUc) ([]entity.PrivacyException, error) {
+	if len(e.AlwaysShare)+len(e.NeverShare) > maxPrivacyExceptions {
+		return nil, InvalidPrivacyExceptionErr
+	}
+	exceptions := []entity.PrivacyException{}
+	seen := make(map[string]bool)
+	add := func(usernames []string, allow bool) error {
+		for _, username := range usernames {
+			if username == userLogin.Username || seen[username] {
+				return InvalidPrivacyExceptionErr
+			}
+			seen[username] = true
+			user, err := uc.uRepo.GetUserByUsername(username)
+			if err != nil {
+				return err
+			}
+			exceptions = append(exceptions, entity.PrivacyException{UserId: user.Id, Username: user.Username, Allow: allow})
+		}
+		return nil
+	}
+	if err := add(e.AlwaysShare, true); err != nil {
+		return nil, err
+	}
+	if err := add(e.NeverShare, false); err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
+
+// UpdateProfile mengubah profil user login, field nil tidak diubah.
+// Profil baru dikirim ke kontak user yang sedang online
+func (uc *UserProfileUseCase) UpdateProfile(ctx context.Context, e entity.UpdateProfileReqUc) (entity.PublicProfile, error) {
+	if e.DisplayName != nil {
+		displayName := strings.TrimSpace(*e.DisplayName)
+		e.DisplayName = &displayName
+	}
+	if err := validateProfile(e); err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UpdateProfile: %w", err)
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UpdateProfile - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if err = uc.profileRepo.UpdateProfile(ctx, userLogin.Id, e); err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UpdateProfile - uc.profileRepo.UpdateProfile: %w", err)
+	}
+	profile, err := uc.profileRepo.GetProfile(ctx, userLogin.Username)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UpdateProfile - uc.profileRepo.GetProfile: %w", err)
+	}
+
+	uc.notifyProfileUpdated(ctx, profile)
+	return profile, nil
+}
+
+// AvatarMaxSize ukuran maksimal file avatar dalam byte, 0 berarti tidak dibatasi
+func (uc *UserProfileUseCase) AvatarMaxSize() int64 {
+	return uc.limits.AvatarMaxSize
+}
+
+// UploadAvatar menyimpan avatar baru user login, avatar_url user diarahkan ke endpoint avatar user.
+// Profil baru dikirim ke kontak user yang sedang online
+func (uc *UserProfileUseCase) UploadAvatar(ctx context.Context, e entity.AvatarUploadReqUc) (entity.PublicProfile, error) {
+	if uc.limits.AvatarMaxSize > 0 && int64(len(e.Data)) > uc.limits.AvatarMaxSize {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UploadAvatar: %w", AvatarTooLargeErr)
+	}
+	contentType := http.DetectContentType(e.Data)
+	if len(e.Data) == 0 || !avatarContentTypes[contentType] {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UploadAvatar: %w", InvalidAvatarTypeErr)
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UploadAvatar - uc.uRepo.GetUserByUsername: %w", err)
+	}
+
+	avatar := entity.Avatar{ContentType: contentType, Data: e.Data, UpdatedAt: time.Now()}
+	// query v agar cache client / cdn tidak memakai avatar lama
+	avatarUrl := fmt.Sprintf("/v1/users/%s/avatar?v=%d", userLogin.Username, avatar.UpdatedAt.Unix())
+	if err = uc.profileRepo.SetAvatar(ctx, userLogin.Id, avatar, avatarUrl); err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UploadAvatar - uc.profileRepo.SetAvatar: %w", err)
+	}
+	profile, err := uc.profileRepo.GetProfile(ctx, userLogin.Username)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - UploadAvatar - uc.profileRepo.GetProfile: %w", err)
+	}
+
+	uc.notifyProfileUpdated(ctx, profile)
+	return profile, nil
+}
+
+// RemoveAvatar menghapus avatar user login. Profil baru dikirim ke kontak user yang sedang online
+func (uc *UserProfileUseCase) RemoveAvatar(ctx context.Context, username string) (entity.PublicProfile, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - RemoveAvatar - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if err = uc.profileRepo.RemoveAvatar(ctx, userLogin.Id); err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - RemoveAvatar - uc.profileRepo.RemoveAvatar: %w", err)
+	}
+	profile, err := uc.profileRepo.GetProfile(ctx, userLogin.Username)
+	if err != nil {
+		return entity.PublicProfile{}, fmt.Errorf("UserProfileUseCase - RemoveAvatar - uc.profileRepo.GetProfile: %w", err)
+	}
+
+	uc.notifyProfileUpdated(ctx, profile)
+	return profile, nil
+}
+
+// GetAvatar mendapatkan avatar yang diupload user
+func (uc *UserProfileUseCase) GetAvatar(ctx context.Context, username string) (entity.Avatar, error) {
+	avatar, err := uc.profileRepo.GetAvatar(ctx, username)
+	if err != nil {
+		return entity.Avatar{}, fmt.Errorf("UserProfileUseCase - GetAvatar - uc.profileRepo.GetAvatar: %w", err)
+	}
+	return avatar, nil
+}
+
+// GetStatus mendapatkan status (away / busy / dnd & teks status) user login
+func (uc *UserProfileUseCase) GetStatus(ctx context.Context, username string) (entity.UserStatus, error) {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return entity.UserStatus{}, fmt.Errorf("UserProfileUseCase - GetStatus - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	status, err := uc.usrRedis.GetUserStatus(userLogin.Id.String())
+	if err != nil {
+		return entity.UserStatus{}, fmt.Errorf("UserProfileUseCase - GetStatus - uc.usrRedis.GetUserStatus: %w", err)
+	}
+	return status, nil
+}
+
+// SetStatus mengubah status user login, status dengan ExpiresIn otomatis kembali ke available setelah kadaluarsa.
+// Status baru di fanout ke semua kontak user
+func (uc *UserProfileUseCase) SetStatus(ctx context.Context, e entity.UserStatusReqUc) (entity.UserStatus, error) {
+	if e.Presence == "" {
+		e.Presence = entity.PresenceAvailable
+	}
+	e.Text = strings.TrimSpace(e.Text)
+	switch {
+	case !e.Presence.Valid():
+		return entity.UserStatus{}, fmt.Errorf("UserProfileUseCase - SetStatus: %w", InvalidUserStatusErr)
+	case utf8.RuneCountInString(e.Text) > maxStatusTextLength:
+		return entity.UserStatus{}, fmt.Errorf("UserProfileUseCase - SetStatus: %w", UserStatusTextTooLongErr)
+	case e.ExpiresIn < 0 || e.ExpiresIn > MaxStatusExpiry:
+		return entity.UserStatus{}, fmt.Errorf("UserProfileUseCase - SetStatus: %w", InvalidStatusExpiryErr)
+	}
+	userLogin, err := uc.uRepo.GetUserByUsername(e.UserName)
+	if err != nil {
+		return entity.UserStatus{}, fmt.Errorf("UserProfileUseCase - SetStatus - uc.uRepo.GetUserByUsername: %w", err)
+	}
+
+	status := entity.UserStatus{Presence: e.Presence, Text: e.Text}
+	if e.ExpiresIn > 0 && !status.IsDefault() {
+		expiresAt := time.Now().Add(e.ExpiresIn)
+		status.ExpiresAt = &expiresAt
+	}
+	if err = uc.usrRedis.SetUserStatus(userLogin.Id.String(), status); err != nil {
+		return entity.UserStatus{}, fmt.Errorf("UserProfileUseCase - SetStatus - uc.usrRedis.SetUserStatus: %w", err)
+	}
+
+	uc.fanout.FanoutUserStatus(userLogin.Username)
+	return status, nil
+}
+
+// ClearStatus menghapus status user login (kembali ke available), di fanout ke semua kontak user
+func (uc *UserProfileUseCase) ClearStatus(ctx context.Context, username string) error {
+	userLogin, err := uc.uRepo.GetUserByUsername(username)
+	if err != nil {
+		return fmt.Errorf("UserProfileUseCase - ClearStatus - uc.uRepo.GetUserByUsername: %w", err)
+	}
+	if err = uc.usrRedis.ClearUserStatus(userLogin.Id.String()); err != nil {
+		return fmt.Errorf("UserProfileUseCase - ClearStatus - uc.usrRedis.ClearUserStatus: %w", err)
+	}
+
+	uc.fanout.FanoutUserStatus(userLogin.Username)
+	return nil
+}
+
+// validateProfile cek panjang field profil & url website
+func validateProfile(e entity.UpdateProfileReqUc) error {
+	switch {
+	case e.DisplayName != nil && utf8.RuneCountInString(*e.DisplayName) > maxDisplayNameLength:
+		return InvalidDisplayNameErr
+	case e.Bio != nil && utf8.RuneCountInString(*e.Bio) > maxBioLength:
+		return InvalidBioErr
+	case e.Location != nil && utf8.RuneCountInString(*e.Location) > maxLocationLength:
+		return InvalidLocationErr
+	case e.Website != nil && *e.Website != "" && (len(*e.Website) > maxWebsiteLength || !isHttpUrl(*e.Website)):
+		return InvalidWebsiteErr
+	}
+	return nil
+}
+
+// notifyProfileUpdated mengirim profil baru user ke semua koneksi websocket kontak user yang sedang online,
+// kontak yang diblokir user tidak dikirimi
+func (uc *UserProfileUseCase) notifyProfileUpdated(ctx context.Context, profile entity.PublicProfile) {
+	user, err := uc.uRepo.GetUserFriends(ctx, profile.Username)
+	if err != nil {
+		log.Println("UserProfileUseCase - notifyProfileUpdated - uc.uRepo.GetUserFriends: ", err)
+		return
+	}
+	blockedUsers, err := uc.blockRepo.GetBlockedUsers(ctx, profile.UserId)
+	if err != nil {
+		log.Println("UserProfileUseCase - notifyProfileUpdated - uc.blockRepo.GetBlockedUsers: ", err)
+	}
+	blocked := make(map[string]bool, len(blockedUsers))
+	for _, blockedUser := range blockedUsers {
+		blocked[blockedUser.Username] = true
+	}
+
+	var contacts []entity.UserResponse
+	var contactIds []string
+	for _, friend := range user.Friends {
+		if blocked[friend.Username] {
+			continue
+		}
+		contacts = append(contacts, friend)
+		contactIds = append(contactIds, friend.Id.String())
+	}
+	presences, err := uc.usrRedis.GetUsersPresence(contactIds)
+	if err != nil {
+		log.Println("UserProfileUseCase - notifyProfileUpdated - uc.usrRedis.GetUsersPresence: ", err)
+		return
+	}
+
+	for i, contact := range contacts {
+		if !presences[i].Online {
+			continue
+		}
+		msgWs := &entity.MessageWs{
+			Type: entity.MessageTypeProfileUpdated,
+			MsgProfileUpdated: entity.MessageProfileUpdated{
+				Username:          profile.Username,
+				DisplayName:       profile.DisplayName,
+				AvatarUrl:         profile.AvatarUrl,
+				Bio:               profile.Bio,
+				Location:          profile.Location,
+				Website:           profile.Website,
+				RecipientUsername: contact.Username,
+			},
+		}
+		servers, err := uc.usrRedis.GetUserSessionServers(contact.Id.String())
+		if err != nil {
+			log.Println("UserProfileUseCase - notifyProfileUpdated - uc.usrRedis.GetUserSessionServers: ", err)
+			continue
+		}
+		for _, server := range servers {
+			if err = uc.pubSub.PublishToChannel(server, msgWs); err != nil {
+				log.Println("UserProfileUseCase - notifyProfileUpdated - uc.pubSub.PublishToChannel: ", err)
+			}
+		}
+	}
+}
@@ -38,7 +38,10 @@ func (api *EdenAIAPI) GenerateText(text string) (string, error) {
 	req.Header.Add("content-type", "application/json")
 	req.Header.Add("authorization", "Bearer "+api.apiKey)
 
-	res, _ := http.DefaultClient.Do(req)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("http.DefaultClient.Do: %w", ErrorRequest)
+	}
 
 	defer res.Body.Close()
 	body, _ := io.ReadAll(res.Body)
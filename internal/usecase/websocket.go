@@ -49,6 +49,9 @@ func (uc *WebsocketUseCase) WebsocketHandler(w http.ResponseWriter, r *http.Requ
 		// Tell the user its not authorized
 		return WebsocketUnauthorizedError
 	}
+	if isSuspended(userDb) {
+		return WebsocketUnauthorizedError
+	}
 
 	err = uc.otpRepo.GetOtp(otp, ctx, username)
 	if err != nil {
@@ -0,0 +1,310 @@
+// Package contentfilter runs outgoing chat messages through a configurable
+// chain of filters (profanity, regex blocklist, link count and spam score).
+package contentfilter
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type (
+	Action     string
+	FilterType string
+)
+
+const (
+	// ActionAllow filter hanya mencatat (log) pesan yang match, pesan tetap dikirim apa adanya
+	ActionAllow Action = "allow"
+	// ActionMask bagian pesan yang match diganti '*'
+	ActionMask Action = "mask"
+	// ActionReject pesan tidak dikirim & pengirim mendapat error
+	ActionReject Action = "reject"
+	// ActionFlag pesan tetap dikirim tetapi masuk ke moderation queue
+	ActionFlag Action = "flag"
+)
+
+const (
+	FilterTypeProfanity FilterType = "profanity"
+	FilterTypeRegex     FilterType = "regex"
+	FilterTypeLinkCount FilterType = "link_count"
+	FilterTypeSpamScore FilterType = "spam_score"
+)
+
+const (
+	defaultSpamThreshold = 0.7
+	maskChar             = '*'
+)
+
+var (
+	ErrInvalidFilter = errors.New("invalid content filter config")
+)
+
+// Config isi file config content filter, filter dijalankan berurutan sesuai urutan di file
+type Config struct {
+	Filters []FilterConfig `yaml:"filters"`
+}
+
+// FilterConfig config 1 filter, field yang dipakai tergantung Type
+type FilterConfig struct {
+	Name      string   `yaml:"name"`
+	Type      string   `yaml:"type"`
+	Action    string   `yaml:"action"`
+	Message   string   `yaml:"message"`   // pesan error ke pengirim jika action reject
+	Words     []string `yaml:"words"`     // profanity
+	Patterns  []string `yaml:"patterns"`  // regex
+	MaxLinks  int      `yaml:"max_links"` // link_count
+	Threshold float64  `yaml:"threshold"` // spam_score, 0-1
+}
+
+// Flag filter dengan action flag yang match
+type Flag struct {
+	Filter string
+	Reason string
+}
+
+// Result hasil menjalankan pesan ke semua filter
+type Result struct {
+	Content  string // content setelah di mask
+	Rejected bool
+	Reason   string // alasan reject, dikirim ke pengirim
+	Flags    []Flag
+}
+
+// filter 1 filter di pipeline, match mengembalikan range byte yang match (untuk mask) & alasan match
+type filter struct {
+	name    string
+	action  Action
+	message string
+	match   func(text string) ([][]int, string, bool)
+}
+
+// Pipeline chain filter yang bisa di reload ketika file config berubah
+type Pipeline struct {
+	path    string
+	mu      sync.RWMutex
+	filters []filter
+	modTime time.Time
+}
+
+// NewPipeline membuat pipeline dari file config di path.
+// Jika file tidak ada, pipeline kosong (semua pesan lolos) & file akan dibaca ketika sudah dibuat
+func NewPipeline(path string) (*Pipeline, error) {
+	p := &Pipeline{path: path}
+	if err := p.Reload(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload membaca ulang file config, filter lama tetap dipakai jika config baru tidak valid
+func (p *Pipeline) Reload() error {
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("contentfilter - Reload - os.Stat: %w", err)
+	}
+
+	var cfg Config
+	if err = cleanenv.ReadConfig(p.path, &cfg); err != nil {
+		return fmt.Errorf("contentfilter - Reload - cleanenv.ReadConfig: %w", err)
+	}
+	filters, err := build(cfg)
+	if err != nil {
+		return fmt.Errorf("contentfilter - Reload - build: %w", err)
+	}
+
+	p.mu.Lock()
+	p.filters = filters
+	p.modTime = info.ModTime()
+	p.mu.Unlock()
+	return nil
+}
+
+// Watch mengecek perubahan file config setiap interval & reload jika berubah.
+// Dijalankan di goroutine sendiri, interval <= 0 berarti hot reload dimatikan
+func (p *Pipeline) Watch(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		info, err := os.Stat(p.path)
+		if err != nil {
+			continue
+		}
+		p.mu.RLock()
+		changed := !info.ModTime().Equal(p.modTime)
+		p.mu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		if err = p.Reload(); err != nil {
+			log.Println("contentfilter - Watch - p.Reload: ", err)
+			// jangan coba reload file yang sama terus menerus
+			p.mu.Lock()
+			p.modTime = info.ModTime()
+			p.mu.Unlock()
+			continue
+		}
+		log.Println("contentfilter - Watch: content filter reloaded from ", p.path)
+	}
+}
+
+// Run menjalankan content ke semua filter secara berurutan.
+// Mask tidak mengubah panjang content dalam utf-16 code unit sehingga offset entity markdown tetap valid.
+// links (url entity text_link markdown) dinilai filter bersama content, url tidak bisa di mask
+// sehingga pesan di reject jika filter mask match di salah satu link
+func (p *Pipeline) Run(content string, links ...string) Result {
+	res := Result{Content: content}
+	if p == nil {
+		return res
+	}
+	p.mu.RLock()
+	filters := p.filters
+	p.mu.RUnlock()
+
+	for _, f := range filters {
+		text := res.Content
+		for _, link := range links {
+			// link yang labelnya url itu sendiri sudah dinilai di content
+			if !strings.Contains(res.Content, link) {
+				text += "\n" + link
+			}
+		}
+		ranges, reason, ok := f.match(text)
+		if !ok {
+			continue
+		}
+		action := f.action
+		if action == ActionMask && maskesLinks(ranges, len(res.Content)) {
+			action = ActionReject
+		}
+		switch action {
+		case ActionAllow:
+			log.Printf("contentfilter - Run: filter %s matched (allow): %s\n", f.name, reason)
+		case ActionMask:
+			res.Content = mask(res.Content, ranges)
+		case ActionReject:
+			res.Rejected = true
+			res.Reason = f.message
+			if res.Reason == "" {
+				res.Reason = "message rejected: " + reason
+			}
+			return res
+		case ActionFlag:
+			res.Flags = append(res.Flags, Flag{Filter: f.name, Reason: reason})
+		}
+	}
+	return res
+}
+
+// build validasi config & compile semua filter
+func build(cfg Config) ([]filter, error) {
+	var filters []filter
+	for i, fc := range cfg.Filters {
+		name := fc.Name
+		if name == "" {
+			name = fmt.Sprintf("%s#%d", fc.Type, i)
+		}
+		action := Action(strings.ToLower(fc.Action))
+		switch action {
+		case ActionAllow, ActionMask, ActionReject, ActionFlag:
+		default:
+			return nil, fmt.Errorf("%w: filter %s has unknown action %q", ErrInvalidFilter, name, fc.Action)
+		}
+
+		f := filter{name: name, action: action, message: fc.Message}
+		switch FilterType(fc.Type) {
+		case FilterTypeProfanity:
+			f.match = profanityMatcher(fc.Words)
+		case FilterTypeRegex:
+			matcher, err := regexMatcher(fc.Patterns)
+			if err != nil {
+				return nil, fmt.Errorf("%w: filter %s: %s", ErrInvalidFilter, name, err.Error())
+			}
+			f.match = matcher
+		case FilterTypeLinkCount:
+			f.match = linkCountMatcher(fc.MaxLinks)
+		case FilterTypeSpamScore:
+			threshold := fc.Threshold
+			if threshold <= 0 {
+				threshold = defaultSpamThreshold
+			}
+			f.match = spamScoreMatcher(threshold)
+		default:
+			return nil, fmt.Errorf("%w: filter %s has unknown type %q", ErrInvalidFilter, name, fc.Type)
+		}
+
+		// link_count & spam_score menilai pesan secara keseluruhan, tidak ada bagian yang bisa di mask
+		if action == ActionMask && (FilterType(fc.Type) == FilterTypeLinkCount || FilterType(fc.Type) == FilterTypeSpamScore) {
+			return nil, fmt.Errorf("%w: filter %s of type %s can not mask", ErrInvalidFilter, name, fc.Type)
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// maskesLinks cek apakah ada range match yang berada di luar content (di link)
+func maskesLinks(ranges [][]int, contentLen int) bool {
+	for _, r := range ranges {
+		if r[1] > contentLen {
+			return true
+		}
+	}
+	return false
+}
+
+// mask mengganti setiap rune di range byte dengan '*', rune di luar BMP diganti 2 '*'
+func mask(content string, ranges [][]int) string {
+	if len(ranges) == 0 {
+		return content
+	}
+	masked := make([]bool, len(content))
+	for _, r := range ranges {
+		for i := r[0]; i < r[1] && i < len(content); i++ {
+			masked[i] = true
+		}
+	}
+
+	var sb strings.Builder
+	for i, r := range content {
+		if !masked[i] {
+			sb.WriteRune(r)
+			continue
+		}
+		sb.WriteRune(maskChar)
+		if r >= 0x10000 {
+			sb.WriteRune(maskChar)
+		}
+	}
+	return sb.String()
+}
+
+// words memecah text menjadi kata (huruf & angka), mengembalikan range byte setiap kata
+func words(text string) [][]int {
+	var res [][]int
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && start < 0 {
+			start = i
+		}
+		if !isWord && start >= 0 {
+			res = append(res, []int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		res = append(res, []int{start, len(text)})
+	}
+	return res
+}
@@ -0,0 +1,219 @@
+package contentfilter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+const testConfig = `filters:
+  - name: profanity
+    type: profanity
+    action: mask
+    words: ["jelek", "anak nakal"]
+  - name: emoji
+    type: regex
+    action: mask
+    patterns: ["🍆"]
+  - name: shortener
+    type: regex
+    action: reject
+    message: link shortener is not allowed
+    patterns: ["(?i)bit\\.ly/"]
+  - name: links
+    type: link_count
+    action: reject
+    message: too many links
+    max_links: 2
+`
+
+// writeConfig menulis file config content filter di direktori sementara test
+func writeConfig(t *testing.T, path string, config string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+}
+
+func newTestPipeline(t *testing.T, config string) (*Pipeline, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "content_filter.yml")
+	writeConfig(t, path, config)
+	p, err := NewPipeline(path)
+	if err != nil {
+		t.Fatalf("NewPipeline: %v", err)
+	}
+	return p, path
+}
+
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+func TestPipelineRun(t *testing.T) {
+	p, _ := newTestPipeline(t, testConfig)
+
+	tests := []struct {
+		name     string
+		content  string
+		links    []string
+		want     string
+		rejected bool
+		reason   string
+	}{
+		{name: "pesan bersih", content: "halo semua", want: "halo semua"},
+		{name: "mask kata", content: "kamu jelek", want: "kamu *****"},
+		{name: "mask case insensitive", content: "JeLeK!", want: "*****!"},
+		{name: "bukan kata utuh", content: "jelekan", want: "jelekan"},
+		{name: "mask frasa", content: "dasar anak nakal!", want: "dasar **********!"},
+		{name: "mask frasa dipisah tanda baca", content: "anak-nakal", want: "**********"},
+		{name: "sebagian frasa tidak di mask", content: "anak baik", want: "anak baik"},
+		{name: "mask emoji 2 code unit", content: "a 🍆 b", want: "a ** b"},
+		{name: "mask setelah emoji", content: "😀 jelek", want: "😀 *****"},
+		{
+			name:     "reject regex di content",
+			content:  "cek bit.ly/abc",
+			rejected: true,
+			reason:   "link shortener is not allowed",
+		},
+		{
+			name:     "reject regex di url link markdown",
+			content:  "cek ini",
+			links:    []string{"https://bit.ly/abc"},
+			rejected: true,
+			reason:   "link shortener is not allowed",
+		},
+		{
+			name:     "link markdown dihitung link_count",
+			content:  "a b c",
+			links:    []string{"https://a.com", "https://b.com", "https://c.com"},
+			rejected: true,
+			reason:   "too many links",
+		},
+		{
+			name:    "link yang labelnya url tidak dihitung 2 kali",
+			content: "https://a.com https://b.com",
+			links:   []string{"https://a.com"},
+			want:    "https://a.com https://b.com",
+		},
+		{
+			name:     "mask match di url link markdown di reject",
+			content:  "lihat",
+			links:    []string{"https://example.com/jelek"},
+			rejected: true,
+			reason:   "message rejected: message contains blocked words",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := p.Run(tt.content, tt.links...)
+			if res.Rejected != tt.rejected {
+				t.Fatalf("Run(%q) rejected = %v, want %v", tt.content, res.Rejected, tt.rejected)
+			}
+			if tt.rejected {
+				if res.Reason != tt.reason {
+					t.Errorf("Run(%q) reason = %q, want %q", tt.content, res.Reason, tt.reason)
+				}
+				return
+			}
+			if res.Content != tt.want {
+				t.Errorf("Run(%q) content = %q, want %q", tt.content, res.Content, tt.want)
+			}
+			// offset entity markdown tetap valid jika panjang utf-16 tidak berubah
+			if utf16Len(res.Content) != utf16Len(tt.content) {
+				t.Errorf("Run(%q) utf-16 length = %d, want %d", tt.content, utf16Len(res.Content), utf16Len(tt.content))
+			}
+		})
+	}
+}
+
+func TestPipelineReload(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+		want    string
+	}{
+		{
+			name:   "config baru dipakai",
+			config: "filters:\n  - type: profanity\n    action: mask\n    words: [\"bodoh\"]\n",
+			want:   "jelek *****",
+		},
+		{
+			name:    "config tidak valid, filter lama tetap dipakai",
+			config:  "filters:\n  - type: profanity\n    action: hapus\n",
+			wantErr: true,
+			want:    "***** bodoh",
+		},
+		{
+			name:    "regex tidak valid, filter lama tetap dipakai",
+			config:  "filters:\n  - type: regex\n    action: reject\n    patterns: [\"(\"]\n",
+			wantErr: true,
+			want:    "***** bodoh",
+		},
+		{
+			name:    "link_count tidak bisa mask",
+			config:  "filters:\n  - type: link_count\n    action: mask\n",
+			wantErr: true,
+			want:    "***** bodoh",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, path := newTestPipeline(t, testConfig)
+			writeConfig(t, path, tt.config)
+
+			err := p.Reload()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := p.Run("jelek bodoh").Content; got != tt.want {
+				t.Errorf("Run() content = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPipelineMissingConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "content_filter.yml")
+	p, err := NewPipeline(path)
+	if err != nil {
+		t.Fatalf("NewPipeline: %v", err)
+	}
+	if got := p.Run("jelek").Content; got != "jelek" {
+		t.Errorf("Run() tanpa config = %q, want %q", got, "jelek")
+	}
+
+	writeConfig(t, path, testConfig)
+	if err = p.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := p.Run("jelek").Content; got != "*****" {
+		t.Errorf("Run() setelah config dibuat = %q, want %q", got, "*****")
+	}
+}
+
+func TestPipelineWatch(t *testing.T) {
+	p, path := newTestPipeline(t, testConfig)
+	go p.Watch(10 * time.Millisecond)
+
+	writeConfig(t, path, "filters: []\n")
+	// pastikan mod time berubah walaupun resolusi mod time filesystem kasar
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("os.Chtimes: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if p.Run("jelek").Content == "jelek" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Watch tidak reload config setelah file berubah")
+}
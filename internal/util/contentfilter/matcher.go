@@ -0,0 +1,155 @@
+package contentfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// linkRegex url di dalam pesan, dengan scheme http(s) atau diawali www.
+var linkRegex = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s]+`)
+
+// profanityMatcher match kata / frasa (case insensitive, kata utuh) yang ada di wordlist.
+// Entry dengan beberapa kata match jika kata-kata tsb berurutan di pesan, dipisah spasi / tanda baca apapun
+func profanityMatcher(wordlist []string) func(string) ([][]int, string, bool) {
+	// frasa dikelompokkan berdasarkan kata pertamanya
+	phrases := make(map[string][][]string, len(wordlist))
+	for _, w := range wordlist {
+		w = strings.ToLower(w)
+		var phrase []string
+		for _, r := range words(w) {
+			phrase = append(phrase, w[r[0]:r[1]])
+		}
+		if len(phrase) > 0 {
+			phrases[phrase[0]] = append(phrases[phrase[0]], phrase)
+		}
+	}
+
+	return func(text string) ([][]int, string, bool) {
+		ws := words(text)
+		tokens := make([]string, len(ws))
+		for i, w := range ws {
+			tokens[i] = strings.ToLower(text[w[0]:w[1]])
+		}
+
+		var ranges [][]int
+		for i := range tokens {
+			for _, phrase := range phrases[tokens[i]] {
+				if i+len(phrase) > len(tokens) || !equalTokens(tokens[i:i+len(phrase)], phrase) {
+					continue
+				}
+				ranges = append(ranges, []int{ws[i][0], ws[i+len(phrase)-1][1]})
+			}
+		}
+		return ranges, "message contains blocked words", len(ranges) > 0
+	}
+}
+
+func equalTokens(a []string, b []string) bool {
+	for i := range b {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// regexMatcher match semua pattern di blocklist
+func regexMatcher(patterns []string) (func(string) ([][]int, string, bool), error) {
+	var regexes []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		regexes = append(regexes, re)
+	}
+
+	return func(text string) ([][]int, string, bool) {
+		var ranges [][]int
+		for _, re := range regexes {
+			ranges = append(ranges, re.FindAllStringIndex(text, -1)...)
+		}
+		return ranges, "message contains blocked content", len(ranges) > 0
+	}, nil
+}
+
+// linkCountMatcher match jika jumlah link di pesan lebih dari maxLinks
+func linkCountMatcher(maxLinks int) func(string) ([][]int, string, bool) {
+	return func(text string) ([][]int, string, bool) {
+		links := linkRegex.FindAllStringIndex(text, -1)
+		if len(links) <= maxLinks {
+			return nil, "", false
+		}
+		return links, fmt.Sprintf("message contains more than %d links", maxLinks), true
+	}
+}
+
+// spamScoreMatcher match jika spamScore pesan >= threshold
+func spamScoreMatcher(threshold float64) func(string) ([][]int, string, bool) {
+	return func(text string) ([][]int, string, bool) {
+		score := spamScore(text)
+		if score < threshold {
+			return nil, "", false
+		}
+		return nil, fmt.Sprintf("message looks like spam (score %.2f)", score), true
+	}
+}
+
+// spamScore skor spam pesan antara 0-1, gabungan dari:
+// rasio huruf kapital, karakter yang diulang berturut-turut, kata yang diulang & kepadatan link
+func spamScore(text string) float64 {
+	var letters, upper, longestRun, run int
+	var prev rune
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+		if r == prev && !unicode.IsSpace(r) {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longestRun {
+			longestRun = run
+		}
+		prev = r
+	}
+
+	var capsScore float64
+	if letters >= 10 {
+		capsScore = float64(upper) / float64(letters)
+	}
+
+	// karakter yang sama >= 5 kali berturut-turut mulai dianggap spam, 15 kali skor penuh
+	runScore := clamp(float64(longestRun-4) / 10)
+
+	ws := words(text)
+	var repeatScore, linkScore float64
+	if len(ws) >= 5 {
+		unique := make(map[string]bool)
+		for _, w := range ws {
+			unique[strings.ToLower(text[w[0]:w[1]])] = true
+		}
+		repeatScore = 1 - float64(len(unique))/float64(len(ws))
+	}
+	if len(ws) > 0 {
+		linkScore = clamp(float64(len(linkRegex.FindAllStringIndex(text, -1))) * 5 / float64(len(ws)))
+	}
+
+	return 0.3*capsScore + 0.2*runScore + 0.3*repeatScore + 0.2*linkScore
+}
+
+func clamp(f float64) float64 {
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+	return f
+}
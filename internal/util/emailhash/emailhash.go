@@ -0,0 +1,62 @@
+// Package emailhash hash email address untuk contact discovery.
+// Client mengirim hex(sha256(salt + lower(trim(email)))) dengan salt global dari server,
+// server menyimpan & mencocokkan hmac-sha256 dari hash client dengan secret server (users.email_hash, di index)
+// sehingga hash di database tidak bisa dicocokkan tanpa secret.
+package emailhash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MinSecretLength panjang minimal secret hmac dalam byte
+const MinSecretLength = 32
+
+var (
+	ErrWeakSecret = errors.New("contact discovery secret is too short")
+)
+
+type Hasher struct {
+	salt   string
+	secret []byte
+	key    string
+}
+
+// NewHasher membuat hasher dengan salt (dibagikan ke client) & secret (hanya di server).
+// Secret kosong / lebih pendek dari MinSecretLength ditolak
+func NewHasher(salt string, secret string) (*Hasher, error) {
+	if len(strings.TrimSpace(secret)) < MinSecretLength {
+		return nil, fmt.Errorf("emailhash - NewHasher: %w (min %d bytes)", ErrWeakSecret, MinSecretLength)
+	}
+	h := &Hasher{salt: salt, secret: []byte(secret)}
+	// key berubah jika salt / secret diganti, hash user dengan key lama dihitung ulang
+	h.key = h.Index(salt)[:16]
+	return h, nil
+}
+
+// Salt salt global yang dipakai client untuk hash email
+func (h *Hasher) Salt() string {
+	return h.salt
+}
+
+// ClientHash hash email yang sama dengan yang dikirim client
+func (h *Hasher) ClientHash(email string) string {
+	sum := sha256.Sum256([]byte(h.salt + strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// Index hmac-sha256 dari hash client, nilai yang disimpan di users.email_hash
+func (h *Hasher) Index(clientHash string) string {
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(clientHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Key id salt & secret yang dipakai untuk menghitung users.email_hash
+func (h *Hasher) Key() string {
+	return h.key
+}
@@ -0,0 +1,298 @@
+// Package markdown parses the markdown subset allowed in chat messages into
+// plain text and formatting entities.
+package markdown
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"github.com/lintangbs/chat-be/internal/entity"
+)
+
+const (
+	// MaxEntities jumlah entity maksimal di dalam 1 pesan
+	MaxEntities = 100
+	// maxUrlLength panjang maksimal url di text_link
+	maxUrlLength = 2048
+)
+
+var (
+	ErrEmptyMessage    = errors.New("message is empty after formatting")
+	ErrTooManyEntities = errors.New("message has too many formatting entities")
+)
+
+// allowedSchemes scheme url yang boleh dipakai di link, selain itu link dibuang & hanya labelnya yang dipakai
+var allowedSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// Parse mengubah markdown menjadi plain text & entities.
+// Subset yang didukung: **bold**, *italic* / _italic_, `code`, ```pre```, [label](url) dan @mention.
+// Karakter markdown lain tetap ditulis apa adanya, html tidak pernah di-render karena hasilnya plain text.
+func Parse(text string) (string, []entity.MessageEntity, error) {
+	p := &parser{}
+	p.parse([]rune(sanitize(text)))
+
+	plain := strings.TrimSpace(string(p.out))
+	if plain == "" {
+		return "", nil, ErrEmptyMessage
+	}
+	if len(p.entities) > MaxEntities {
+		return "", nil, ErrTooManyEntities
+	}
+
+	// offset bergeser jika ada whitespace di awal teks yang di trim
+	leading := utf16Len([]rune(strings.TrimRightFunc(string(p.out), unicode.IsSpace))) - utf16Len([]rune(plain))
+	plainLen := utf16Len([]rune(plain))
+	var entities []entity.MessageEntity
+	for _, e := range p.entities {
+		e.Offset -= leading
+		if e.Offset < 0 {
+			e.Length += e.Offset
+			e.Offset = 0
+		}
+		if e.Offset+e.Length > plainLen {
+			e.Length = plainLen - e.Offset
+		}
+		if e.Length <= 0 {
+			continue
+		}
+		entities = append(entities, e)
+	}
+
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Offset == entities[j].Offset {
+			return entities[i].Length > entities[j].Length
+		}
+		return entities[i].Offset < entities[j].Offset
+	})
+
+	return plain, entities, nil
+}
+
+// sanitize membuang control character selain newline & tab
+func sanitize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+type parser struct {
+	out      []rune
+	offset   int // panjang out dalam utf-16 code unit
+	entities []entity.MessageEntity
+}
+
+func (p *parser) emit(rs ...rune) {
+	p.out = append(p.out, rs...)
+	p.offset += utf16Len(rs)
+}
+
+func (p *parser) add(t entity.MessageEntityType, start int) *entity.MessageEntity {
+	if p.offset == start {
+		return nil
+	}
+	p.entities = append(p.entities, entity.MessageEntity{Type: t, Offset: start, Length: p.offset - start})
+	return &p.entities[len(p.entities)-1]
+}
+
+func (p *parser) parse(rs []rune) {
+	for i := 0; i < len(rs); {
+		switch {
+		case rs[i] == '\\' && i+1 < len(rs) && isSpecial(rs[i+1]):
+			p.emit(rs[i+1])
+			i += 2
+
+		case hasPrefix(rs, i, "```"):
+			j := index(rs, i+3, "```")
+			if j < 0 {
+				p.emit(rs[i : i+3]...)
+				i += 3
+				continue
+			}
+			content := rs[i+3 : j]
+			// ```\ncode\n``` -> newline setelah pembuka tidak ikut ke dalam teks
+			if len(content) > 0 && content[0] == '\n' {
+				content = content[1:]
+			}
+			start := p.offset
+			p.emit(content...)
+			p.add(entity.MessageEntityPre, start)
+			i = j + 3
+
+		case rs[i] == '`':
+			j := index(rs, i+1, "`")
+			if j <= i+1 {
+				p.emit(rs[i])
+				i++
+				continue
+			}
+			start := p.offset
+			p.emit(rs[i+1 : j]...)
+			p.add(entity.MessageEntityCode, start)
+			i = j + 1
+
+		case hasPrefix(rs, i, "**"):
+			j := index(rs, i+2, "**")
+			if j <= i+2 {
+				p.emit(rs[i : i+2]...)
+				i += 2
+				continue
+			}
+			start := p.offset
+			p.parse(rs[i+2 : j])
+			p.add(entity.MessageEntityBold, start)
+			i = j + 2
+
+		case (rs[i] == '*' || rs[i] == '_') && (i == 0 || !isWordChar(rs[i-1])):
+			j := indexItalicEnd(rs, i+1, rs[i])
+			if j <= i+1 {
+				p.emit(rs[i])
+				i++
+				continue
+			}
+			start := p.offset
+			p.parse(rs[i+1 : j])
+			p.add(entity.MessageEntityItalic, start)
+			i = j + 1
+
+		case rs[i] == '[':
+			labelEnd := index(rs, i+1, "](")
+			if labelEnd <= i+1 {
+				p.emit(rs[i])
+				i++
+				continue
+			}
+			urlEnd := index(rs, labelEnd+2, ")")
+			if urlEnd < 0 {
+				p.emit(rs[i])
+				i++
+				continue
+			}
+			start := p.offset
+			p.emit(rs[i+1 : labelEnd]...)
+			if link, ok := safeUrl(string(rs[labelEnd+2 : urlEnd])); ok {
+				if e := p.add(entity.MessageEntityTextLink, start); e != nil {
+					e.Url = link
+				}
+			}
+			i = urlEnd + 1
+
+		case rs[i] == '@' && (i == 0 || !isWordChar(rs[i-1])):
+			j := i + 1
+			for j < len(rs) && isUsernameChar(rs[j]) {
+				j++
+			}
+			if j == i+1 {
+				p.emit(rs[i])
+				i++
+				continue
+			}
+			start := p.offset
+			p.emit(rs[i:j]...)
+			if e := p.add(entity.MessageEntityMention, start); e != nil {
+				e.Username = string(rs[i+1 : j])
+			}
+			i = j
+
+		default:
+			p.emit(rs[i])
+			i++
+		}
+	}
+}
+
+// safeUrl mengecek url link, hanya url absolut dg scheme yang diizinkan yang boleh dipakai
+func safeUrl(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxUrlLength {
+		return "", false
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !allowedSchemes[strings.ToLower(u.Scheme)] {
+		return "", false
+	}
+	if u.Scheme != "mailto" && u.Host == "" {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// indexItalicEnd mencari penutup italic, untuk '*' tidak boleh bagian dari "**"
+func indexItalicEnd(rs []rune, from int, marker rune) int {
+	for j := from; j < len(rs); j++ {
+		if rs[j] == '\\' {
+			j++
+			continue
+		}
+		if rs[j] != marker {
+			continue
+		}
+		if marker == '*' && j+1 < len(rs) && rs[j+1] == '*' {
+			j++
+			continue
+		}
+		if marker == '_' && j+1 < len(rs) && isWordChar(rs[j+1]) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+func hasPrefix(rs []rune, i int, prefix string) bool {
+	for _, r := range prefix {
+		if i >= len(rs) || rs[i] != r {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+func index(rs []rune, from int, sub string) int {
+	for j := from; j < len(rs); j++ {
+		if hasPrefix(rs, j, sub) {
+			return j
+		}
+	}
+	return -1
+}
+
+func isSpecial(r rune) bool {
+	return strings.ContainsRune("\\`*_[]()@", r)
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isUsernameChar username hanya alphanumeric (lihat validasi register)
+func isUsernameChar(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// utf16Len panjang rs dalam utf-16 code unit, rune di luar BMP (emoji) dihitung 2
+func utf16Len(rs []rune) int {
+	n := 0
+	for _, r := range rs {
+		if r1, _ := utf16.EncodeRune(r); r1 != unicode.ReplacementChar {
+			n += 2
+			continue
+		}
+		n++
+	}
+	return n
+}
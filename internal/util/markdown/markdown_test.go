@@ -0,0 +1,156 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lintangbs/chat-be/internal/entity"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		plain    string
+		entities []entity.MessageEntity
+	}{
+		{
+			name:  "bold & italic",
+			text:  "**halo** _dunia_",
+			plain: "halo dunia",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityBold, Offset: 0, Length: 4},
+				{Type: entity.MessageEntityItalic, Offset: 5, Length: 5},
+			},
+		},
+		{
+			name:  "offset utf-16 setelah emoji",
+			text:  "😀 **ok**",
+			plain: "😀 ok",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityBold, Offset: 3, Length: 2},
+			},
+		},
+		{
+			name:  "panjang utf-16 emoji di dalam entity",
+			text:  "`a😀b` c",
+			plain: "a😀b c",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityCode, Offset: 0, Length: 4},
+			},
+		},
+		{
+			name:  "whitespace di awal teks di trim",
+			text:  "  \n **bold** x",
+			plain: "bold x",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityBold, Offset: 0, Length: 4},
+			},
+		},
+		{
+			name:  "entity berisi whitespace di awal teks dipotong",
+			text:  "`  kode` x",
+			plain: "kode x",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityCode, Offset: 0, Length: 4},
+			},
+		},
+		{
+			name:  "pre block di awal teks",
+			text:  "```\n  kode\n``` x",
+			plain: "kode\n x",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityPre, Offset: 0, Length: 5},
+			},
+		},
+		{
+			name:  "link https",
+			text:  "[docs](https://example.com/a)",
+			plain: "docs",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityTextLink, Offset: 0, Length: 4, Url: "https://example.com/a"},
+			},
+		},
+		{
+			name:  "link mailto",
+			text:  "[email](mailto:a@example.com)",
+			plain: "email",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityTextLink, Offset: 0, Length: 5, Url: "mailto:a@example.com"},
+			},
+		},
+		{
+			name:  "link javascript dibuang",
+			text:  "[klik](javascript:alert(1))",
+			plain: "klik)",
+		},
+		{
+			name:  "link data dibuang",
+			text:  "[klik](data:text/html,x)",
+			plain: "klik",
+		},
+		{
+			name:  "link tanpa host dibuang",
+			text:  "[klik](https:///path)",
+			plain: "klik",
+		},
+		{
+			name:  "link relatif dibuang",
+			text:  "[klik](/v1/users)",
+			plain: "klik",
+		},
+		{
+			name:  "mention",
+			text:  "hai @budi!",
+			plain: "hai @budi!",
+			entities: []entity.MessageEntity{
+				{Type: entity.MessageEntityMention, Offset: 4, Length: 5, Username: "budi"},
+			},
+		},
+		{
+			name:  "escape karakter markdown",
+			text:  `\*bukan italic\*`,
+			plain: "*bukan italic*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, entities, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.text, err)
+			}
+			if plain != tt.plain {
+				t.Errorf("Parse(%q) plain = %q, want %q", tt.text, plain, tt.plain)
+			}
+			if !reflect.DeepEqual(entities, tt.entities) {
+				t.Errorf("Parse(%q) entities = %+v, want %+v", tt.text, entities, tt.entities)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  error
+	}{
+		{name: "kosong", text: "", err: ErrEmptyMessage},
+		{name: "hanya whitespace", text: " \n\t ", err: ErrEmptyMessage},
+		{name: "hanya markdown kosong", text: "****", err: nil},
+		{name: "hanya control character", text: "\x00\x07", err: ErrEmptyMessage},
+		{name: "terlalu banyak entity", text: strings.Repeat("**a** ", MaxEntities+1), err: ErrTooManyEntities},
+		{name: "entity maksimal", text: strings.Repeat("**a** ", MaxEntities), err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Parse(tt.text)
+			if err != tt.err {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.text, err, tt.err)
+			}
+		})
+	}
+}
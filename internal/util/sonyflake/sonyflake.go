@@ -18,7 +18,7 @@ func NewSonyFlake() *SonyFlake {
 func (sf *SonyFlake) GenerateId() (uint64, error) {
 	id, err := sf.sf.NextID()
 	if err != nil {
-		return 0, fmt.Errorf("SonyFlake - GenearateId - sf.NextId()", err)
+		return 0, fmt.Errorf("SonyFlake - GenearateId - sf.NextId(): %w", err)
 	}
 	return id, nil
 }
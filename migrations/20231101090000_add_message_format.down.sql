@@ -0,0 +1,5 @@
+ALTER TABLE private_chats DROP COLUMN IF EXISTS format;
+ALTER TABLE private_chats DROP COLUMN IF EXISTS entities;
+
+ALTER TABLE group_chats DROP COLUMN IF EXISTS format;
+ALTER TABLE group_chats DROP COLUMN IF EXISTS entities;
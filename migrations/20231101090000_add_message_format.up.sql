@@ -0,0 +1,5 @@
+ALTER TABLE private_chats ADD COLUMN format varchar NOT NULL DEFAULT 'plain';
+ALTER TABLE private_chats ADD COLUMN entities jsonb;
+
+ALTER TABLE group_chats ADD COLUMN format varchar NOT NULL DEFAULT 'plain';
+ALTER TABLE group_chats ADD COLUMN entities jsonb;
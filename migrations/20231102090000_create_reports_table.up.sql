@@ -0,0 +1,26 @@
+-- admin di set manual di database: UPDATE users SET is_admin = true WHERE username = '...';
+ALTER TABLE users ADD COLUMN is_admin boolean NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN suspended_at timestamptz;
+
+CREATE TABLE reports (
+                         id uuid PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4 (),
+                         reporter_id uuid NOT NULL,
+                         reported_user_id uuid NOT NULL,
+                         conversation_type varchar,
+                         message_id bigint,
+                         group_id uuid,
+                         reason text NOT NULL,
+                         status varchar NOT NULL DEFAULT 'open',
+                         resolved_by uuid,
+                         created_at timestamptz NOT NULL DEFAULT (now()),
+                         updated_at timestamptz NOT NULL DEFAULT (now()),
+                         deleted_at timestamptz
+);
+
+ALTER TABLE reports ADD CONSTRAINT fk_reports_users_reporter FOREIGN KEY (reporter_id)
+    REFERENCES users (id);
+
+ALTER TABLE reports ADD CONSTRAINT fk_reports_users_reported FOREIGN KEY (reported_user_id)
+    REFERENCES users (id);
+
+CREATE INDEX idx_reports_status ON reports (status, created_at);
@@ -0,0 +1,2 @@
+-- report yang dibuat otomatis oleh content filter tidak punya reporter
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
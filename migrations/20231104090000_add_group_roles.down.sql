@@ -0,0 +1,3 @@
+ALTER TABLE users_group DROP COLUMN IF EXISTS role;
+
+ALTER TABLE group_chats DROP COLUMN IF EXISTS pinned_at;
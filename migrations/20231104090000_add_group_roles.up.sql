@@ -0,0 +1,11 @@
+ALTER TABLE users_group ADD COLUMN role varchar NOT NULL DEFAULT 'member';
+
+-- group lama: member pertama (pembuat group) menjadi owner
+UPDATE users_group SET role = 'owner'
+WHERE id IN (
+    SELECT DISTINCT ON (group_id) id FROM users_group
+    WHERE deleted_at IS NULL
+    ORDER BY group_id, created_at
+);
+
+ALTER TABLE group_chats ADD COLUMN pinned_at timestamptz;
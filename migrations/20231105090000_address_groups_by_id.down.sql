@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_groups_name;
+DROP INDEX IF EXISTS idx_users_group_user_id;
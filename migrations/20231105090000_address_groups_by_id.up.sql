@@ -0,0 +1,5 @@
+-- group diidentifikasi dengan id, nama group boleh sama.
+-- selama masa transisi client lama masih bisa memakai nama group, lookup by name dibatasi ke group yang diikuti user
+-- (nama group sebelumnya unik, jadi lookup client lama tetap tidak ambigu sampai ada group baru dengan nama yang sama)
+CREATE INDEX IF NOT EXISTS idx_groups_name ON groups (name);
+CREATE INDEX IF NOT EXISTS idx_users_group_user_id ON users_group (user_id, group_id);
@@ -0,0 +1,22 @@
+CREATE TABLE group_invites (
+                               id uuid PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4 (),
+                               group_id uuid NOT NULL,
+                               token varchar NOT NULL UNIQUE,
+                               created_by uuid NOT NULL,
+                               expires_at timestamptz,
+                               max_uses int NOT NULL DEFAULT 0,
+                               uses int NOT NULL DEFAULT 0,
+                               approval_required boolean NOT NULL DEFAULT false,
+                               revoked_at timestamptz,
+                               created_at timestamptz NOT NULL DEFAULT (now()),
+                               updated_at timestamptz NOT NULL DEFAULT (now()),
+                               deleted_at timestamptz
+);
+
+ALTER TABLE group_invites ADD CONSTRAINT fk_group_invites_groups FOREIGN KEY (group_id)
+    REFERENCES groups (id);
+
+ALTER TABLE group_invites ADD CONSTRAINT fk_group_invites_users FOREIGN KEY (created_by)
+    REFERENCES users (id);
+
+CREATE INDEX idx_group_invites_group_id ON group_invites (group_id);
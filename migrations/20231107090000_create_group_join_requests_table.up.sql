@@ -0,0 +1,24 @@
+CREATE TABLE group_join_requests (
+                                     id uuid PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4 (),
+                                     group_id uuid NOT NULL,
+                                     user_id uuid NOT NULL,
+                                     invite_id uuid,
+                                     status varchar NOT NULL DEFAULT 'pending',
+                                     decided_by uuid,
+                                     decided_at timestamptz,
+                                     created_at timestamptz NOT NULL DEFAULT (now()),
+                                     updated_at timestamptz NOT NULL DEFAULT (now()),
+                                     deleted_at timestamptz
+);
+
+ALTER TABLE group_join_requests ADD CONSTRAINT fk_group_join_requests_groups FOREIGN KEY (group_id)
+    REFERENCES groups (id);
+
+ALTER TABLE group_join_requests ADD CONSTRAINT fk_group_join_requests_users FOREIGN KEY (user_id)
+    REFERENCES users (id);
+
+ALTER TABLE group_join_requests ADD CONSTRAINT fk_group_join_requests_group_invites FOREIGN KEY (invite_id)
+    REFERENCES group_invites (id);
+
+-- user hanya bisa punya 1 request pending per group
+CREATE UNIQUE INDEX idx_group_join_requests_pending ON group_join_requests (group_id, user_id) WHERE status = 'pending';
@@ -0,0 +1,4 @@
+ALTER TABLE group_chats DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE groups DROP COLUMN IF EXISTS avatar_url;
+ALTER TABLE groups DROP COLUMN IF EXISTS description;
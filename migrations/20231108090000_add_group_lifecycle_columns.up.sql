@@ -0,0 +1,5 @@
+ALTER TABLE groups ADD COLUMN description text NOT NULL DEFAULT '';
+ALTER TABLE groups ADD COLUMN avatar_url varchar NOT NULL DEFAULT '';
+
+-- message: pesan biasa dari member, selain itu system message (perubahan group / member)
+ALTER TABLE group_chats ADD COLUMN kind varchar NOT NULL DEFAULT 'message';
@@ -0,0 +1,49 @@
+-- channel broadcast, hanya owner & admin yang bisa posting, subscriber hanya menerima
+CREATE TABLE channels (
+                          id uuid PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4 (),
+                          name varchar(255) NOT NULL,
+                          description text NOT NULL DEFAULT '',
+                          owner_id uuid NOT NULL,
+                          subscriber_count bigint NOT NULL DEFAULT 0,
+                          created_at timestamptz NOT NULL DEFAULT (now()),
+                          updated_at timestamptz NOT NULL DEFAULT (now()),
+                          deleted_at timestamptz
+);
+
+ALTER TABLE channels ADD CONSTRAINT fk_channels_users FOREIGN KEY (owner_id)
+    REFERENCES users (id);
+
+CREATE TABLE channel_members (
+                                 id uuid PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4 (),
+                                 channel_id uuid NOT NULL,
+                                 user_id uuid NOT NULL,
+                                 role varchar NOT NULL DEFAULT 'subscriber',
+                                 created_at timestamptz NOT NULL DEFAULT (now()),
+                                 updated_at timestamptz NOT NULL DEFAULT (now()),
+                                 deleted_at timestamptz
+);
+
+ALTER TABLE channel_members ADD CONSTRAINT fk_channel_members_channels FOREIGN KEY (channel_id)
+    REFERENCES channels (id);
+
+ALTER TABLE channel_members ADD CONSTRAINT fk_channel_members_users FOREIGN KEY (user_id)
+    REFERENCES users (id);
+
+CREATE UNIQUE INDEX idx_channel_members_channel_user ON channel_members (channel_id, user_id);
+CREATE INDEX idx_channel_members_user_id ON channel_members (user_id);
+
+CREATE TABLE channel_messages (
+                                  channel_id uuid NOT NULL,
+                                  message_id bigint NOT NULL,
+                                  user_id uuid NOT NULL,
+                                  content text NOT NULL,
+                                  format varchar NOT NULL DEFAULT 'plain',
+                                  entities jsonb,
+                                  created_at timestamptz NOT NULL DEFAULT (now()),
+                                  updated_at timestamptz NOT NULL DEFAULT (now()),
+                                  deleted_at timestamptz,
+                                  PRIMARY KEY (channel_id, message_id)
+);
+
+ALTER TABLE channel_messages ADD CONSTRAINT fk_channel_messages_channels FOREIGN KEY (channel_id)
+    REFERENCES channels (id);
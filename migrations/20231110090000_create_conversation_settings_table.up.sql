@@ -0,0 +1,21 @@
+CREATE TABLE conversation_settings (
+                                       id uuid PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4 (),
+                                       user_id uuid NOT NULL,
+                                       conversation_type varchar NOT NULL,
+                                       conversation_id uuid NOT NULL,
+                                       muted boolean NOT NULL DEFAULT false,
+                                       muted_until timestamptz,
+                                       archived boolean NOT NULL DEFAULT false,
+                                       created_at timestamptz NOT NULL DEFAULT (now()),
+                                       updated_at timestamptz NOT NULL DEFAULT (now()),
+                                       deleted_at timestamptz
+);
+
+ALTER TABLE conversation_settings ADD CONSTRAINT fk_conversation_settings_users FOREIGN KEY (user_id)
+    REFERENCES users (id);
+
+-- 1 setting per user per percakapan (private: id friend, group: id group)
+CREATE UNIQUE INDEX idx_conversation_settings_conversation ON conversation_settings (user_id, conversation_type, conversation_id);
+
+-- mencari member group yang mute group ketika fanout pesan group
+CREATE INDEX idx_conversation_settings_muted ON conversation_settings (conversation_type, conversation_id) WHERE muted = true;
@@ -0,0 +1,21 @@
+CREATE TABLE friend_requests (
+                                 id uuid PRIMARY KEY NOT NULL DEFAULT uuid_generate_v4 (),
+                                 sender_id uuid NOT NULL,
+                                 recipient_id uuid NOT NULL,
+                                 status varchar NOT NULL DEFAULT 'pending',
+                                 decided_at timestamptz,
+                                 created_at timestamptz NOT NULL DEFAULT (now()),
+                                 updated_at timestamptz NOT NULL DEFAULT (now()),
+                                 deleted_at timestamptz
+);
+
+ALTER TABLE friend_requests ADD CONSTRAINT fk_friend_requests_users_sender FOREIGN KEY (sender_id)
+    REFERENCES users (id);
+
+ALTER TABLE friend_requests ADD CONSTRAINT fk_friend_requests_users_recipient FOREIGN KEY (recipient_id)
+    REFERENCES users (id);
+
+-- hanya boleh ada 1 request pending dari sender ke recipient
+CREATE UNIQUE INDEX idx_friend_requests_pending ON friend_requests (sender_id, recipient_id) WHERE status = 'pending';
+
+CREATE INDEX idx_friend_requests_recipient ON friend_requests (recipient_id, status);
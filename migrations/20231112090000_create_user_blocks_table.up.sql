@@ -0,0 +1,15 @@
+CREATE TABLE user_blocks (
+                             blocker_id uuid NOT NULL,
+                             blocked_id uuid NOT NULL,
+                             created_at timestamptz NOT NULL DEFAULT (now()),
+                             PRIMARY KEY (blocker_id, blocked_id)
+);
+
+ALTER TABLE user_blocks ADD CONSTRAINT fk_user_blocks_users_blocker FOREIGN KEY (blocker_id)
+    REFERENCES users (id);
+
+ALTER TABLE user_blocks ADD CONSTRAINT fk_user_blocks_users_blocked FOREIGN KEY (blocked_id)
+    REFERENCES users (id);
+
+-- mencari semua user yang memblokir user tertentu (fanout online status & group chat)
+CREATE INDEX idx_user_blocks_blocked ON user_blocks (blocked_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_contacts_active;
+
+ALTER TABLE contacts DROP COLUMN IF EXISTS note;
+ALTER TABLE contacts DROP COLUMN IF EXISTS nickname;
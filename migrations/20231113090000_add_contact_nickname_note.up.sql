@@ -0,0 +1,6 @@
+-- nickname & catatan pribadi user untuk kontaknya, hanya terlihat oleh user (user_id)
+ALTER TABLE contacts ADD COLUMN nickname varchar NOT NULL DEFAULT '';
+ALTER TABLE contacts ADD COLUMN note text NOT NULL DEFAULT '';
+
+-- kontak yang dihapus tetap disimpan dengan deleted_at terisi
+CREATE INDEX idx_contacts_active ON contacts (user_id) WHERE deleted_at IS NULL;
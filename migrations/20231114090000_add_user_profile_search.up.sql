@@ -0,0 +1,23 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- profil publik user
+ALTER TABLE users ADD COLUMN display_name varchar NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN avatar_url varchar NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN bio text NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN last_seen_at timestamptz;
+
+-- setting privacy user, user tanpa row memakai default (everyone)
+CREATE TABLE user_privacy_settings (
+                                       user_id uuid PRIMARY KEY NOT NULL,
+                                       discoverable varchar NOT NULL DEFAULT 'everyone',
+                                       last_seen varchar NOT NULL DEFAULT 'everyone',
+                                       created_at timestamptz NOT NULL DEFAULT (now()),
+                                       updated_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE user_privacy_settings ADD CONSTRAINT fk_user_privacy_settings_users FOREIGN KEY (user_id)
+    REFERENCES users (id);
+
+-- prefix (LIKE 'abc%') & trigram (%) search username & display name
+CREATE INDEX idx_users_username_trgm ON users USING gin (lower(username) gin_trgm_ops);
+CREATE INDEX idx_users_display_name_trgm ON users USING gin (lower(display_name) gin_trgm_ops);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS user_avatars;
+
+ALTER TABLE users DROP COLUMN IF EXISTS website;
+ALTER TABLE users DROP COLUMN IF EXISTS location;
+
+DROP INDEX IF EXISTS idx_users_username_unique;
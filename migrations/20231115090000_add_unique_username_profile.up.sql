@@ -0,0 +1,36 @@
+-- cek username duplikat (case insensitive) sebelum membuat unique index,
+-- migration gagal & menampilkan semua konflik agar bisa di rename manual terlebih dahulu
+DO $$
+DECLARE
+    conflicts text;
+BEGIN
+    SELECT string_agg(dup.lower_username || ': ' || dup.usernames, '; ')
+    INTO conflicts
+    FROM (SELECT lower(username) AS lower_username, string_agg(username || ' (' || id || ')', ', ') AS usernames
+          FROM users
+          WHERE deleted_at IS NULL
+          GROUP BY lower(username)
+          HAVING count(*) > 1) dup;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'duplicate usernames found, rename them before running this migration: %', conflicts;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX idx_users_username_unique ON users (lower(username)) WHERE deleted_at IS NULL;
+
+-- field profil tambahan
+ALTER TABLE users ADD COLUMN location varchar NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN website varchar NOT NULL DEFAULT '';
+
+-- avatar yang diupload user, disimpan di postgres agar bisa diakses dari chat-server manapun
+CREATE TABLE user_avatars (
+                              user_id uuid PRIMARY KEY NOT NULL,
+                              content_type varchar NOT NULL,
+                              data bytea NOT NULL,
+                              created_at timestamptz NOT NULL DEFAULT (now()),
+                              updated_at timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE user_avatars ADD CONSTRAINT fk_user_avatars_users FOREIGN KEY (user_id)
+    REFERENCES users (id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_privacy_exceptions;
+
+ALTER TABLE user_privacy_settings DROP COLUMN IF EXISTS online_status;
@@ -0,0 +1,19 @@
+-- siapa yang bisa melihat status online user
+ALTER TABLE user_privacy_settings ADD COLUMN online_status varchar NOT NULL DEFAULT 'everyone';
+
+-- pengecualian setting privacy (online_status / last_seen) per user,
+-- allow true selalu boleh melihat, false tidak pernah boleh melihat
+CREATE TABLE user_privacy_exceptions (
+                                         user_id uuid NOT NULL,
+                                         setting varchar NOT NULL,
+                                         target_id uuid NOT NULL,
+                                         allow boolean NOT NULL,
+                                         created_at timestamptz NOT NULL DEFAULT (now()),
+                                         PRIMARY KEY (user_id, setting, target_id)
+);
+
+ALTER TABLE user_privacy_exceptions ADD CONSTRAINT fk_user_privacy_exceptions_users FOREIGN KEY (user_id)
+    REFERENCES users (id);
+
+ALTER TABLE user_privacy_exceptions ADD CONSTRAINT fk_user_privacy_exceptions_users_target FOREIGN KEY (target_id)
+    REFERENCES users (id);
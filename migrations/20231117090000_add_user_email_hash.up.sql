@@ -0,0 +1,5 @@
+-- hmac dari hash email client (lihat internal/util/emailhash), diisi oleh server karena butuh secret.
+-- email_hash_key berbeda dari key server berarti hash belum ada / salt atau secret sudah diganti & dihitung ulang
+ALTER TABLE users ADD COLUMN email_hash varchar;
+ALTER TABLE users ADD COLUMN email_hash_key varchar;
+CREATE INDEX IF NOT EXISTS idx_users_email_hash ON users (email_hash) WHERE deleted_at IS NULL;
@@ -0,0 +1,12 @@
+-- member yang terduplikasi (approve join request bersamaan) di soft delete, yang paling lama dipertahankan
+UPDATE users_group SET deleted_at = now()
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, row_number() OVER (PARTITION BY group_id, user_id ORDER BY created_at, id) AS rn
+        FROM users_group
+        WHERE deleted_at IS NULL
+    ) duplicates
+    WHERE duplicates.rn > 1
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_group_member_unique ON users_group (group_id, user_id) WHERE deleted_at IS NULL;
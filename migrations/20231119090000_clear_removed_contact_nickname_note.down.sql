@@ -0,0 +1,2 @@
+-- nickname & catatan pribadi kontak yang sudah dihapus tidak bisa dikembalikan
+SELECT 1;
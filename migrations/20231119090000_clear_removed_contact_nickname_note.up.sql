@@ -0,0 +1,2 @@
+-- kontak yang sudah dihapus tidak menyimpan nickname & catatan pribadi lagi
+UPDATE contacts SET nickname = '', note = '' WHERE deleted_at IS NOT NULL;